@@ -10,6 +10,8 @@ var application = &subcommands.DefaultApplication{
 	Title: "Multi-protocol load testing tool",
 	Commands: []*subcommands.Command{
 		cmdInit,
+		cmdInjector,
+		cmdProfile,
 		subcommands.CmdHelp,
 		cmdVersion,
 	},
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/maruel/subcommands"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/telemetry"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var cmdInjector = &subcommands.Command{
+	UsageLine: "injector [-bind address] [-port port] [-labels key=value,...] [-collector-capacity samples] [-overflow-policy policy] [-aggregation-bucket duration] [-spool-dir path]",
+	ShortDesc: "starts an injector waiting for cockpit connections",
+	LongDesc: "Starts an injector that listens for control connections from a cockpit. " +
+		"Scripts, specs and commands are received through the control protocol",
+	CommandRun: func() subcommands.CommandRun {
+		run := &injectorRun{}
+		run.Flags.StringVar(&run.bindAddress, "bind", "0.0.0.0", "address the injector listens on")
+		run.Flags.UintVar(&run.port, "port", 3200, "port the injector listens on")
+		run.Flags.StringVar(&run.labels, "labels", "", "comma-separated labels used to place scenarios on the injector")
+		run.Flags.IntVar(&run.collectorCapacity, "collector-capacity", telemetry.DefaultCollectorCapacity, "samples buffered by each shooter between two telemetry flushes")
+		run.Flags.StringVar(&run.overflowPolicy, "overflow-policy", string(telemetry.DropOldest), "policy applied when a shooter buffer is full (block, drop_oldest, drop_and_count)")
+		run.Flags.DurationVar(&run.aggregationBucket, "aggregation-bucket", telemetry.DefaultBucketSize, "time span of the buckets the samples statistics are computed on")
+		run.Flags.StringVar(&run.spoolDirectory, "spool-dir", "", "directory keeping the telemetry not yet acknowledged by the cockpit (defaults to a temporary directory bound to the port)")
+		return run
+	},
+}
+
+type injectorRun struct {
+	subcommands.CommandRunBase
+	bindAddress string
+	port        uint
+	labels      string
+
+	collectorCapacity int
+	overflowPolicy    string
+	aggregationBucket time.Duration
+	spoolDirectory    string
+}
+
+func (ir *injectorRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	overflowPolicy := telemetry.OverflowPolicy(ir.overflowPolicy)
+	if !overflowPolicy.IsValid() {
+		fmt.Fprintf(a.GetErr(), "invalid overflow policy '%s'\n", ir.overflowPolicy)
+		return 1
+	}
+
+	settings := injector.Settings{
+		BindAddress:       ir.bindAddress,
+		Port:              ir.port,
+		CollectorCapacity: ir.collectorCapacity,
+		OverflowPolicy:    overflowPolicy,
+		AggregationBucket: ir.aggregationBucket,
+		SpoolDirectory:    ir.spoolDirectory,
+	}
+	for _, label := range strings.Split(ir.labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			settings.Labels = append(settings.Labels, label)
+		}
+	}
+
+	harkInjector := injector.New(ctx, os.Stdout, settings)
+	harkInjector.Start()
+	fmt.Printf("Injector %s listening on %s\n", harkInjector.ID, harkInjector.Address())
+
+	<-ctx.Done()
+	harkInjector.Stop()
+	return 0
+}
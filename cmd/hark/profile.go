@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/maruel/subcommands"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/preview"
+	"github.com/steromano87/harkonnen/project"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+var cmdProfile = &subcommands.Command{
+	UsageLine: "profile [-specs file] [-format ascii|csv|svg] [-output file] [-step duration]",
+	ShortDesc: "validates and previews the load profile of a project",
+	LongDesc: "Validates the load profiles declared in the specs file and renders the total amount of shooters " +
+		"over time, together with the split among the declared injectors, as an ASCII chart, a CSV table or an SVG image",
+	CommandRun: func() subcommands.CommandRun {
+		run := &profileRun{}
+		run.Flags.StringVar(&run.specsFile, "specs", project.Specs{}.File(), "specs file to read the load profiles from")
+		run.Flags.StringVar(&run.format, "format", "ascii", "output format, one of ascii, csv and svg")
+		run.Flags.StringVar(&run.output, "output", "", "file to write the preview to, standard output if empty")
+		run.Flags.DurationVar(&run.step, "step", 0, "sampling interval of the profile, by default the profile is split in 100 samples")
+		run.Flags.IntVar(&run.width, "width", 0, "chart width, in characters for ascii and in pixels for svg")
+		run.Flags.IntVar(&run.height, "height", 0, "chart height, in lines for ascii and in pixels for svg")
+		return run
+	},
+}
+
+type profileRun struct {
+	subcommands.CommandRunBase
+	specsFile string
+	format    string
+	output    string
+	step      time.Duration
+	width     int
+	height    int
+}
+
+func (pr *profileRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
+	if err := pr.render(); err != nil {
+		fmt.Fprintf(a.GetErr(), "%s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func (pr *profileRun) render() error {
+	content, err := ioutil.ReadFile(pr.specsFile)
+	if err != nil {
+		return err
+	}
+
+	var specs project.Specs
+	if err := yaml.Unmarshal(content, &specs); err != nil {
+		return err
+	}
+
+	if err := specs.Validate(); err != nil {
+		return err
+	}
+
+	harkCockpit := cockpit.New(context.Background(), injector.RemoteInjector)
+	harkCockpit.Injectors = cockpit.ReferencesFromSpecs(specs)
+	harkCockpit.Scenarios = cockpit.ScenariosFromSpecs(specs)
+	if err := harkCockpit.Validate(); err != nil {
+		return err
+	}
+
+	if pr.step < 0 {
+		return fmt.Errorf("invalid step '%s', it cannot be negative", pr.step)
+	}
+
+	// Only the default step has a floor, so that short profiles are not split in uselessly small samples
+	step := pr.step
+	if step == 0 {
+		step = harkCockpit.TotalDuration() / 100
+		if step < time.Second {
+			step = time.Second
+		}
+	}
+
+	var output io.Writer = os.Stdout
+	if pr.output != "" {
+		file, err := os.Create(pr.output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	schedule := preview.New(harkCockpit, step)
+	switch pr.format {
+	case "ascii":
+		return schedule.WriteASCII(output, withDefault(pr.width, 100), withDefault(pr.height, 20))
+	case "csv":
+		return schedule.WriteCSV(output)
+	case "svg":
+		return schedule.WriteSVG(output, withDefault(pr.width, 800), withDefault(pr.height, 400))
+	default:
+		return fmt.Errorf("unsupported format '%s', use one of ascii, csv and svg", pr.format)
+	}
+}
+
+func withDefault(value int, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
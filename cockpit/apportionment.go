@@ -0,0 +1,144 @@
+package cockpit
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"sort"
+)
+
+const DefaultHysteresis = 0.25
+
+// apportion splits the shooters among the injectors by weight with the largest remainder method,
+// honoring the per-injector caps. The extra shooters given by the remainders stay on the injectors that
+// already had them, unless another injector has a remainder larger by more than the hysteresis.
+func apportion(total int, injectors map[string]injector.Reference, previous map[string]int, hysteresis float64) map[string]int {
+	ids := make([]string, 0, len(injectors))
+	for id := range injectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	output := make(map[string]int, len(ids))
+	for _, id := range ids {
+		output[id] = 0
+	}
+
+	if total <= 0 || len(ids) == 0 {
+		return output
+	}
+
+	// Minimums cannot be honored while the load profile asks for less shooters than their sum (e.g. during ramp-up)
+	minimums := 0
+	for _, id := range ids {
+		minimums += injectors[id].MinShooters
+	}
+	honorMinimums := minimums <= total
+
+	lowerBound := func(id string) int {
+		if !honorMinimums {
+			return 0
+		}
+		return injectors[id].MinShooters
+	}
+	upperBound := func(id string) int {
+		if injectors[id].MaxShooters > 0 && injectors[id].MaxShooters < lowerBound(id) {
+			return lowerBound(id)
+		}
+		return injectors[id].MaxShooters
+	}
+
+	free := ids
+	remaining := total
+	for len(free) > 0 && remaining > 0 {
+		quotas := largestRemainder(remaining, free, injectors, previous, hysteresis)
+
+		var below, above []string
+		for _, id := range free {
+			if quotas[id] < lowerBound(id) {
+				below = append(below, id)
+			} else if upperBound(id) > 0 && quotas[id] > upperBound(id) {
+				above = append(above, id)
+			}
+		}
+
+		if len(below) == 0 && len(above) == 0 {
+			for _, id := range free {
+				output[id] = quotas[id]
+			}
+			break
+		}
+
+		// Capped injectors are fixed at their bound and the rest of the shooters is split again among the others
+		fixed := make(map[string]bool)
+		if len(below) > 0 {
+			for _, id := range below {
+				output[id] = lowerBound(id)
+				fixed[id] = true
+			}
+		} else {
+			for _, id := range above {
+				output[id] = upperBound(id)
+				fixed[id] = true
+			}
+		}
+
+		var stillFree []string
+		for _, id := range free {
+			if fixed[id] {
+				remaining -= output[id]
+			} else {
+				stillFree = append(stillFree, id)
+			}
+		}
+		free = stillFree
+	}
+
+	return output
+}
+
+func largestRemainder(total int, ids []string, injectors map[string]injector.Reference, previous map[string]int, hysteresis float64) map[string]int {
+	totalWeight := 0
+	for _, id := range ids {
+		totalWeight += effectiveWeight(injectors[id].Weight)
+	}
+
+	type candidate struct {
+		id       string
+		priority float64
+	}
+
+	output := make(map[string]int, len(ids))
+	candidates := make([]candidate, 0, len(ids))
+	assigned := 0
+	for _, id := range ids {
+		exact := float64(total) * float64(effectiveWeight(injectors[id].Weight)) / float64(totalWeight)
+		floor := int(exact)
+		output[id] = floor
+		assigned += floor
+
+		priority := exact - float64(floor)
+		if previous[id] > floor {
+			priority += hysteresis
+		}
+		candidates = append(candidates, candidate{id: id, priority: priority})
+	}
+
+	// Ties are broken by injector ID, so that the same inputs always give the same split
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].priority > candidates[b].priority
+	})
+
+	for index := 0; index < total-assigned; index++ {
+		output[candidates[index].id]++
+	}
+
+	return output
+}
+
+func effectiveWeight(weight int) int {
+	// Injectors without an explicit weight count as a single unit
+	if weight == 0 {
+		return 1
+	}
+
+	return weight
+}
@@ -2,9 +2,13 @@ package cockpit
 
 import (
 	"context"
+	"fmt"
+	"github.com/steromano87/harkonnen/control"
 	"github.com/steromano87/harkonnen/injector"
 	"github.com/steromano87/harkonnen/load"
-	"math"
+	"github.com/steromano87/harkonnen/telemetry"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -15,6 +19,50 @@ type Cockpit struct {
 	Injectors    map[string]injector.Reference
 
 	LoadProfiles []load.Profile
+	Scenarios    []Scenario
+
+	HeartbeatInterval   time.Duration
+	MaxMissedHeartbeats int
+	MinCapacity         float64
+
+	// Hysteresis is the fraction of a shooter another injector must exceed to take a rounded shooter away
+	Hysteresis float64
+
+	state *state
+}
+
+type state struct {
+	clock       *load.Clock
+	controllers map[string]Controller
+	remotes     map[string]*RemoteInjector
+	lost        map[string]time.Time
+	quotas      map[string]map[string]int
+	pushed      map[string]int
+	timeline    []TimelineEvent
+	mutex       sync.Mutex
+}
+
+func New(ctx context.Context, injectorType injector.Type) *Cockpit {
+	output := new(Cockpit)
+	output.Context = ctx
+	output.InjectorType = injectorType
+	output.Injectors = make(map[string]injector.Reference)
+	output.Hysteresis = DefaultHysteresis
+	output.state = newState()
+
+	return output
+}
+
+func newState() *state {
+	output := new(state)
+	output.clock = load.NewClock()
+	output.controllers = make(map[string]Controller)
+	output.remotes = make(map[string]*RemoteInjector)
+	output.lost = make(map[string]time.Time)
+	output.quotas = make(map[string]map[string]int)
+	output.pushed = make(map[string]int)
+
+	return output
 }
 
 func (c Cockpit) At(elapsed time.Duration) int {
@@ -23,6 +71,10 @@ func (c Cockpit) At(elapsed time.Duration) int {
 		totalInjectors += loadProfile.At(elapsed)
 	}
 
+	for _, scenario := range c.Scenarios {
+		totalInjectors += scenario.Profile.At(elapsed)
+	}
+
 	return totalInjectors
 }
 
@@ -34,29 +86,452 @@ func (c Cockpit) TotalDuration() time.Duration {
 		}
 	}
 
+	for _, scenario := range c.Scenarios {
+		if scenario.Profile.TotalDuration() > maxDuration {
+			maxDuration = scenario.Profile.TotalDuration()
+		}
+	}
+
 	return maxDuration
 }
 
+// Validate checks the injector caps, the load profiles and that every scenario with a selector can be placed on an injector
+func (c Cockpit) Validate() error {
+	ids := make([]string, 0, len(c.Injectors))
+	for id := range c.Injectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := validateReference(id, c.Injectors[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, loadProfile := range c.LoadProfiles {
+		if err := load.Validate(loadProfile); err != nil {
+			return err
+		}
+	}
+
+	for _, scenario := range c.Scenarios {
+		if err := load.Validate(scenario.Profile); err != nil {
+			return fmt.Errorf("scenario '%s': %w", scenario.Name, err)
+		}
+
+		// Without declared injectors the test runs locally, where selectors have nothing to choose from
+		if len(c.Injectors) == 0 || scenario.Selector.IsEmpty() {
+			continue
+		}
+
+		if len(scenario.matchingInjectors(c.Injectors)) == 0 {
+			return ErrUnsatisfiableSelector{Scenario: scenario.Name, Selector: scenario.Selector.String()}
+		}
+	}
+
+	return nil
+}
+
+func validateReference(id string, reference injector.Reference) error {
+	switch {
+	case reference.Weight < 0:
+		return ErrInvalidInjectorReference{Injector: id, Reason: fmt.Sprintf("weight cannot be negative, got %d", reference.Weight)}
+	case reference.MinShooters < 0:
+		return ErrInvalidInjectorReference{Injector: id, Reason: fmt.Sprintf("min_shooters cannot be negative, got %d", reference.MinShooters)}
+	case reference.MaxShooters < 0:
+		return ErrInvalidInjectorReference{Injector: id, Reason: fmt.Sprintf("max_shooters cannot be negative, got %d", reference.MaxShooters)}
+	case reference.MaxShooters > 0 && reference.MinShooters > reference.MaxShooters:
+		return ErrInvalidInjectorReference{
+			Injector: id,
+			Reason:   fmt.Sprintf("min_shooters (%d) cannot be greater than max_shooters (%d)", reference.MinShooters, reference.MaxShooters),
+		}
+	}
+
+	return nil
+}
+
 func (c Cockpit) AtForEachInjector(elapsed time.Duration) map[string]int {
-	totalShooters := c.At(elapsed)
+	injectors := c.aliveInjectors()
 
-	totalWeights := 0
-	for _, spec := range c.Injectors {
-		if spec.Weight == 0 {
-			totalWeights += 1
-		} else {
-			totalWeights += spec.Weight
+	output := make(map[string]int, len(injectors))
+	for id := range injectors {
+		output[id] = 0
+	}
+
+	totalShooters := 0
+	for _, loadProfile := range c.LoadProfiles {
+		totalShooters += closedLoopShooters(loadProfile, elapsed)
+	}
+	c.addQuotas(output, "", totalShooters, injectors)
+
+	// Scenarios with a selector only share their shooters among the matching injectors
+	for index, scenario := range c.Scenarios {
+		key := fmt.Sprintf("scenario-%d", index)
+		c.addQuotas(output, key, closedLoopShooters(scenario.Profile, elapsed), scenario.matchingInjectors(injectors))
+	}
+
+	return output
+}
+
+// addQuotas splits the shooters within what is left of the injector caps, so that the caps hold for the sum of all the quotas
+func (c Cockpit) addQuotas(assigned map[string]int, key string, totalShooters int, injectors map[string]injector.Reference) {
+	available := make(map[string]injector.Reference, len(injectors))
+	for id, reference := range injectors {
+		if reference.MaxShooters > 0 {
+			if assigned[id] >= reference.MaxShooters {
+				continue
+			}
+			reference.MaxShooters -= assigned[id]
+		}
+
+		reference.MinShooters -= assigned[id]
+		if reference.MinShooters < 0 {
+			reference.MinShooters = 0
 		}
+		available[id] = reference
+	}
+
+	for id, quota := range c.apportion(key, totalShooters, available) {
+		assigned[id] += quota
 	}
+}
+
+// RatesForEachInjector splits the rate and the pool of every arrival rate scenario among the matching injectors by weight.
+// Scenarios are identified by their position, which is the one of the ramps in the specs they come from.
+func (c Cockpit) RatesForEachInjector() map[string][]control.RateShare {
+	injectors := c.aliveInjectors()
+	output := make(map[string][]control.RateShare, len(injectors))
 
-	output := make(map[string]int)
+	for index, scenario := range c.Scenarios {
+		rateProfile, isRate := scenario.Profile.(load.RateProfile)
+		if !isRate {
+			continue
+		}
 
-	for id, spec := range c.Injectors {
-		shootersQuota := int(math.Round(float64(totalShooters*spec.Weight) / float64(totalWeights)))
+		matching := scenario.matchingInjectors(injectors)
+		ids := make([]string, 0, len(matching))
+		totalWeight := 0
+		for id, reference := range matching {
+			ids = append(ids, id)
+			totalWeight += effectiveWeight(reference.Weight)
+		}
+		sort.Strings(ids)
 
-		output[id] = shootersQuota
-		totalWeights -= spec.Weight
-		totalShooters -= shootersQuota
+		pools := largestRemainder(rateProfile.PoolSize(), ids, matching, nil, 0)
+		for _, id := range ids {
+			output[id] = append(output[id], control.RateShare{
+				Ramp:     index,
+				Fraction: float64(effectiveWeight(matching[id].Weight)) / float64(totalWeight),
+				PoolSize: pools[id],
+			})
+		}
 	}
+
 	return output
 }
+
+// closedLoopShooters leaves out the pools of the arrival rates, since they are allocated by the injectors executors
+func closedLoopShooters(profile load.Profile, elapsed time.Duration) int {
+	if _, isRate := profile.(load.RateProfile); isRate {
+		return 0
+	}
+
+	return profile.At(elapsed)
+}
+
+func (c Cockpit) apportion(key string, totalShooters int, injectors map[string]injector.Reference) map[string]int {
+	if c.state == nil {
+		return apportion(totalShooters, injectors, nil, c.Hysteresis)
+	}
+
+	c.state.mutex.Lock()
+	defer c.state.mutex.Unlock()
+
+	output := apportion(totalShooters, injectors, c.state.quotas[key], c.Hysteresis)
+	c.state.quotas[key] = output
+	return output
+}
+
+func (c Cockpit) aliveInjectors() map[string]injector.Reference {
+	if c.state == nil {
+		return c.Injectors
+	}
+
+	c.state.mutex.Lock()
+	defer c.state.mutex.Unlock()
+
+	if len(c.state.lost) == 0 {
+		return c.Injectors
+	}
+
+	output := make(map[string]injector.Reference, len(c.Injectors))
+	for id, reference := range c.Injectors {
+		if _, isLost := c.state.lost[id]; !isLost {
+			output[id] = reference
+		}
+	}
+
+	return output
+}
+
+func (c *Cockpit) Attach(id string, controller Controller) {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.controllers[id] = controller
+}
+
+// ConnectInjectors opens a control connection towards every remote injector and attaches it to the cockpit
+func (c *Cockpit) ConnectInjectors(timeout time.Duration) error {
+	for id, reference := range c.Injectors {
+		if reference.Type != injector.RemoteInjector {
+			continue
+		}
+
+		remote, err := Connect(reference, timeout)
+		if err != nil {
+			return fmt.Errorf("injector '%s': %w", id, err)
+		}
+
+		state := c.currentState()
+		state.mutex.Lock()
+		state.remotes[id] = remote
+		state.mutex.Unlock()
+		c.Attach(id, remote)
+	}
+
+	return nil
+}
+
+func (c *Cockpit) Remote(id string) (*RemoteInjector, bool) {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	remote, isPresent := state.remotes[id]
+	return remote, isPresent
+}
+
+// AssignPartitions gives every connected injector its own slice of the test-wide feeder rows
+func (c *Cockpit) AssignPartitions() error {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	ids := make([]string, 0, len(state.remotes))
+	for id := range state.remotes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for index, id := range ids {
+		if err := state.remotes[id].AssignPartition(index, len(ids)); err != nil {
+			return fmt.Errorf("injector '%s': %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// AssignRates gives every connected injector its share of the arrival rate scenarios
+func (c *Cockpit) AssignRates() error {
+	shares := c.RatesForEachInjector()
+
+	state := c.currentState()
+	state.mutex.Lock()
+	ids := make([]string, 0, len(state.remotes))
+	remotes := make(map[string]*RemoteInjector, len(state.remotes))
+	for id, remote := range state.remotes {
+		ids = append(ids, id)
+		remotes[id] = remote
+	}
+	state.mutex.Unlock()
+	sort.Strings(ids)
+
+	// Injectors without a share still get an empty one, which clears what a previous test assigned them
+	for _, id := range ids {
+		if err := remotes[id].AssignRates(shares[id]); err != nil {
+			return fmt.Errorf("injector '%s': %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// StartTest starts the test on every connected injector, each of them following only its own quota of the shooters.
+// Quotas change over time, so Monitor is expected to run for the whole test to keep them up to date.
+func (c *Cockpit) StartTest() error {
+	if err := c.AssignPartitions(); err != nil {
+		return err
+	}
+
+	if err := c.AssignRates(); err != nil {
+		return err
+	}
+
+	// Injectors would otherwise run the whole profiles on their own until the first check of the monitor
+	c.pushTargets(false)
+
+	state := c.currentState()
+	state.mutex.Lock()
+	ids := make([]string, 0, len(state.remotes))
+	remotes := make(map[string]*RemoteInjector, len(state.remotes))
+	for id, remote := range state.remotes {
+		ids = append(ids, id)
+		remotes[id] = remote
+	}
+	state.mutex.Unlock()
+	sort.Strings(ids)
+
+	for index, id := range ids {
+		if err := remotes[id].Start(); err != nil {
+			// A test is either started everywhere or nowhere
+			for _, started := range ids[:index] {
+				_ = remotes[started].Stop()
+			}
+			return fmt.Errorf("injector '%s': %w", id, err)
+		}
+	}
+
+	c.Start()
+	return nil
+}
+
+func (c *Cockpit) StreamTelemetry(handler TelemetryHandler) {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for _, remote := range state.remotes {
+		remote.StreamTelemetry(handler)
+	}
+}
+
+// Aggregates merges the statistics of all the connected injectors
+func (c *Cockpit) Aggregates(bucketSize time.Duration) (*telemetry.Aggregator, error) {
+	state := c.currentState()
+	state.mutex.Lock()
+	remotes := make(map[string]*RemoteInjector, len(state.remotes))
+	for id, remote := range state.remotes {
+		remotes[id] = remote
+	}
+	state.mutex.Unlock()
+
+	output := telemetry.NewAggregator(bucketSize)
+	for id, remote := range remotes {
+		entries, err := remote.Aggregates()
+		if err != nil {
+			return nil, fmt.Errorf("injector '%s': %w", id, err)
+		}
+		output.Import(entries...)
+	}
+
+	return output, nil
+}
+
+func (c *Cockpit) Disconnect() error {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	var firstErr error
+	for id, remote := range state.remotes {
+		if err := remote.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("injector '%s': %w", id, err)
+		}
+		delete(state.remotes, id)
+		delete(state.controllers, id)
+	}
+
+	return firstErr
+}
+
+func (c *Cockpit) Start() {
+	c.currentState().clock.Start()
+}
+
+func (c *Cockpit) Elapsed() time.Duration {
+	return c.currentState().clock.Elapsed()
+}
+
+func (c *Cockpit) IsPaused() bool {
+	return c.currentState().clock.IsPaused()
+}
+
+func (c *Cockpit) Pause() error {
+	state := c.currentState()
+	previous := state.clock.Snapshot()
+	if !state.clock.Pause() {
+		return ErrInvalidPauseState{Paused: true}
+	}
+
+	// A test that cannot be paused everywhere keeps on running, so the clock is rolled back as well
+	if err := c.forEachController(Controller.Pause, Controller.Resume); err != nil {
+		state.clock.Restore(previous)
+		return err
+	}
+
+	return nil
+}
+
+func (c *Cockpit) Resume() error {
+	state := c.currentState()
+	previous := state.clock.Snapshot()
+	if _, _, resumed := state.clock.Resume(); !resumed {
+		return ErrInvalidPauseState{Paused: false}
+	}
+
+	// The pause goes on as if the resume was never attempted, so its duration is not split in two
+	if err := c.forEachController(Controller.Resume, Controller.Pause); err != nil {
+		state.clock.Restore(previous)
+		return err
+	}
+
+	return nil
+}
+
+// forEachController applies an action to every injector, rolling it back on the succeeded ones if any of them fails
+func (c *Cockpit) forEachController(action func(Controller) error, rollback func(Controller) error) error {
+	state := c.currentState()
+	state.mutex.Lock()
+	ids := make([]string, 0, len(state.controllers))
+	controllers := make(map[string]Controller, len(state.controllers))
+	for id, controller := range state.controllers {
+		ids = append(ids, id)
+		controllers[id] = controller
+	}
+	state.mutex.Unlock()
+	sort.Strings(ids)
+
+	// Every injector is notified even if some of them fail, the first failure is reported
+	var firstErr error
+	var succeeded []string
+	for _, id := range ids {
+		if err := action(controllers[id]); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("injector '%s': %w", id, err)
+			}
+			continue
+		}
+		succeeded = append(succeeded, id)
+	}
+
+	if firstErr != nil {
+		for _, id := range succeeded {
+			_ = rollback(controllers[id])
+		}
+	}
+
+	return firstErr
+}
+
+func (c *Cockpit) currentState() *state {
+	// Cockpits created as plain literals get their state on first use
+	if c.state == nil {
+		c.state = newState()
+	}
+
+	return c.state
+}
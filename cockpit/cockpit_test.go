@@ -1,9 +1,12 @@
 package cockpit_test
 
 import (
+	"context"
+	"errors"
 	"github.com/steromano87/harkonnen/cockpit"
 	"github.com/steromano87/harkonnen/injector"
 	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/project"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"math"
@@ -167,6 +170,272 @@ func (suite CockpitTestSuite) TestAtForEachInjector_NonUniformWeightsWithRemaind
 	assert.Equal(suite.T(), 2, int(math.Round(float64(maxQuota)/float64(minQuota))))
 }
 
+func (suite CockpitTestSuite) TestAtForEachInjector_ZeroWeightCountsAsOne() {
+	unweighted := suite.injectorOdd
+	unweighted.Weight = 0
+
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"unweighted1": unweighted, "unweighted2": unweighted, "odd1": suite.injectorOdd},
+		LoadProfiles: []load.Profile{suite.loadRampEven},
+	}
+
+	assert.Equal(
+		suite.T(),
+		map[string]int{"unweighted1": 2, "unweighted2": 2, "odd1": 2},
+		cc.AtForEachInjector(suite.standardElapsed))
+}
+
+func (suite CockpitTestSuite) TestAtForEachInjector_DeterministicRemainders() {
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"c": suite.injectorEven, "a": suite.injectorEven, "b": suite.injectorEven},
+		LoadProfiles: []load.Profile{suite.loadRampOdd},
+	}
+
+	for attempt := 0; attempt < 20; attempt++ {
+		assert.Equal(suite.T(), map[string]int{"a": 2, "b": 2, "c": 1}, cc.AtForEachInjector(suite.standardElapsed))
+	}
+}
+
+func (suite CockpitTestSuite) TestAtForEachInjector_Caps() {
+	capped := suite.injectorOdd
+	capped.MaxShooters = 1
+	reserved := suite.injectorOdd
+	reserved.MinShooters = 4
+
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"capped": capped, "free1": suite.injectorOdd, "free2": suite.injectorOdd},
+		LoadProfiles: []load.Profile{suite.loadRampEven},
+	}
+	assert.Equal(suite.T(), map[string]int{"capped": 1, "free1": 3, "free2": 2}, cc.AtForEachInjector(suite.standardElapsed))
+
+	cc.Injectors = map[string]injector.Reference{"reserved": reserved, "free1": suite.injectorOdd, "free2": suite.injectorOdd}
+	assert.Equal(suite.T(), map[string]int{"reserved": 4, "free1": 1, "free2": 1}, cc.AtForEachInjector(suite.standardElapsed))
+
+	// Minimums are ignored while the profile asks for less shooters than their sum
+	cc.LoadProfiles = []load.Profile{suite.loadRampEven}
+	assert.Equal(suite.T(), map[string]int{"reserved": 1, "free1": 1, "free2": 1}, cc.AtForEachInjector(500*time.Millisecond))
+
+	// Shooters exceeding the sum of the maximums cannot be placed
+	cc.Injectors = map[string]injector.Reference{"capped1": capped, "capped2": capped}
+	assert.Equal(suite.T(), map[string]int{"capped1": 1, "capped2": 1}, cc.AtForEachInjector(suite.standardElapsed))
+}
+
+func (suite CockpitTestSuite) TestAtForEachInjector_CapsHoldAcrossScenarios() {
+	capped := suite.injectorOdd
+	capped.MaxShooters = 4
+	reserved := suite.injectorOdd
+	reserved.MinShooters = 5
+
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"capped": capped, "free": suite.injectorOdd},
+		LoadProfiles: []load.Profile{suite.loadRampEven},
+		Scenarios:    []cockpit.Scenario{{Name: "global users", Profile: suite.loadRampEven}},
+	}
+
+	// Each split alone gives 3 shooters to the capped injector, but the cap applies to their sum
+	assert.Equal(suite.T(), map[string]int{"capped": 4, "free": 8}, cc.AtForEachInjector(suite.standardElapsed))
+
+	// Minimums already reached by the first split are not reserved again by the next ones
+	cc.Injectors = map[string]injector.Reference{"reserved": reserved, "free": suite.injectorOdd}
+	assert.Equal(suite.T(), map[string]int{"reserved": 5 + 3, "free": 1 + 3}, cc.AtForEachInjector(suite.standardElapsed))
+}
+
+func (suite CockpitTestSuite) TestAtForEachInjector_Hysteresis() {
+	large := suite.injectorOdd
+	large.Weight = 6
+	small := suite.injectorOdd
+	small.Weight = 2
+	injectors := map[string]injector.Reference{"large1": large, "large2": large, "small": small}
+
+	ten, _ := load.ParseLinearRamp(10, "0s", "0s", "1m", "0s")
+	eleven, _ := load.ParseLinearRamp(11, "0s", "0s", "1m", "0s")
+
+	// Without hysteresis, raising the shooters from 10 to 11 takes a shooter away from the small injector
+	plain := cockpit.Cockpit{InjectorType: injector.LocalInjector, Injectors: injectors, LoadProfiles: []load.Profile{ten}}
+	assert.Equal(suite.T(), map[string]int{"large1": 4, "large2": 4, "small": 2}, plain.AtForEachInjector(time.Second))
+	plain.LoadProfiles = []load.Profile{eleven}
+	assert.Equal(suite.T(), map[string]int{"large1": 5, "large2": 5, "small": 1}, plain.AtForEachInjector(time.Second))
+
+	sticky := cockpit.New(context.Background(), injector.LocalInjector)
+	sticky.Injectors = injectors
+	sticky.LoadProfiles = []load.Profile{ten}
+	assert.Equal(suite.T(), map[string]int{"large1": 4, "large2": 4, "small": 2}, sticky.AtForEachInjector(time.Second))
+	sticky.LoadProfiles = []load.Profile{eleven}
+	assert.Equal(suite.T(), map[string]int{"large1": 5, "large2": 4, "small": 2}, sticky.AtForEachInjector(time.Second))
+}
+
+func (suite CockpitTestSuite) TestAtForEachInjector_ScenariosWithSelector() {
+	european := suite.injectorOdd
+	european.Labels = []string{"region=eu", "net=internal"}
+	american := suite.injectorOdd
+	american.Labels = []string{"region=us"}
+
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"eu1": european, "eu2": european, "us1": american},
+		Scenarios: []cockpit.Scenario{
+			{Name: "european users", Profile: suite.loadRampEven, Selector: project.LabelSelector{"region": "eu"}},
+			{Name: "global users", Profile: suite.loadRampEven},
+		},
+	}
+
+	assert.NoError(suite.T(), cc.Validate())
+	assert.Equal(suite.T(), 12, cc.At(suite.standardElapsed))
+	assert.Equal(suite.T(), suite.loadRampEven.TotalDuration(), cc.TotalDuration())
+	assert.Equal(
+		suite.T(),
+		map[string]int{"eu1": 3 + 2, "eu2": 3 + 2, "us1": 0 + 2},
+		cc.AtForEachInjector(suite.standardElapsed))
+}
+
+func (suite CockpitTestSuite) TestValidate_UnsatisfiableSelector() {
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"odd1": suite.injectorOdd},
+		Scenarios: []cockpit.Scenario{
+			{Name: "internal users", Profile: suite.loadRampEven, Selector: project.LabelSelector{"net": "internal"}},
+		},
+	}
+
+	assert.Equal(
+		suite.T(),
+		cockpit.ErrUnsatisfiableSelector{Scenario: "internal users", Selector: "net=internal"},
+		cc.Validate())
+}
+
+func (suite CockpitTestSuite) TestValidate_InvalidInjectorReference() {
+	negative := suite.injectorOdd
+	negative.Weight = -1
+	inverted := suite.injectorOdd
+	inverted.MinShooters = 5
+	inverted.MaxShooters = 2
+
+	cc := cockpit.Cockpit{
+		InjectorType: injector.LocalInjector,
+		Injectors:    map[string]injector.Reference{"negative": negative, "odd1": suite.injectorOdd},
+		LoadProfiles: []load.Profile{suite.loadRampEven},
+	}
+	assert.Equal(
+		suite.T(),
+		cockpit.ErrInvalidInjectorReference{Injector: "negative", Reason: "weight cannot be negative, got -1"},
+		cc.Validate())
+
+	cc.Injectors = map[string]injector.Reference{"inverted": inverted, "odd1": suite.injectorOdd}
+	assert.Equal(
+		suite.T(),
+		cockpit.ErrInvalidInjectorReference{Injector: "inverted", Reason: "min_shooters (5) cannot be greater than max_shooters (2)"},
+		cc.Validate())
+}
+
+func (suite CockpitTestSuite) TestScenariosFromSpecs() {
+	specs := project.Specs{Ramps: []project.Scenario{
+		{Name: "european users", Definition: load.Definition{Profile: suite.loadRampOdd}, Selector: project.LabelSelector{"region": "eu"}},
+	}}
+
+	scenarios := cockpit.ScenariosFromSpecs(specs)
+	if assert.Len(suite.T(), scenarios, 1) {
+		assert.Equal(suite.T(), "european users", scenarios[0].Name)
+		assert.Equal(suite.T(), suite.loadRampOdd, scenarios[0].Profile)
+		assert.Equal(suite.T(), project.LabelSelector{"region": "eu"}, scenarios[0].Selector)
+	}
+}
+
 func TestCockpitTestSuite(t *testing.T) {
 	suite.Run(t, new(CockpitTestSuite))
 }
+
+type fakeController struct {
+	paused bool
+	err    error
+	during func()
+}
+
+func (f *fakeController) Pause() error {
+	if f.during != nil {
+		f.during()
+	}
+	f.paused = true
+	return f.err
+}
+
+func (f *fakeController) Resume() error {
+	if f.during != nil {
+		f.during()
+	}
+	f.paused = false
+	return f.err
+}
+
+func TestCockpit_PauseAndResume(t *testing.T) {
+	cc := cockpit.New(context.Background(), injector.LocalInjector)
+	first := new(fakeController)
+	second := new(fakeController)
+	cc.Attach("first", first)
+	cc.Attach("second", second)
+
+	cc.Start()
+	assert.NoError(t, cc.Pause())
+	assert.True(t, cc.IsPaused())
+	assert.True(t, first.paused)
+	assert.True(t, second.paused)
+
+	frozen := cc.Elapsed()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, frozen, cc.Elapsed())
+	assert.Equal(t, cockpit.ErrInvalidPauseState{Paused: true}, cc.Pause())
+
+	assert.NoError(t, cc.Resume())
+	assert.False(t, first.paused)
+	assert.False(t, second.paused)
+	assert.Equal(t, cockpit.ErrInvalidPauseState{Paused: false}, cc.Resume())
+}
+
+func TestCockpit_PauseReportsControllerErrors(t *testing.T) {
+	cc := cockpit.Cockpit{InjectorType: injector.RemoteInjector}
+	healthy := new(fakeController)
+	cc.Attach("broken", &fakeController{err: errors.New("connection lost")})
+	cc.Attach("healthy", healthy)
+
+	cc.Start()
+	assert.EqualError(t, cc.Pause(), "injector 'broken': connection lost")
+	assert.False(t, cc.IsPaused(), "the cockpit clock is rolled back")
+	assert.False(t, healthy.paused, "healthy injectors are resumed back")
+}
+
+func TestCockpit_ResumeRollsBackOnControllerErrors(t *testing.T) {
+	cc := cockpit.Cockpit{InjectorType: injector.RemoteInjector}
+	broken := new(fakeController)
+	healthy := new(fakeController)
+	cc.Attach("broken", broken)
+	cc.Attach("healthy", healthy)
+
+	cc.Start()
+	assert.NoError(t, cc.Pause())
+	frozen := cc.Elapsed()
+	time.Sleep(10 * time.Millisecond)
+
+	broken.err = errors.New("connection lost")
+	assert.EqualError(t, cc.Resume(), "injector 'broken': connection lost")
+	assert.True(t, cc.IsPaused(), "the cockpit clock is rolled back")
+	assert.Equal(t, frozen, cc.Elapsed(), "the pause is not split by the failed resume")
+	assert.True(t, healthy.paused, "healthy injectors are paused back")
+}
+
+func TestCockpit_PauseDoesNotHoldStateDuringCalls(t *testing.T) {
+	cc := cockpit.New(context.Background(), injector.RemoteInjector)
+	calls := 0
+	cc.Attach("slow", &fakeController{during: func() {
+		// Calls to the injectors may take long, meanwhile the cockpit state must stay available
+		cc.LostInjectors()
+		calls++
+	}})
+
+	cc.Start()
+	assert.NoError(t, cc.Pause())
+	assert.NoError(t, cc.Resume())
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,6 @@
+package cockpit
+
+type Controller interface {
+	Pause() error
+	Resume() error
+}
@@ -0,0 +1,204 @@
+package cockpit_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/project"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type DistributionTestSuite struct {
+	suite.Suite
+	injectors  map[string]*injector.Injector
+	cockpit    *cockpit.Cockpit
+	usernames  map[string]int
+	iterations int
+	mutex      sync.Mutex
+}
+
+func (suite *DistributionTestSuite) SetupTest() {
+	// Shooters of the previous test may still be stopping, so counters are reset under the lock
+	suite.mutex.Lock()
+	suite.usernames = make(map[string]int)
+	suite.iterations = 0
+	suite.mutex.Unlock()
+	suite.cockpit = cockpit.New(context.Background(), injector.RemoteInjector)
+
+	suite.injectors = make(map[string]*injector.Injector)
+	for id, zone := range map[string]string{"first": "eu", "second": "us"} {
+		labels := []string{"role=load", "zone=" + zone}
+		instance := injector.New(context.Background(), ioutil.Discard, injector.Settings{
+			BindAddress:    "127.0.0.1",
+			Labels:         labels,
+			SpoolDirectory: suite.T().TempDir(),
+		})
+		instance.TickInterval = 10 * time.Millisecond
+		instance.ScriptLoader = suite.loadScript
+		instance.Start()
+		suite.injectors[id] = instance
+
+		_, rawPort, err := net.SplitHostPort(instance.Address())
+		suite.Require().NoError(err)
+		port, err := strconv.Atoi(rawPort)
+		suite.Require().NoError(err)
+
+		suite.cockpit.Injectors[id] = injector.Reference{
+			Address: "127.0.0.1",
+			Port:    uint16(port),
+			Weight:  1,
+			Labels:  labels,
+			Type:    injector.RemoteInjector,
+		}
+	}
+	suite.Require().NoError(suite.cockpit.ConnectInjectors(time.Second))
+}
+
+func (suite *DistributionTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.cockpit.Disconnect())
+	for _, instance := range suite.injectors {
+		instance.Stop()
+	}
+}
+
+// Compiling real plugins is too slow for unit tests, uploaded scripts are resolved in memory instead
+func (suite *DistributionTestSuite) loadScript(path string) (shooter.Script, error) {
+	return func(ctx shooter.Context) error {
+		suite.mutex.Lock()
+		suite.iterations++
+		// Tests without feeders only count the shooters
+		if username, err := ctx.VariablePool().GetString("username"); err == nil {
+			suite.usernames[username]++
+		}
+		suite.mutex.Unlock()
+		return ctx.Think(2 * time.Millisecond)
+	}, nil
+}
+
+func (suite *DistributionTestSuite) upload(specs string) {
+	var parsed project.Specs
+	suite.Require().NoError(yaml.Unmarshal([]byte(specs), &parsed))
+	suite.cockpit.Scenarios = cockpit.ScenariosFromSpecs(parsed)
+
+	for _, id := range []string{"first", "second"} {
+		remote, isPresent := suite.cockpit.Remote(id)
+		suite.Require().True(isPresent)
+		suite.Require().NoError(remote.UploadScript("browse", control.MainRole, []byte("compiled plugin")))
+		suite.Require().NoError(remote.UploadSpecs([]byte(specs)))
+	}
+}
+
+func (suite *DistributionTestSuite) startAll() {
+	for _, id := range []string{"first", "second"} {
+		remote, isPresent := suite.cockpit.Remote(id)
+		suite.Require().True(isPresent)
+		suite.Require().NoError(remote.Start())
+	}
+}
+
+func (suite *DistributionTestSuite) TestTestWideFeederIsPartitioned() {
+	suite.upload(`
+name: partitioned test
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+ramps:
+  - shooters: 1
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 1m
+    ramp_down_time: 0s
+feeders:
+  - name: users
+    type: inline
+    strategy: unique
+    sharing: test
+    rows:
+      - username: alice
+      - username: bob
+      - username: carol
+      - username: dave
+`)
+	suite.Require().NoError(suite.cockpit.AssignPartitions())
+	suite.startAll()
+
+	assert.Eventually(suite.T(), func() bool {
+		suite.mutex.Lock()
+		defer suite.mutex.Unlock()
+		return len(suite.usernames) == 4
+	}, time.Second, 5*time.Millisecond)
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	assert.Equal(suite.T(), map[string]int{"alice": 1, "bob": 1, "carol": 1, "dave": 1}, suite.usernames)
+}
+
+func (suite *DistributionTestSuite) TestShootersAreSplitAmongMatchingInjectors() {
+	suite.upload(`
+name: split test
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+ramps:
+  - name: browsing
+    selector: role=load
+    shooters: 4
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 1m
+    ramp_down_time: 0s
+`)
+	suite.Require().NoError(suite.cockpit.StartTest())
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.injectors["first"].ActiveShooters()+suite.injectors["second"].ActiveShooters() == 4
+	}, time.Second, 5*time.Millisecond)
+
+	// The whole profile is not run on every injector, not even for a moment
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(suite.T(), 2, suite.injectors["first"].ActiveShooters())
+	assert.Equal(suite.T(), 2, suite.injectors["second"].ActiveShooters())
+}
+
+func (suite *DistributionTestSuite) TestArrivalRateIsSplitAmongMatchingInjectors() {
+	suite.upload(`
+name: rate test
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+ramps:
+  - name: checkout
+    selector: role=load
+    type: arrival_rate
+    rate: 100
+    duration: 500ms
+    pre_allocated_shooters: 4
+`)
+	suite.Require().NoError(suite.cockpit.StartTest())
+
+	assert.Eventually(suite.T(), func() bool {
+		return !suite.injectors["first"].Status().Running && !suite.injectors["second"].Status().Running
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Every injector generates half of the rate with half of the pool, so together they match the profile
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	assert.InDelta(suite.T(), 50, suite.iterations, 10)
+}
+
+func TestDistributionTestSuite(t *testing.T) {
+	suite.Run(t, new(DistributionTestSuite))
+}
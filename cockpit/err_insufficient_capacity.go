@@ -0,0 +1,15 @@
+package cockpit
+
+import "fmt"
+
+type ErrInsufficientCapacity struct {
+	Remaining float64
+	Required  float64
+}
+
+func (ic ErrInsufficientCapacity) Error() string {
+	return fmt.Sprintf(
+		"remaining injector capacity (%.0f%%) is below the required minimum (%.0f%%)",
+		ic.Remaining*100,
+		ic.Required*100)
+}
@@ -0,0 +1,15 @@
+package cockpit_test
+
+import (
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInsufficientCapacity_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		cockpit.ErrInsufficientCapacity{Remaining: 0.5, Required: 0.75},
+		"remaining injector capacity (50%) is below the required minimum (75%)",
+		"Wrong error message format")
+}
@@ -0,0 +1,12 @@
+package cockpit
+
+import "fmt"
+
+type ErrInvalidInjectorReference struct {
+	Injector string
+	Reason   string
+}
+
+func (iir ErrInvalidInjectorReference) Error() string {
+	return fmt.Sprintf("invalid injector '%s': %s", iir.Injector, iir.Reason)
+}
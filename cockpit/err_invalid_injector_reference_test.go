@@ -0,0 +1,15 @@
+package cockpit_test
+
+import (
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidInjectorReference_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		cockpit.ErrInvalidInjectorReference{Injector: "eu1", Reason: "weight cannot be negative, got -1"},
+		"invalid injector 'eu1': weight cannot be negative, got -1",
+		"Wrong error message format")
+}
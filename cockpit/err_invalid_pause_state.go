@@ -0,0 +1,13 @@
+package cockpit
+
+type ErrInvalidPauseState struct {
+	Paused bool
+}
+
+func (ips ErrInvalidPauseState) Error() string {
+	if ips.Paused {
+		return "test is already paused"
+	}
+
+	return "test is not paused"
+}
@@ -0,0 +1,12 @@
+package cockpit_test
+
+import (
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidPauseState_Error(t *testing.T) {
+	assert.EqualError(t, cockpit.ErrInvalidPauseState{Paused: true}, "test is already paused", "Wrong error message format")
+	assert.EqualError(t, cockpit.ErrInvalidPauseState{Paused: false}, "test is not paused", "Wrong error message format")
+}
@@ -0,0 +1,12 @@
+package cockpit
+
+import "fmt"
+
+type ErrUnsatisfiableSelector struct {
+	Scenario string
+	Selector string
+}
+
+func (us ErrUnsatisfiableSelector) Error() string {
+	return fmt.Sprintf("no injector matches selector '%s' of scenario '%s'", us.Selector, us.Scenario)
+}
@@ -0,0 +1,15 @@
+package cockpit_test
+
+import (
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnsatisfiableSelector_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		cockpit.ErrUnsatisfiableSelector{Scenario: "checkout", Selector: "region=eu"},
+		"no injector matches selector 'region=eu' of scenario 'checkout'",
+		"Wrong error message format")
+}
@@ -0,0 +1,248 @@
+package cockpit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHeartbeatInterval   = time.Second
+	defaultMaxMissedHeartbeats = 3
+)
+
+// Monitor checks the remote injectors until the cockpit context is done, moving the load of lost injectors to the surviving ones
+func (c *Cockpit) Monitor() error {
+	interval := c.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+
+	maxMissed := c.MaxMissedHeartbeats
+	if maxMissed <= 0 {
+		maxMissed = defaultMaxMissedHeartbeats
+	}
+
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// The injectors follow the cockpit targets from the start, later targets are only pushed when they change
+	c.pushTargets(false)
+	missed := make(map[string]int)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		lost := c.checkHeartbeats(missed, maxMissed, interval)
+		for _, id := range lost {
+			c.markLost(id, missed[id])
+			delete(missed, id)
+		}
+
+		if len(lost) > 0 {
+			if err := c.checkCapacity(); err != nil {
+				c.abort(err)
+				return err
+			}
+		}
+
+		c.pushTargets(len(lost) > 0)
+	}
+}
+
+func (c *Cockpit) LostInjectors() []string {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	output := make([]string, 0, len(state.lost))
+	for id := range state.lost {
+		output = append(output, id)
+	}
+	sort.Strings(output)
+
+	return output
+}
+
+func (c *Cockpit) Timeline() []TimelineEvent {
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	output := make([]TimelineEvent, len(state.timeline))
+	copy(output, state.timeline)
+	return output
+}
+
+func (c *Cockpit) checkHeartbeats(missed map[string]int, maxMissed int, timeout time.Duration) []string {
+	state := c.currentState()
+	state.mutex.Lock()
+	remotes := make(map[string]*RemoteInjector, len(state.remotes))
+	for id, remote := range state.remotes {
+		remotes[id] = remote
+	}
+	state.mutex.Unlock()
+
+	// Heartbeats are sent in parallel, so that a hanging injector does not delay the checks of the others
+	failures := make(map[string]bool)
+	var failuresMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	for id, remote := range remotes {
+		waitGroup.Add(1)
+		go func(id string, remote *RemoteInjector) {
+			defer waitGroup.Done()
+
+			_, err := remote.HeartbeatWithin(timeout)
+
+			failuresMutex.Lock()
+			failures[id] = err != nil
+			failuresMutex.Unlock()
+		}(id, remote)
+	}
+	waitGroup.Wait()
+
+	var lost []string
+	for id, failed := range failures {
+		if !failed {
+			missed[id] = 0
+			continue
+		}
+
+		missed[id]++
+		if missed[id] >= maxMissed {
+			lost = append(lost, id)
+		}
+	}
+	sort.Strings(lost)
+
+	return lost
+}
+
+func (c *Cockpit) markLost(id string, missedHeartbeats int) {
+	state := c.currentState()
+	state.mutex.Lock()
+	remote := state.remotes[id]
+	delete(state.remotes, id)
+	delete(state.controllers, id)
+	state.lost[id] = time.Now()
+	state.mutex.Unlock()
+
+	if remote != nil {
+		_ = remote.Close()
+	}
+
+	c.recordEvent(InjectorLost, id, fmt.Sprintf("injector missed %d heartbeats", missedHeartbeats))
+}
+
+func (c *Cockpit) checkCapacity() error {
+	if c.MinCapacity <= 0 {
+		return nil
+	}
+
+	totalWeight := 0
+	for _, reference := range c.Injectors {
+		totalWeight += effectiveWeight(reference.Weight)
+	}
+
+	remainingWeight := 0
+	for _, reference := range c.aliveInjectors() {
+		remainingWeight += effectiveWeight(reference.Weight)
+	}
+
+	if totalWeight == 0 {
+		return nil
+	}
+
+	remaining := float64(remainingWeight) / float64(totalWeight)
+	if remaining < c.MinCapacity {
+		return ErrInsufficientCapacity{Remaining: remaining, Required: c.MinCapacity}
+	}
+
+	return nil
+}
+
+func (c *Cockpit) pushTargets(record bool) {
+	quotas := c.AtForEachInjector(c.Elapsed())
+
+	state := c.currentState()
+	state.mutex.Lock()
+	remotes := make(map[string]*RemoteInjector, len(state.remotes))
+	pushed := make(map[string]int, len(state.pushed))
+	for id, remote := range state.remotes {
+		remotes[id] = remote
+	}
+	for id, target := range state.pushed {
+		pushed[id] = target
+	}
+	state.mutex.Unlock()
+
+	ids := make([]string, 0, len(remotes))
+	for id := range remotes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Unreachable injectors are detected by the next heartbeats, so failed pushes are only retried at the next check
+	targets := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if target, isPushed := pushed[id]; !isPushed || target != quotas[id] {
+			if err := remotes[id].OverrideShooters(quotas[id]); err != nil {
+				delete(pushed, id)
+				continue
+			}
+			pushed[id] = quotas[id]
+		}
+		targets = append(targets, fmt.Sprintf("%s=%d", id, quotas[id]))
+	}
+
+	state.mutex.Lock()
+	state.pushed = pushed
+	state.mutex.Unlock()
+
+	if record {
+		c.recordEvent(LoadRebalanced, "", "new shooter targets: "+strings.Join(targets, ", "))
+	}
+}
+
+func (c *Cockpit) abort(err error) {
+	c.recordEvent(TestAborted, "", err.Error())
+
+	state := c.currentState()
+	state.mutex.Lock()
+	remotes := make([]*RemoteInjector, 0, len(state.remotes))
+	for _, remote := range state.remotes {
+		remotes = append(remotes, remote)
+	}
+	state.mutex.Unlock()
+
+	for _, remote := range remotes {
+		_ = remote.Stop()
+	}
+}
+
+func (c *Cockpit) recordEvent(eventType TimelineEventType, injectorID string, message string) {
+	event := TimelineEvent{
+		Type:       eventType,
+		InjectorID: injectorID,
+		At:         time.Now(),
+		Elapsed:    c.Elapsed(),
+		Message:    message,
+	}
+
+	state := c.currentState()
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.timeline = append(state.timeline, event)
+}
@@ -0,0 +1,171 @@
+package cockpit_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type FailoverTestSuite struct {
+	suite.Suite
+	injectors map[string]*injector.Injector
+	cockpit   *cockpit.Cockpit
+	cancel    context.CancelFunc
+	monitored chan error
+}
+
+func (suite *FailoverTestSuite) SetupTest() {
+	var ctx context.Context
+	ctx, suite.cancel = context.WithCancel(context.Background())
+	suite.cockpit = cockpit.New(ctx, injector.RemoteInjector)
+	suite.cockpit.HeartbeatInterval = 10 * time.Millisecond
+	suite.cockpit.MaxMissedHeartbeats = 2
+
+	sustain, err := load.ParseLinearRamp(4, "0s", "0s", "1m", "0s")
+	suite.Require().NoError(err)
+	suite.cockpit.LoadProfiles = []load.Profile{sustain}
+
+	suite.injectors = make(map[string]*injector.Injector)
+	for _, id := range []string{"first", "second"} {
+		instance := injector.New(context.Background(), ioutil.Discard, injector.Settings{
+			BindAddress:    "127.0.0.1",
+			SpoolDirectory: suite.T().TempDir(),
+		})
+		instance.Start()
+		suite.injectors[id] = instance
+
+		_, rawPort, err := net.SplitHostPort(instance.Address())
+		suite.Require().NoError(err)
+		port, err := strconv.Atoi(rawPort)
+		suite.Require().NoError(err)
+
+		suite.cockpit.Injectors[id] = injector.Reference{
+			Address: "127.0.0.1",
+			Port:    uint16(port),
+			Weight:  1,
+			Type:    injector.RemoteInjector,
+		}
+	}
+	suite.Require().NoError(suite.cockpit.ConnectInjectors(time.Second))
+	suite.cockpit.Start()
+}
+
+func (suite *FailoverTestSuite) TearDownTest() {
+	suite.cancel()
+	assert.NoError(suite.T(), suite.cockpit.Disconnect())
+	for _, instance := range suite.injectors {
+		instance.Stop()
+	}
+}
+
+func (suite *FailoverTestSuite) startMonitor() {
+	suite.monitored = make(chan error, 1)
+	go func() {
+		suite.monitored <- suite.cockpit.Monitor()
+	}()
+}
+
+func (suite *FailoverTestSuite) eventTypes() []cockpit.TimelineEventType {
+	var output []cockpit.TimelineEventType
+	for _, event := range suite.cockpit.Timeline() {
+		output = append(output, event.Type)
+	}
+
+	return output
+}
+
+func (suite *FailoverTestSuite) TestLoadIsMovedToSurvivingInjectors() {
+	assert.Equal(suite.T(), map[string]int{"first": 2, "second": 2}, suite.cockpit.AtForEachInjector(suite.cockpit.Elapsed()))
+
+	suite.startMonitor()
+	suite.injectors["second"].Stop()
+
+	assert.Eventually(suite.T(), func() bool {
+		return len(suite.cockpit.LostInjectors()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), []string{"second"}, suite.cockpit.LostInjectors())
+	assert.Equal(suite.T(), map[string]int{"first": 4}, suite.cockpit.AtForEachInjector(suite.cockpit.Elapsed()))
+
+	remote, isPresent := suite.cockpit.Remote("first")
+	suite.Require().True(isPresent)
+	assert.Eventually(suite.T(), func() bool {
+		status, err := remote.Status()
+		return err == nil && status.Override != nil && *status.Override == 4
+	}, time.Second, 5*time.Millisecond)
+
+	_, isPresent = suite.cockpit.Remote("second")
+	assert.False(suite.T(), isPresent)
+
+	timeline := suite.cockpit.Timeline()
+	suite.Require().Len(timeline, 2)
+	assert.Equal(suite.T(), cockpit.InjectorLost, timeline[0].Type)
+	assert.Equal(suite.T(), "second", timeline[0].InjectorID)
+	assert.Equal(suite.T(), cockpit.LoadRebalanced, timeline[1].Type)
+	assert.Contains(suite.T(), timeline[1].Message, "first=4")
+
+	suite.cancel()
+	assert.NoError(suite.T(), <-suite.monitored)
+}
+
+func (suite *FailoverTestSuite) TestTargetsArePushedFromTheStart() {
+	rampUp, err := load.ParseLinearRamp(4, "0s", "200ms", "1m", "0s")
+	suite.Require().NoError(err)
+	suite.cockpit.LoadProfiles = []load.Profile{rampUp}
+	suite.startMonitor()
+
+	for _, id := range []string{"first", "second"} {
+		remote, isPresent := suite.cockpit.Remote(id)
+		suite.Require().True(isPresent)
+
+		assert.Eventually(suite.T(), func() bool {
+			status, err := remote.Status()
+			return err == nil && status.Override != nil
+		}, time.Second, 5*time.Millisecond, "targets of injector '%s' not pushed at start", id)
+		assert.Eventually(suite.T(), func() bool {
+			status, err := remote.Status()
+			return err == nil && status.Override != nil && *status.Override == 2
+		}, time.Second, 5*time.Millisecond, "targets of injector '%s' not updated while ramping up", id)
+	}
+	assert.Empty(suite.T(), suite.eventTypes())
+
+	suite.cancel()
+	assert.NoError(suite.T(), <-suite.monitored)
+}
+
+func (suite *FailoverTestSuite) TestAbortOnInsufficientCapacity() {
+	suite.cockpit.MinCapacity = 0.75
+	suite.startMonitor()
+	suite.injectors["first"].Stop()
+
+	select {
+	case err := <-suite.monitored:
+		assert.Equal(suite.T(), cockpit.ErrInsufficientCapacity{Remaining: 0.5, Required: 0.75}, err)
+	case <-time.After(time.Second):
+		suite.Fail("monitor did not abort the test")
+	}
+
+	assert.Equal(suite.T(), []cockpit.TimelineEventType{cockpit.InjectorLost, cockpit.TestAborted}, suite.eventTypes())
+}
+
+func (suite *FailoverTestSuite) TestHealthyInjectorsAreKept() {
+	suite.startMonitor()
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Empty(suite.T(), suite.cockpit.LostInjectors())
+	assert.Empty(suite.T(), suite.eventTypes())
+
+	suite.cancel()
+	assert.NoError(suite.T(), <-suite.monitored)
+}
+
+func TestFailoverTestSuite(t *testing.T) {
+	suite.Run(t, new(FailoverTestSuite))
+}
@@ -0,0 +1,70 @@
+package cockpit
+
+import (
+	"fmt"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
+)
+
+// FeedbackHandler aggregates the streamed telemetry in the window and feeds it to the goal-seeking profiles,
+// pushing the new shooter targets to the injectors whenever the profiles change them
+func (c *Cockpit) FeedbackHandler(window *telemetry.Window, percentile float64) TelemetryHandler {
+	return func(injectorID string, records []telemetry.Record) {
+		window.Add(records...)
+
+		receivers := c.feedbackReceivers()
+		if len(receivers) == 0 {
+			return
+		}
+
+		elapsed := c.Elapsed()
+		summary := window.Summarize(time.Now(), percentile)
+		observation := load.Observation{
+			Elapsed:    elapsed,
+			Samples:    summary.Count,
+			Throughput: summary.Throughput,
+			Latency:    summary.Latency,
+			ErrorRate:  summary.ErrorRate,
+		}
+
+		before := c.At(elapsed)
+		for _, receiver := range receivers {
+			wasDone := isDone(receiver)
+			receiver.Observe(observation)
+			if wasDone || !isDone(receiver) {
+				continue
+			}
+
+			result, _ := receiver.Result()
+			c.recordEvent(FeedbackStopped, "", fmt.Sprintf(
+				"feedback profile stopped with a capacity of %d shooters: %s", result.Capacity, result.Reason))
+		}
+
+		if c.At(elapsed) != before {
+			c.pushTargets(false)
+		}
+	}
+}
+
+func isDone(receiver load.FeedbackReceiver) bool {
+	select {
+	case <-receiver.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func (c Cockpit) feedbackReceivers() []load.FeedbackReceiver {
+	var receivers []load.FeedbackReceiver
+	for _, loadProfile := range c.LoadProfiles {
+		receivers = append(receivers, load.FeedbackReceivers(loadProfile)...)
+	}
+
+	for _, scenario := range c.Scenarios {
+		receivers = append(receivers, load.FeedbackReceivers(scenario.Profile)...)
+	}
+
+	return receivers
+}
@@ -0,0 +1,74 @@
+package cockpit_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCockpit_FeedbackHandler(t *testing.T) {
+	feedback, _ := load.ParseFeedback(load.StepSearch, 5, 50, "10m")
+	feedback.TargetLatency = 500 * time.Millisecond
+	feedback.MaxLatency = 800 * time.Millisecond
+
+	harkCockpit := cockpit.New(context.Background(), injector.RemoteInjector)
+	harkCockpit.LoadProfiles = append(harkCockpit.LoadProfiles, feedback)
+	harkCockpit.Start()
+
+	handler := harkCockpit.FeedbackHandler(telemetry.NewWindow(time.Minute), 95)
+
+	now := time.Now()
+	fast := telemetry.NewBaseSample("GET /", now.Add(-100*time.Millisecond), now, 0, 0)
+	handler("injector-1", []telemetry.Record{telemetry.NewRecord("shooter-1", fast)})
+
+	_, stopped := feedback.Result()
+	assert.False(t, stopped)
+
+	slow := telemetry.NewBaseSample("GET /", now.Add(-2*time.Second), now, 0, 0)
+	handler("injector-1", []telemetry.Record{
+		telemetry.NewRecord("shooter-1", slow),
+		telemetry.NewRecord("shooter-2", slow),
+	})
+
+	result, stopped := feedback.Result()
+	if assert.True(t, stopped) {
+		assert.Equal(t, 5, result.Capacity)
+		assert.Equal(t, "latency 2s exceeded the limit of 800ms", result.Reason)
+	}
+	assert.Less(t, harkCockpit.TotalDuration(), time.Minute)
+
+	timeline := harkCockpit.Timeline()
+	if assert.Len(t, timeline, 1) {
+		assert.Equal(t, cockpit.FeedbackStopped, timeline[0].Type)
+		assert.Equal(t, "feedback profile stopped with a capacity of 5 shooters: latency 2s exceeded the limit of 800ms", timeline[0].Message)
+	}
+}
+
+func TestCockpit_FeedbackHandlerWithNestedProfiles(t *testing.T) {
+	feedback, _ := load.ParseFeedback(load.StepSearch, 5, 50, "10m")
+	feedback.TargetLatency = 500 * time.Millisecond
+	feedback.MaxLatency = 800 * time.Millisecond
+
+	harkCockpit := cockpit.New(context.Background(), injector.RemoteInjector)
+	harkCockpit.Scenarios = []cockpit.Scenario{{Name: "search", Profile: load.NewSum(load.NewScale(2, feedback))}}
+	harkCockpit.Start()
+
+	now := time.Now()
+	slow := telemetry.NewBaseSample("GET /", now.Add(-2*time.Second), now, 0, 0)
+	harkCockpit.FeedbackHandler(telemetry.NewWindow(time.Minute), 95)("injector-1", []telemetry.Record{
+		telemetry.NewRecord("shooter-1", slow),
+	})
+
+	_, stopped := feedback.Result()
+	assert.True(t, stopped)
+
+	timeline := harkCockpit.Timeline()
+	if assert.Len(t, timeline, 1) {
+		assert.Equal(t, cockpit.FeedbackStopped, timeline[0].Type)
+	}
+}
@@ -0,0 +1,108 @@
+package cockpit
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
+)
+
+type RemoteInjector struct {
+	Reference injector.Reference
+	client    *control.Client
+}
+
+func Connect(reference injector.Reference, timeout time.Duration) (*RemoteInjector, error) {
+	client, err := control.Dial(fmt.Sprintf("%s:%d", reference.Address, reference.Port), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	output := new(RemoteInjector)
+	output.Reference = reference
+	output.client = client
+
+	return output, nil
+}
+
+func (r *RemoteInjector) ID() string {
+	return r.client.InjectorID
+}
+
+func (r *RemoteInjector) Client() *control.Client {
+	return r.client
+}
+
+func (r *RemoteInjector) UploadScript(name string, role control.ScriptRole, content []byte) error {
+	return r.client.Call(control.UploadScript, control.ScriptPayload{Name: name, Role: role, Content: content}, nil)
+}
+
+func (r *RemoteInjector) UploadSpecs(content []byte) error {
+	return r.client.Call(control.UploadSpecs, control.SpecsPayload{Content: content}, nil)
+}
+
+func (r *RemoteInjector) Start() error {
+	return r.client.Call(control.StartTest, nil, nil)
+}
+
+func (r *RemoteInjector) Stop() error {
+	return r.client.Call(control.StopTest, nil, nil)
+}
+
+func (r *RemoteInjector) Pause() error {
+	return r.client.Call(control.PauseTest, nil, nil)
+}
+
+func (r *RemoteInjector) Resume() error {
+	return r.client.Call(control.ResumeTest, nil, nil)
+}
+
+func (r *RemoteInjector) OverrideShooters(shooters int) error {
+	return r.client.Call(control.OverrideShooter, control.OverridePayload{Shooters: &shooters}, nil)
+}
+
+func (r *RemoteInjector) AssignPartition(index int, count int) error {
+	return r.client.Call(control.AssignPartition, control.PartitionPayload{Index: index, Count: count}, nil)
+}
+
+func (r *RemoteInjector) AssignRates(shares []control.RateShare) error {
+	return r.client.Call(control.AssignRates, control.RatesPayload{Shares: shares}, nil)
+}
+
+func (r *RemoteInjector) ClearShooterOverride() error {
+	return r.client.Call(control.OverrideShooter, control.OverridePayload{}, nil)
+}
+
+func (r *RemoteInjector) Status() (control.StatusPayload, error) {
+	var status control.StatusPayload
+	err := r.client.Call(control.QueryStatus, nil, &status)
+	return status, err
+}
+
+func (r *RemoteInjector) Aggregates() ([]telemetry.AggregateEntry, error) {
+	var payload control.AggregatesPayload
+	if err := r.client.Call(control.QueryAggregates, nil, &payload); err != nil {
+		return nil, err
+	}
+
+	var entries []telemetry.AggregateEntry
+	if err := json.Unmarshal(payload.Data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *RemoteInjector) Heartbeat() (time.Duration, error) {
+	return r.client.Heartbeat()
+}
+
+func (r *RemoteInjector) HeartbeatWithin(timeout time.Duration) (time.Duration, error) {
+	return r.client.HeartbeatWithin(timeout)
+}
+
+func (r *RemoteInjector) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,229 @@
+package cockpit_test
+
+import (
+	"context"
+	"errors"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type RemoteInjectorTestSuite struct {
+	suite.Suite
+	injector   *injector.Injector
+	cockpit    *cockpit.Cockpit
+	iterations int64
+	failing    int32
+}
+
+func (suite *RemoteInjectorTestSuite) SetupTest() {
+	atomic.StoreInt64(&suite.iterations, 0)
+	atomic.StoreInt32(&suite.failing, 0)
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{
+		BindAddress:    "127.0.0.1",
+		SpoolDirectory: suite.T().TempDir(),
+	})
+	suite.injector.TickInterval = 10 * time.Millisecond
+
+	// Compiling real plugins is too slow for unit tests, uploaded scripts are resolved in memory instead
+	suite.injector.ScriptLoader = func(path string) (shooter.Script, error) {
+		if filepath.Base(path) == "prepare" {
+			return func(ctx shooter.Context) error {
+				return errors.New("database not ready")
+			}, nil
+		}
+
+		return func(ctx shooter.Context) error {
+			atomic.AddInt64(&suite.iterations, 1)
+			if atomic.LoadInt32(&suite.failing) == 1 {
+				return errors.New("checkout failed")
+			}
+			return ctx.Think(2 * time.Millisecond)
+		}, nil
+	}
+	suite.injector.Start()
+
+	_, rawPort, err := net.SplitHostPort(suite.injector.Address())
+	suite.Require().NoError(err)
+	port, err := strconv.Atoi(rawPort)
+	suite.Require().NoError(err)
+
+	suite.cockpit = cockpit.New(context.Background(), injector.RemoteInjector)
+	suite.cockpit.Injectors["remote"] = injector.Reference{
+		Address: "127.0.0.1",
+		Port:    uint16(port),
+		Type:    injector.RemoteInjector,
+	}
+	suite.Require().NoError(suite.cockpit.ConnectInjectors(time.Second))
+}
+
+func (suite *RemoteInjectorTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.cockpit.Disconnect())
+	suite.injector.Stop()
+}
+
+func (suite *RemoteInjectorTestSuite) remote() *cockpit.RemoteInjector {
+	remote, isPresent := suite.cockpit.Remote("remote")
+	suite.Require().True(isPresent)
+	return remote
+}
+
+func (suite *RemoteInjectorTestSuite) TestHandshake() {
+	assert.Equal(suite.T(), suite.injector.ID, suite.remote().ID())
+
+	roundTrip, err := suite.remote().Heartbeat()
+	assert.NoError(suite.T(), err)
+	assert.Greater(suite.T(), roundTrip, time.Duration(0))
+}
+
+func (suite *RemoteInjectorTestSuite) TestRunDrivenByCockpit() {
+	remote := suite.remote()
+
+	assert.NoError(suite.T(), remote.UploadScript("browse", control.MainRole, []byte("compiled plugin")))
+	assert.NoError(suite.T(), remote.UploadSpecs([]byte(`
+name: remote test
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+ramps:
+  - shooters: 2
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 1m
+    ramp_down_time: 0s
+`)))
+
+	assert.NoError(suite.T(), remote.Start())
+	assert.IsType(suite.T(), control.ErrRemoteFailure{}, remote.Start(), "test cannot be started twice")
+	assert.Eventually(suite.T(), func() bool {
+		status, err := remote.Status()
+		return err == nil && status.Running && status.ActiveShooters == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// Live override of the amount of shooters
+	assert.NoError(suite.T(), remote.OverrideShooters(4))
+	assert.Eventually(suite.T(), func() bool {
+		status, _ := remote.Status()
+		return status.ActiveShooters == 4 && status.Override != nil && *status.Override == 4
+	}, time.Second, 5*time.Millisecond)
+
+	assert.NoError(suite.T(), remote.ClearShooterOverride())
+	assert.Eventually(suite.T(), func() bool {
+		status, _ := remote.Status()
+		return status.ActiveShooters == 2 && status.Override == nil
+	}, time.Second, 5*time.Millisecond)
+
+	suite.cockpit.Start()
+	assert.NoError(suite.T(), suite.cockpit.Pause())
+	status, err := remote.Status()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), status.Paused)
+	assert.NoError(suite.T(), suite.cockpit.Resume())
+
+	assert.NoError(suite.T(), remote.Stop())
+	assert.Eventually(suite.T(), func() bool {
+		status, _ := remote.Status()
+		return !status.Running && status.ActiveShooters == 0
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Greater(suite.T(), atomic.LoadInt64(&suite.iterations), int64(0))
+}
+
+func (suite *RemoteInjectorTestSuite) TestAbortByErrorPolicy() {
+	atomic.StoreInt32(&suite.failing, 1)
+	remote := suite.remote()
+
+	assert.NoError(suite.T(), remote.UploadScript("checkout", control.MainRole, []byte("compiled plugin")))
+	assert.NoError(suite.T(), remote.UploadSpecs([]byte(`
+name: aborted test
+scripts:
+  main:
+    - name: checkout
+      path: scripts/checkout.go
+error_policy: abort_test
+ramps:
+  - shooters: 2
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 1m
+    ramp_down_time: 0s
+`)))
+
+	// Aborted runs do not prevent the injector from running the test again
+	for run := 0; run < 2; run++ {
+		assert.NoError(suite.T(), remote.Start())
+		assert.Eventually(suite.T(), func() bool {
+			status, err := remote.Status()
+			return err == nil && !status.Running && status.ActiveShooters == 0
+		}, 2*time.Second, 5*time.Millisecond)
+	}
+	assert.Greater(suite.T(), atomic.LoadInt64(&suite.iterations), int64(0))
+}
+
+func (suite *RemoteInjectorTestSuite) TestSetUpErrorPolicy() {
+	remote := suite.remote()
+
+	assert.NoError(suite.T(), remote.UploadScript("prepare", control.SetUpRole, []byte("compiled plugin")))
+	assert.NoError(suite.T(), remote.UploadScript("browse", control.MainRole, []byte("compiled plugin")))
+	assert.NoError(suite.T(), remote.UploadSpecs([]byte(`
+name: tolerant test
+scripts:
+  set_up:
+    name: prepare
+    path: scripts/prepare.go
+    error_policy: continue_iteration
+  main:
+    - name: browse
+      path: scripts/browse.go
+ramps:
+  - shooters: 1
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 1m
+    ramp_down_time: 0s
+`)))
+
+	// Setup failures would stop the shooters by default, the policy of the script lets them go on instead
+	assert.NoError(suite.T(), remote.Start())
+	assert.Eventually(suite.T(), func() bool {
+		return atomic.LoadInt64(&suite.iterations) > 0
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(suite.T(), remote.Stop())
+}
+
+func (suite *RemoteInjectorTestSuite) TestStartWithMissingScript() {
+	remote := suite.remote()
+
+	assert.NoError(suite.T(), remote.UploadSpecs([]byte(`
+name: remote test
+scripts:
+  main:
+    - name: checkout
+      path: scripts/checkout.go
+`)))
+
+	err := remote.Start()
+	assert.Equal(suite.T(), control.ErrRemoteFailure{
+		Type:    control.StartTest,
+		Message: injector.ErrMissingScript{Name: "checkout"}.Error(),
+	}, err)
+}
+
+func (suite *RemoteInjectorTestSuite) TestUploadWithInvalidRole() {
+	err := suite.remote().UploadScript("browse", control.ScriptRole("warm_up"), []byte("compiled plugin"))
+	assert.IsType(suite.T(), control.ErrRemoteFailure{}, err)
+}
+
+func TestRemoteInjectorTestSuite(t *testing.T) {
+	suite.Run(t, new(RemoteInjectorTestSuite))
+}
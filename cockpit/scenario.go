@@ -0,0 +1,56 @@
+package cockpit
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/project"
+)
+
+// Scenario is a load profile whose shooters are distributed only across the injectors matching its selector
+type Scenario struct {
+	Name     string
+	Profile  load.Profile
+	Selector project.LabelSelector
+}
+
+func ScenariosFromSpecs(specs project.Specs) []Scenario {
+	output := make([]Scenario, 0, len(specs.Ramps))
+	for _, ramp := range specs.Ramps {
+		output = append(output, Scenario{Name: ramp.Name, Profile: ramp.Profile, Selector: ramp.Selector})
+	}
+
+	return output
+}
+
+func (s Scenario) matchingInjectors(injectors map[string]injector.Reference) map[string]injector.Reference {
+	if s.Selector.IsEmpty() {
+		return injectors
+	}
+
+	output := make(map[string]injector.Reference)
+	for id, reference := range injectors {
+		if reference.Matches(s.Selector) {
+			output[id] = reference
+		}
+	}
+
+	return output
+}
+
+// ReferencesFromSpecs returns the remote injectors declared in the specs
+func ReferencesFromSpecs(specs project.Specs) map[string]injector.Reference {
+	output := make(map[string]injector.Reference, len(specs.Injectors))
+	for id, spec := range specs.Injectors {
+		output[id] = injector.Reference{
+			Address:     spec.Address,
+			Port:        spec.Port,
+			Weight:      spec.Weight,
+			Labels:      spec.Labels,
+			Type:        injector.RemoteInjector,
+			MinShooters: spec.MinShooters,
+			MaxShooters: spec.MaxShooters,
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,106 @@
+package cockpit
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/telemetry"
+	"sync"
+)
+
+type TelemetryHandler func(injectorID string, records []telemetry.Record)
+
+// receiveWindow is the amount of sequence numbers tracked beyond the last batch received without gaps
+const receiveWindow = 1024
+
+type telemetryReceiver struct {
+	handler  TelemetryHandler
+	received map[string]*receivedSequences
+	mutex    sync.Mutex
+}
+
+// receivedSequences keeps the highest sequence received without gaps, plus the ones received out of order after it
+type receivedSequences struct {
+	highWater uint64
+	window    map[uint64]struct{}
+}
+
+func newTelemetryReceiver(handler TelemetryHandler) *telemetryReceiver {
+	output := new(telemetryReceiver)
+	output.handler = handler
+	output.received = make(map[string]*receivedSequences)
+
+	return output
+}
+
+// StreamTelemetry delivers the samples pushed by the injector to the handler, acknowledging every batch
+func (r *RemoteInjector) StreamTelemetry(handler TelemetryHandler) {
+	receiver := newTelemetryReceiver(handler)
+	r.client.OnPush(func(envelope control.Envelope) {
+		if envelope.Type == control.TelemetryBatch {
+			receiver.receive(r.client, envelope)
+		}
+	})
+}
+
+func (receiver *telemetryReceiver) receive(client *control.Client, envelope control.Envelope) {
+	var payload control.TelemetryPayload
+	if err := envelope.Decode(&payload); err != nil {
+		return
+	}
+
+	batch, err := telemetry.DecodeBatch(payload.Data)
+	if err != nil {
+		// Corrupted batches are not acknowledged, so that the injector sends them again
+		return
+	}
+
+	// Batches are resent when acknowledgements get lost, duplicates must not be delivered twice
+	if receiver.markReceived(batch) {
+		receiver.handler(batch.Source, batch.Records)
+	}
+
+	// Acknowledgements do not wait for a reply, lost ones only make the injector send the batch again
+	_ = client.Send(control.TelemetryAck, control.TelemetryAckPayload{Sequence: payload.Sequence})
+}
+
+func (receiver *telemetryReceiver) markReceived(batch telemetry.Batch) bool {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	sequences, isPresent := receiver.received[batch.Source]
+	if !isPresent {
+		sequences = &receivedSequences{window: make(map[uint64]struct{})}
+		receiver.received[batch.Source] = sequences
+	}
+
+	return sequences.mark(batch.Sequence)
+}
+
+func (rs *receivedSequences) mark(sequence uint64) bool {
+	if sequence <= rs.highWater {
+		return false
+	}
+	if _, isDuplicate := rs.window[sequence]; isDuplicate {
+		return false
+	}
+	rs.window[sequence] = struct{}{}
+
+	// Gaps that are never filled (e.g. batches of a previous run) cannot make the window grow unbounded
+	if sequence-rs.highWater > receiveWindow {
+		rs.highWater = sequence - receiveWindow
+		for tracked := range rs.window {
+			if tracked <= rs.highWater {
+				delete(rs.window, tracked)
+			}
+		}
+	}
+
+	for {
+		if _, isReceived := rs.window[rs.highWater+1]; !isReceived {
+			break
+		}
+		rs.highWater++
+		delete(rs.window, rs.highWater)
+	}
+
+	return true
+}
@@ -0,0 +1,378 @@
+package cockpit_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type TelemetryReceiverTestSuite struct {
+	suite.Suite
+	spoolDirectory string
+	injector       *injector.Injector
+	cockpit        *cockpit.Cockpit
+	records        []telemetry.Record
+	sources        map[string]bool
+	generation     int
+	delay          time.Duration
+	mutex          sync.Mutex
+}
+
+func (suite *TelemetryReceiverTestSuite) SetupTest() {
+	var err error
+	suite.spoolDirectory, err = ioutil.TempDir("", "harkonnen-spool-test")
+	suite.Require().NoError(err)
+
+	// Handlers of the previous test may still be delivering the last records, which must be ignored
+	suite.mutex.Lock()
+	suite.generation++
+	suite.delay = 0
+	suite.records = nil
+	suite.sources = make(map[string]bool)
+	suite.mutex.Unlock()
+
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{
+		BindAddress:    "127.0.0.1",
+		FlushInterval:  time.Hour,
+		SpoolDirectory: suite.spoolDirectory,
+	})
+	suite.injector.Start()
+}
+
+func (suite *TelemetryReceiverTestSuite) TearDownTest() {
+	if suite.cockpit != nil {
+		assert.NoError(suite.T(), suite.cockpit.Disconnect())
+		suite.cockpit = nil
+	}
+	suite.injector.Stop()
+	assert.NoError(suite.T(), os.RemoveAll(suite.spoolDirectory))
+}
+
+func (suite *TelemetryReceiverTestSuite) connect() {
+	_, rawPort, err := net.SplitHostPort(suite.injector.Address())
+	suite.Require().NoError(err)
+	port, err := strconv.Atoi(rawPort)
+	suite.Require().NoError(err)
+
+	suite.cockpit = cockpit.New(context.Background(), injector.RemoteInjector)
+	suite.cockpit.Injectors["remote"] = injector.Reference{
+		Address: "127.0.0.1",
+		Port:    uint16(port),
+		Type:    injector.RemoteInjector,
+	}
+	suite.Require().NoError(suite.cockpit.ConnectInjectors(time.Second))
+	suite.mutex.Lock()
+	generation := suite.generation
+	delay := suite.delay
+	suite.mutex.Unlock()
+
+	suite.cockpit.StreamTelemetry(func(injectorID string, records []telemetry.Record) {
+		time.Sleep(delay)
+		suite.mutex.Lock()
+		defer suite.mutex.Unlock()
+
+		if generation != suite.generation {
+			return
+		}
+		suite.sources[injectorID] = true
+		suite.records = append(suite.records, records...)
+	})
+}
+
+func (suite *TelemetryReceiverTestSuite) collectSamples(amount int) {
+	now := time.Now()
+	for index := 0; index < amount; index++ {
+		suite.injector.SampleCollector().Collect(telemetry.NewMetricSample("queue_length", now, float64(index)))
+	}
+}
+
+func (suite *TelemetryReceiverTestSuite) receivedRecords() int {
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+
+	return len(suite.records)
+}
+
+func (suite *TelemetryReceiverTestSuite) spooledFiles() int {
+	entries, err := ioutil.ReadDir(suite.spoolDirectory)
+	suite.Require().NoError(err)
+	return len(entries)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestStreamingWithAcknowledgement() {
+	suite.connect()
+	suite.collectSamples(3)
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 3 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	assert.True(suite.T(), suite.sources[suite.injector.ID])
+	assert.Equal(suite.T(), telemetry.MetricRecord, suite.records[0].Kind)
+	assert.Equal(suite.T(), "queue_length", suite.records[0].Name)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestSpoolingWhileDisconnected() {
+	suite.collectSamples(2)
+	suite.injector.FlushTelemetry()
+	suite.collectSamples(1)
+	suite.injector.FlushTelemetry()
+
+	assert.Equal(suite.T(), 2, suite.spooledFiles())
+	assert.Equal(suite.T(), 2, suite.injector.PendingTelemetry())
+
+	// Spooled batches are replayed as soon as a cockpit is connected
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 3 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), 0, suite.spooledFiles())
+}
+
+func (suite *TelemetryReceiverTestSuite) TestLargeSpoolReplayKeepsHeartbeats() {
+	suite.injector.Stop()
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{
+		BindAddress:    "127.0.0.1",
+		FlushInterval:  time.Hour,
+		BatchSize:      1,
+		SpoolDirectory: suite.spoolDirectory,
+	})
+	suite.injector.Start()
+	suite.collectSamples(200)
+	suite.injector.FlushTelemetry()
+	suite.Require().Equal(200, suite.spooledFiles())
+
+	// Batches are handled slowly, so that most of them are still queued when the heartbeat is sent
+	suite.mutex.Lock()
+	suite.delay = 5 * time.Millisecond
+	suite.mutex.Unlock()
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	remote, found := suite.cockpit.Remote("remote")
+	suite.Require().True(found)
+	_, err := remote.HeartbeatWithin(500 * time.Millisecond)
+	assert.NoError(suite.T(), err)
+	assert.Less(suite.T(), suite.receivedRecords(), 200)
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 200 && suite.injector.PendingTelemetry() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestUnacknowledgedDataIsSpooledOnStop() {
+	suite.collectSamples(4)
+	suite.injector.Stop()
+
+	assert.Equal(suite.T(), 1, suite.spooledFiles())
+
+	// A new injector sharing the spool directory delivers the data left over by the previous one
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{
+		BindAddress:    "127.0.0.1",
+		FlushInterval:  time.Hour,
+		SpoolDirectory: suite.spoolDirectory,
+	})
+	suite.injector.Start()
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 4 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestDefaultSpoolSurvivesRestart() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	suite.Require().NoError(listener.Close())
+
+	// Without a spool directory, injectors listening on the same port share the same spool
+	settings := injector.Settings{BindAddress: "127.0.0.1", Port: uint(port), FlushInterval: time.Hour}
+	defer func() {
+		assert.NoError(suite.T(), os.RemoveAll(filepath.Join(os.TempDir(), fmt.Sprintf("harkonnen-spool-%d", port))))
+	}()
+
+	suite.injector.Stop()
+	suite.injector = injector.New(context.Background(), ioutil.Discard, settings)
+	suite.injector.Start()
+	suite.collectSamples(2)
+	suite.injector.Stop()
+
+	suite.injector = injector.New(context.Background(), ioutil.Discard, settings)
+	suite.injector.Start()
+	assert.Equal(suite.T(), 1, suite.injector.PendingTelemetry())
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 2 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestDroppedSamplesAreReported() {
+	suite.injector.Stop()
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{
+		BindAddress:       "127.0.0.1",
+		FlushInterval:     time.Hour,
+		SpoolDirectory:    suite.spoolDirectory,
+		CollectorCapacity: 2,
+		OverflowPolicy:    telemetry.DropAndCount,
+	})
+	suite.injector.Start()
+	suite.injector.TickInterval = 10 * time.Millisecond
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		for index := 0; index < 5; index++ {
+			ctx.SampleCollector().Collect(telemetry.NewMetricSample("queue_length", time.Now(), float64(index)))
+		}
+		return ctx.Think(time.Second)
+	}}
+	constant, _ := load.ParseConstant(1, "0s", "100ms")
+	suite.injector.AddLoadProfile(constant)
+	suite.Require().NoError(suite.injector.Run())
+
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() == 4 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	dropped := 0.0
+	for _, record := range suite.records {
+		if record.Name == injector.DroppedSamplesMetric {
+			dropped += record.Value
+		}
+	}
+	// The collector is emptied while the shooter thinks, so its shutdown event is not dropped
+	assert.Equal(suite.T(), 3.0, dropped)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestDroppedIterationsAreReported() {
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return ctx.Think(100 * time.Millisecond)
+	}}
+	arrivalRate, _ := load.ParseArrivalRate(50, "1s", "200ms", 1)
+	suite.injector.AddLoadProfile(arrivalRate)
+	suite.Require().NoError(suite.injector.Run())
+
+	suite.connect()
+	suite.injector.FlushTelemetry()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.receivedRecords() > 0 && suite.injector.PendingTelemetry() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	dropped := 0.0
+	for _, record := range suite.records {
+		if record.Name == injector.DroppedIterationsMetric {
+			dropped += record.Value
+		}
+	}
+	assert.Greater(suite.T(), dropped, 0.0)
+}
+
+func (suite *TelemetryReceiverTestSuite) TestAggregates() {
+	suite.connect()
+
+	end := time.Now()
+	for index := 1; index <= 4; index++ {
+		sample := telemetry.NewBaseSample("GET /", end.Add(-time.Duration(index)*100*time.Millisecond), end, 10, 100)
+		suite.injector.SampleCollector().Collect(sample)
+	}
+
+	aggregator, err := suite.cockpit.Aggregates(time.Minute)
+	if suite.NoError(err) {
+		overall := aggregator.Overall()[telemetry.AggregationKey{Name: "GET /"}]
+		assert.EqualValues(suite.T(), 4, overall.Count)
+		assert.EqualValues(suite.T(), 400, overall.ReceivedBytes)
+		assert.Equal(suite.T(), 100*time.Millisecond, overall.Min)
+		assert.Equal(suite.T(), 400*time.Millisecond, overall.Max)
+		assert.Equal(suite.T(), 250*time.Millisecond, overall.Mean)
+	}
+}
+
+func TestTelemetryReceiver_DeliversEveryBatchOnce(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	server := control.NewServer("fake", func(request control.Envelope) (interface{}, error) {
+		return nil, nil
+	}, zerolog.Nop())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		_ = server.Close()
+	}()
+
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+	remote, err := cockpit.Connect(injector.Reference{Address: "127.0.0.1", Port: port}, time.Second)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = remote.Close()
+	}()
+
+	var delivered []uint64
+	var mutex sync.Mutex
+	remote.StreamTelemetry(func(injectorID string, records []telemetry.Record) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		delivered = append(delivered, uint64(records[0].Value))
+	})
+
+	// Sequences far beyond the received ones move the window forward, the batches left behind are dropped
+	sequences := []uint64{1, 2, 2, 4, 3, 1, 2000, 5, 1999, 2000, 2001}
+	for _, sequence := range sequences {
+		batch := telemetry.Batch{Source: "fake", Sequence: sequence, Records: []telemetry.Record{
+			telemetry.NewRecord("fake", telemetry.NewMetricSample("queue_length", time.Now(), float64(sequence))),
+		}}
+		data, err := batch.Encode()
+		if assert.NoError(t, err) {
+			assert.NoError(t, server.Push(control.TelemetryBatch, control.TelemetryPayload{Sequence: sequence, Data: data}))
+		}
+	}
+
+	// Pushes are handled in order, so nothing else is delivered after the last batch
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(delivered) > 0 && delivered[len(delivered)-1] == 2001
+	}, time.Second, 5*time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []uint64{1, 2, 4, 3, 2000, 1999, 2001}, delivered)
+}
+
+func TestTelemetryReceiverTestSuite(t *testing.T) {
+	suite.Run(t, new(TelemetryReceiverTestSuite))
+}
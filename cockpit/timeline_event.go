@@ -0,0 +1,20 @@
+package cockpit
+
+import "time"
+
+type TimelineEventType string
+
+const (
+	InjectorLost    TimelineEventType = "injector_lost"
+	LoadRebalanced  TimelineEventType = "load_rebalanced"
+	TestAborted     TimelineEventType = "test_aborted"
+	FeedbackStopped TimelineEventType = "feedback_stopped"
+)
+
+type TimelineEvent struct {
+	Type       TimelineEventType
+	InjectorID string
+	At         time.Time
+	Elapsed    time.Duration
+	Message    string
+}
@@ -0,0 +1,231 @@
+package control
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultCallTimeout = 30 * time.Second
+
+type Client struct {
+	InjectorID  string
+	CallTimeout time.Duration
+
+	conn         net.Conn
+	nextID       uint64
+	pending      map[uint64]chan Envelope
+	pushHandler  func(envelope Envelope)
+	pushes       []Envelope
+	pushed       chan struct{}
+	closed       chan struct{}
+	closeErr     error
+	writeMutex   sync.Mutex
+	pendingMutex sync.Mutex
+	pushesMutex  sync.Mutex
+	closeOnce    sync.Once
+}
+
+func Dial(address string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn)
+}
+
+// NewClient takes ownership of the connection and performs the protocol handshake
+func NewClient(conn net.Conn) (*Client, error) {
+	output := new(Client)
+	output.CallTimeout = DefaultCallTimeout
+	output.conn = conn
+	output.pending = make(map[uint64]chan Envelope)
+	output.closed = make(chan struct{})
+	output.pushed = make(chan struct{}, 1)
+
+	go output.readLoop()
+	go output.dispatchPushes()
+
+	var welcome WelcomePayload
+	err := output.Call(Hello, HelloPayload{Version: ProtocolVersion, Client: "cockpit"}, &welcome)
+	if err == nil && welcome.Version != ProtocolVersion {
+		err = ErrUnsupportedVersion{Local: ProtocolVersion, Remote: welcome.Version}
+	}
+
+	if err != nil {
+		_ = output.Close()
+		return nil, err
+	}
+
+	output.InjectorID = welcome.InjectorID
+	return output, nil
+}
+
+func (c *Client) Call(messageType MessageType, payload interface{}, reply interface{}) error {
+	return c.callWithin(c.CallTimeout, messageType, payload, reply)
+}
+
+func (c *Client) callWithin(timeout time.Duration, messageType MessageType, payload interface{}, reply interface{}) error {
+	request, err := NewEnvelope(messageType, atomic.AddUint64(&c.nextID, 1), payload)
+	if err != nil {
+		return err
+	}
+
+	responses := make(chan Envelope, 1)
+	c.pendingMutex.Lock()
+	c.pending[request.ID] = responses
+	c.pendingMutex.Unlock()
+
+	defer func() {
+		c.pendingMutex.Lock()
+		delete(c.pending, request.ID)
+		c.pendingMutex.Unlock()
+	}()
+
+	if err := c.write(request); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case response := <-responses:
+		if response.Type == Failure {
+			var failure FailurePayload
+			_ = response.Decode(&failure)
+			return ErrRemoteFailure{Type: messageType, Message: failure.Message}
+		}
+
+		return response.Decode(reply)
+
+	case <-c.closed:
+		return c.closeErr
+
+	case <-timer.C:
+		return ErrCallTimeout{Type: messageType, Timeout: timeout}
+	}
+}
+
+// Send writes a request without waiting for its reply, which is discarded once received
+func (c *Client) Send(messageType MessageType, payload interface{}) error {
+	request, err := NewEnvelope(messageType, atomic.AddUint64(&c.nextID, 1), payload)
+	if err != nil {
+		return err
+	}
+
+	return c.write(request)
+}
+
+// Heartbeat checks that the injector is alive and returns the round-trip time
+func (c *Client) Heartbeat() (time.Duration, error) {
+	return c.HeartbeatWithin(c.CallTimeout)
+}
+
+func (c *Client) HeartbeatWithin(timeout time.Duration) (time.Duration, error) {
+	sent := time.Now()
+	var reply HeartbeatPayload
+	if err := c.callWithin(timeout, Heartbeat, HeartbeatPayload{Sent: sent}, &reply); err != nil {
+		return 0, err
+	}
+
+	return time.Since(sent), nil
+}
+
+// OnPush registers the handler of the messages sent by the injector without a previous request
+func (c *Client) OnPush(handler func(envelope Envelope)) {
+	c.pendingMutex.Lock()
+	defer c.pendingMutex.Unlock()
+
+	c.pushHandler = handler
+}
+
+func (c *Client) Done() <-chan struct{} {
+	return c.closed
+}
+
+func (c *Client) Close() error {
+	return c.shutdown(net.ErrClosed)
+}
+
+func (c *Client) write(envelope Envelope) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	return WriteEnvelope(c.conn, envelope)
+}
+
+func (c *Client) readLoop() {
+	for {
+		envelope, err := ReadEnvelope(c.conn)
+		if err != nil {
+			_ = c.shutdown(err)
+			return
+		}
+
+		// Pushes are queued without limits, so that slow handlers never delay the replies to the calls
+		if envelope.ID == 0 {
+			c.pushesMutex.Lock()
+			c.pushes = append(c.pushes, envelope)
+			c.pushesMutex.Unlock()
+
+			select {
+			case c.pushed <- struct{}{}:
+			default:
+			}
+			continue
+		}
+
+		c.pendingMutex.Lock()
+		responses, isPending := c.pending[envelope.ID]
+		c.pendingMutex.Unlock()
+
+		if isPending {
+			responses <- envelope
+		}
+	}
+}
+
+// Pushes are handled on their own goroutine, so that handlers can safely perform calls
+func (c *Client) dispatchPushes() {
+	for {
+		select {
+		case <-c.pushed:
+		case <-c.closed:
+			return
+		}
+
+		c.pushesMutex.Lock()
+		pushes := c.pushes
+		c.pushes = nil
+		c.pushesMutex.Unlock()
+
+		for _, envelope := range pushes {
+			c.pendingMutex.Lock()
+			pushHandler := c.pushHandler
+			c.pendingMutex.Unlock()
+
+			if pushHandler != nil {
+				pushHandler(envelope)
+			}
+		}
+	}
+}
+
+func (c *Client) shutdown(cause error) error {
+	var err error
+	c.closeOnce.Do(func() {
+		if errors.Is(cause, net.ErrClosed) {
+			cause = net.ErrClosed
+		}
+
+		c.closeErr = cause
+		err = c.conn.Close()
+		close(c.closed)
+	})
+
+	return err
+}
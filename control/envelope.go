@@ -0,0 +1,82 @@
+package control
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+)
+
+const (
+	ProtocolVersion = 1
+	MaxMessageSize  = 64 * 1024 * 1024
+	headerSize      = 4
+)
+
+type Envelope struct {
+	Type    MessageType     `json:"type"`
+	ID      uint64          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func NewEnvelope(messageType MessageType, id uint64, payload interface{}) (Envelope, error) {
+	output := Envelope{Type: messageType, ID: id}
+	if payload == nil {
+		return output, nil
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	output.Payload = encodedPayload
+	return output, nil
+}
+
+func (e Envelope) Decode(target interface{}) error {
+	if len(e.Payload) == 0 || target == nil {
+		return nil
+	}
+
+	return json.Unmarshal(e.Payload, target)
+}
+
+// WriteEnvelope frames the JSON-encoded envelope with a 4 bytes big-endian length prefix
+func WriteEnvelope(writer io.Writer, envelope Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	if len(body) > MaxMessageSize {
+		return ErrMessageTooLarge{Size: len(body), Limit: MaxMessageSize}
+	}
+
+	frame := make([]byte, headerSize+len(body))
+	binary.BigEndian.PutUint32(frame, uint32(len(body)))
+	copy(frame[headerSize:], body)
+
+	_, err = writer.Write(frame)
+	return err
+}
+
+func ReadEnvelope(reader io.Reader) (Envelope, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return Envelope{}, err
+	}
+
+	size := int(binary.BigEndian.Uint32(header))
+	if size > MaxMessageSize {
+		return Envelope{}, ErrMessageTooLarge{Size: size, Limit: MaxMessageSize}
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return Envelope{}, err
+	}
+
+	var envelope Envelope
+	err := json.Unmarshal(body, &envelope)
+	return envelope, err
+}
@@ -0,0 +1,49 @@
+package control_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestWriteReadEnvelope(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	original, err := control.NewEnvelope(control.UploadScript, 42, control.ScriptPayload{
+		Name:    "login",
+		Role:    control.MainRole,
+		Content: []byte{0x7f, 'E', 'L', 'F'},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, control.WriteEnvelope(buffer, original))
+	assert.Equal(t, uint32(buffer.Len()-4), binary.BigEndian.Uint32(buffer.Bytes()[:4]))
+
+	decoded, err := control.ReadEnvelope(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, control.UploadScript, decoded.Type)
+	assert.Equal(t, uint64(42), decoded.ID)
+
+	var payload control.ScriptPayload
+	assert.NoError(t, decoded.Decode(&payload))
+	assert.Equal(t, "login", payload.Name)
+	assert.Equal(t, []byte{0x7f, 'E', 'L', 'F'}, payload.Content)
+}
+
+func TestReadEnvelope_TooLarge(t *testing.T) {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, control.MaxMessageSize+1)
+
+	_, err := control.ReadEnvelope(bytes.NewReader(header))
+	assert.Equal(t, control.ErrMessageTooLarge{Size: control.MaxMessageSize + 1, Limit: control.MaxMessageSize}, err)
+}
+
+func TestReadEnvelope_Truncated(t *testing.T) {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, 10)
+
+	_, err := control.ReadEnvelope(bytes.NewReader(append(header, '{')))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,15 @@
+package control
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrCallTimeout struct {
+	Type    MessageType
+	Timeout time.Duration
+}
+
+func (ct ErrCallTimeout) Error() string {
+	return fmt.Sprintf("no reply received for '%s' message within %s", ct.Type, ct.Timeout)
+}
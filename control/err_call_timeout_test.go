@@ -0,0 +1,14 @@
+package control_test
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestErrCallTimeout_Error(t *testing.T) {
+	myError := control.ErrCallTimeout{Type: control.QueryStatus, Timeout: 5 * time.Second}
+
+	assert.EqualError(t, myError, "no reply received for 'status' message within 5s", "Wrong error message format")
+}
@@ -0,0 +1,12 @@
+package control
+
+import "fmt"
+
+type ErrMessageTooLarge struct {
+	Size  int
+	Limit int
+}
+
+func (mtl ErrMessageTooLarge) Error() string {
+	return fmt.Sprintf("control message of %d bytes exceeds the limit of %d bytes", mtl.Size, mtl.Limit)
+}
@@ -0,0 +1,13 @@
+package control_test
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrMessageTooLarge_Error(t *testing.T) {
+	myError := control.ErrMessageTooLarge{Size: 10, Limit: 5}
+
+	assert.EqualError(t, myError, "control message of 10 bytes exceeds the limit of 5 bytes", "Wrong error message format")
+}
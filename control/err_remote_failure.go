@@ -0,0 +1,12 @@
+package control
+
+import "fmt"
+
+type ErrRemoteFailure struct {
+	Type    MessageType
+	Message string
+}
+
+func (rf ErrRemoteFailure) Error() string {
+	return fmt.Sprintf("remote injector failed to handle '%s' message: %s", rf.Type, rf.Message)
+}
@@ -0,0 +1,13 @@
+package control_test
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrRemoteFailure_Error(t *testing.T) {
+	myError := control.ErrRemoteFailure{Type: control.StartTest, Message: "already running"}
+
+	assert.EqualError(t, myError, "remote injector failed to handle 'start' message: already running", "Wrong error message format")
+}
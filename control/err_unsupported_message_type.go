@@ -0,0 +1,11 @@
+package control
+
+import "fmt"
+
+type ErrUnsupportedMessageType struct {
+	Type MessageType
+}
+
+func (umt ErrUnsupportedMessageType) Error() string {
+	return fmt.Sprintf("unsupported control message type '%s'", umt.Type)
+}
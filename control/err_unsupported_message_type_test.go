@@ -0,0 +1,13 @@
+package control_test
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnsupportedMessageType_Error(t *testing.T) {
+	myError := control.ErrUnsupportedMessageType{Type: "bogus"}
+
+	assert.EqualError(t, myError, "unsupported control message type 'bogus'", "Wrong error message format")
+}
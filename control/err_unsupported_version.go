@@ -0,0 +1,12 @@
+package control
+
+import "fmt"
+
+type ErrUnsupportedVersion struct {
+	Local  int
+	Remote int
+}
+
+func (uv ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported control protocol version %d, expected version %d", uv.Remote, uv.Local)
+}
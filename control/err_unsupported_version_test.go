@@ -0,0 +1,13 @@
+package control_test
+
+import (
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnsupportedVersion_Error(t *testing.T) {
+	myError := control.ErrUnsupportedVersion{Local: 1, Remote: 2}
+
+	assert.EqualError(t, myError, "unsupported control protocol version 2, expected version 1", "Wrong error message format")
+}
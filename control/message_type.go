@@ -0,0 +1,24 @@
+package control
+
+type MessageType string
+
+const (
+	Hello           MessageType = "hello"
+	Welcome         MessageType = "welcome"
+	UploadScript    MessageType = "upload_script"
+	UploadSpecs     MessageType = "upload_specs"
+	StartTest       MessageType = "start"
+	StopTest        MessageType = "stop"
+	PauseTest       MessageType = "pause"
+	ResumeTest      MessageType = "resume"
+	OverrideShooter MessageType = "override_shooters"
+	AssignPartition MessageType = "assign_partition"
+	AssignRates     MessageType = "assign_rates"
+	QueryStatus     MessageType = "status"
+	QueryAggregates MessageType = "aggregates"
+	Heartbeat       MessageType = "heartbeat"
+	TelemetryBatch  MessageType = "telemetry"
+	TelemetryAck    MessageType = "telemetry_ack"
+	Ack             MessageType = "ack"
+	Failure         MessageType = "error"
+)
@@ -0,0 +1,88 @@
+package control
+
+import "time"
+
+type ScriptRole string
+
+const (
+	SetUpRole    ScriptRole = "set_up"
+	MainRole     ScriptRole = "main"
+	TearDownRole ScriptRole = "tear_down"
+)
+
+type HelloPayload struct {
+	Version int    `json:"version"`
+	Client  string `json:"client"`
+}
+
+type WelcomePayload struct {
+	Version    int    `json:"version"`
+	InjectorID string `json:"injector_id"`
+}
+
+type ScriptPayload struct {
+	Name    string     `json:"name"`
+	Role    ScriptRole `json:"role"`
+	Content []byte     `json:"content"`
+}
+
+type SpecsPayload struct {
+	Content []byte `json:"content"`
+}
+
+type OverridePayload struct {
+	// A nil amount of shooters removes the override and gives control back to the load profiles
+	Shooters *int `json:"shooters"`
+}
+
+// PartitionPayload tells an injector which slice of the test-wide feeder rows belongs to it
+type PartitionPayload struct {
+	Index int `json:"index"`
+	Count int `json:"count"`
+}
+
+// RatesPayload tells an injector which share of every arrival rate ramp it has to generate
+type RatesPayload struct {
+	Shares []RateShare `json:"shares"`
+}
+
+type RateShare struct {
+	Ramp     int     `json:"ramp"`
+	Fraction float64 `json:"fraction"`
+	PoolSize int     `json:"pool_size"`
+}
+
+type StatusPayload struct {
+	InjectorID     string            `json:"injector_id"`
+	Running        bool              `json:"running"`
+	Paused         bool              `json:"paused"`
+	Elapsed        time.Duration     `json:"elapsed"`
+	ActiveShooters int               `json:"active_shooters"`
+	Override       *int              `json:"override,omitempty"`
+	Shooters       map[string]string `json:"shooters,omitempty"`
+
+	// Thresholds failed by the last completed run
+	ThresholdViolations []string `json:"threshold_violations,omitempty"`
+}
+
+type HeartbeatPayload struct {
+	Sent time.Time `json:"sent"`
+}
+
+type TelemetryPayload struct {
+	Sequence uint64 `json:"sequence"`
+	Data     []byte `json:"data"`
+}
+
+type TelemetryAckPayload struct {
+	Sequence uint64 `json:"sequence"`
+}
+
+// AggregatesPayload carries the JSON-encoded aggregates of an injector, to be merged by the cockpit
+type AggregatesPayload struct {
+	Data []byte `json:"data"`
+}
+
+type FailurePayload struct {
+	Message string `json:"message"`
+}
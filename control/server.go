@@ -0,0 +1,231 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"net"
+	"sync"
+	"time"
+)
+
+type HandlerFunc func(request Envelope) (interface{}, error)
+
+type Server struct {
+	InjectorID string
+	Handler    HandlerFunc
+	Logger     zerolog.Logger
+
+	listener      net.Listener
+	sessions      map[*session]struct{}
+	lastHeartbeat time.Time
+	waitGroup     sync.WaitGroup
+	mutex         sync.Mutex
+}
+
+type session struct {
+	conn       net.Conn
+	writeMutex sync.Mutex
+}
+
+func NewServer(injectorID string, handler HandlerFunc, logger zerolog.Logger) *Server {
+	output := new(Server)
+	output.InjectorID = injectorID
+	output.Handler = handler
+	output.Logger = logger.With().Str("component", "Control Server").Logger()
+	output.sessions = make(map[*session]struct{})
+
+	return output
+}
+
+// Serve accepts control connections until the listener is closed
+func (s *Server) Serve(listener net.Listener) error {
+	s.mutex.Lock()
+	s.listener = listener
+	s.mutex.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.waitGroup.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+		s.listener = nil
+	}
+	for activeSession := range s.sessions {
+		_ = activeSession.conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.waitGroup.Wait()
+	return err
+}
+
+// Push sends an unsolicited message to all the connected cockpits
+func (s *Server) Push(messageType MessageType, payload interface{}) error {
+	envelope, err := NewEnvelope(messageType, 0, payload)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for activeSession := range s.sessions {
+		sessions = append(sessions, activeSession)
+	}
+	s.mutex.Unlock()
+
+	var firstErr error
+	for _, activeSession := range sessions {
+		if err := activeSession.write(envelope); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (s *Server) LastHeartbeat() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.lastHeartbeat
+}
+
+func (s *Server) Sessions() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.sessions)
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.waitGroup.Done()
+
+	activeSession := &session{conn: conn}
+	defer func() {
+		_ = conn.Close()
+		s.mutex.Lock()
+		delete(s.sessions, activeSession)
+		s.mutex.Unlock()
+	}()
+
+	logger := s.Logger.With().Str("remote", conn.RemoteAddr().String()).Logger()
+	if err := s.handshake(activeSession); err != nil {
+		logger.Warn().Err(err).Msg("Control handshake failed, closing connection")
+		return
+	}
+
+	s.mutex.Lock()
+	s.sessions[activeSession] = struct{}{}
+	s.mutex.Unlock()
+	logger.Info().Msg("Cockpit connected")
+
+	for {
+		request, err := ReadEnvelope(conn)
+		if err != nil {
+			logger.Info().Err(err).Msg("Cockpit disconnected")
+			return
+		}
+
+		response := s.dispatch(request)
+		if err := activeSession.write(response); err != nil {
+			logger.Warn().Err(err).Msg("Cannot send control response, closing connection")
+			return
+		}
+	}
+}
+
+func (s *Server) handshake(activeSession *session) error {
+	request, err := ReadEnvelope(activeSession.conn)
+	if err != nil {
+		return err
+	}
+
+	var hello HelloPayload
+	if request.Type != Hello {
+		err = ErrUnsupportedMessageType{Type: request.Type}
+	} else if err = request.Decode(&hello); err == nil && hello.Version != ProtocolVersion {
+		err = ErrUnsupportedVersion{Local: ProtocolVersion, Remote: hello.Version}
+	}
+
+	if err != nil {
+		failure, _ := NewEnvelope(Failure, request.ID, FailurePayload{Message: err.Error()})
+		_ = activeSession.write(failure)
+		return err
+	}
+
+	welcome, err := NewEnvelope(Welcome, request.ID, WelcomePayload{Version: ProtocolVersion, InjectorID: s.InjectorID})
+	if err != nil {
+		return err
+	}
+
+	return activeSession.write(welcome)
+}
+
+func (s *Server) dispatch(request Envelope) Envelope {
+	var reply interface{}
+	var err error
+
+	switch request.Type {
+	case Heartbeat:
+		s.mutex.Lock()
+		s.lastHeartbeat = time.Now()
+		s.mutex.Unlock()
+
+		var heartbeat HeartbeatPayload
+		err = request.Decode(&heartbeat)
+		reply = heartbeat
+
+	default:
+		reply, err = s.safeHandle(request)
+	}
+
+	if err != nil {
+		s.Logger.Warn().Err(err).Str("type", string(request.Type)).Msg("Control request failed")
+		response, _ := NewEnvelope(Failure, request.ID, FailurePayload{Message: err.Error()})
+		return response
+	}
+
+	response, err := NewEnvelope(Ack, request.ID, reply)
+	if err != nil {
+		response, _ = NewEnvelope(Failure, request.ID, FailurePayload{Message: err.Error()})
+	}
+
+	return response
+}
+
+func (s *Server) safeHandle(request Envelope) (reply interface{}, err error) {
+	// A failing handler must not bring the whole injector down
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	if s.Handler == nil {
+		return nil, ErrUnsupportedMessageType{Type: request.Type}
+	}
+
+	return s.Handler(request)
+}
+
+func (activeSession *session) write(envelope Envelope) error {
+	activeSession.writeMutex.Lock()
+	defer activeSession.writeMutex.Unlock()
+
+	return WriteEnvelope(activeSession.conn, envelope)
+}
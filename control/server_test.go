@@ -0,0 +1,135 @@
+package control_test
+
+import (
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	server   *control.Server
+	listener net.Listener
+	received []control.Envelope
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	suite.received = nil
+	suite.server = control.NewServer("injector-1", func(request control.Envelope) (interface{}, error) {
+		suite.received = append(suite.received, request)
+
+		switch request.Type {
+		case control.QueryStatus:
+			return control.StatusPayload{InjectorID: "injector-1", ActiveShooters: 3}, nil
+		case control.StartTest:
+			return nil, errors.New("no scripts uploaded")
+		default:
+			return nil, nil
+		}
+	}, zerolog.New(ioutil.Discard))
+
+	var err error
+	suite.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	go func() {
+		_ = suite.server.Serve(suite.listener)
+	}()
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	assert.NoError(suite.T(), suite.server.Close())
+}
+
+func (suite *ServerTestSuite) TestHandshake() {
+	client, err := control.Dial(suite.listener.Addr().String(), time.Second)
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	assert.Equal(suite.T(), "injector-1", client.InjectorID)
+	assert.Eventually(suite.T(), func() bool {
+		return suite.server.Sessions() == 1
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *ServerTestSuite) TestHandshakeWithWrongVersion() {
+	conn, err := net.Dial("tcp", suite.listener.Addr().String())
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	hello, _ := control.NewEnvelope(control.Hello, 1, control.HelloPayload{Version: control.ProtocolVersion + 1})
+	suite.Require().NoError(control.WriteEnvelope(conn, hello))
+
+	response, err := control.ReadEnvelope(conn)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), control.Failure, response.Type)
+
+	var failure control.FailurePayload
+	assert.NoError(suite.T(), response.Decode(&failure))
+	assert.Equal(suite.T(), control.ErrUnsupportedVersion{Local: control.ProtocolVersion, Remote: control.ProtocolVersion + 1}.Error(), failure.Message)
+}
+
+func (suite *ServerTestSuite) TestCallAndFailure() {
+	client, err := control.Dial(suite.listener.Addr().String(), time.Second)
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	var status control.StatusPayload
+	assert.NoError(suite.T(), client.Call(control.QueryStatus, nil, &status))
+	assert.Equal(suite.T(), 3, status.ActiveShooters)
+
+	err = client.Call(control.StartTest, nil, nil)
+	assert.Equal(suite.T(), control.ErrRemoteFailure{Type: control.StartTest, Message: "no scripts uploaded"}, err)
+	assert.Len(suite.T(), suite.received, 2)
+}
+
+func (suite *ServerTestSuite) TestHeartbeatAndPush() {
+	client, err := control.Dial(suite.listener.Addr().String(), time.Second)
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	pushes := make(chan control.Envelope, 1)
+	client.OnPush(func(envelope control.Envelope) {
+		pushes <- envelope
+	})
+
+	roundTrip, err := client.Heartbeat()
+	assert.NoError(suite.T(), err)
+	assert.Greater(suite.T(), roundTrip, time.Duration(0))
+	assert.False(suite.T(), suite.server.LastHeartbeat().IsZero())
+	assert.Empty(suite.T(), suite.received, "heartbeats are handled by the server itself")
+
+	assert.NoError(suite.T(), suite.server.Push(control.QueryStatus, control.StatusPayload{Running: true}))
+	select {
+	case push := <-pushes:
+		var status control.StatusPayload
+		assert.NoError(suite.T(), push.Decode(&status))
+		assert.True(suite.T(), status.Running)
+	case <-time.After(time.Second):
+		suite.Fail("push message not received")
+	}
+}
+
+func (suite *ServerTestSuite) TestClientNotifiedOnServerClose() {
+	client, err := control.Dial(suite.listener.Addr().String(), time.Second)
+	suite.Require().NoError(err)
+
+	assert.NoError(suite.T(), suite.server.Close())
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		suite.Fail("client not notified of the closed connection")
+	}
+
+	assert.Error(suite.T(), client.Call(control.QueryStatus, nil, nil))
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,11 @@
+package feeder
+
+import "fmt"
+
+type ErrFeederExhausted struct {
+	Name string
+}
+
+func (fe ErrFeederExhausted) Error() string {
+	return fmt.Sprintf("feeder '%s' has no more rows available", fe.Name)
+}
@@ -0,0 +1,13 @@
+package feeder_test
+
+import (
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrFeederExhausted_Error(t *testing.T) {
+	myError := feeder.ErrFeederExhausted{Name: "users"}
+
+	assert.EqualError(t, myError, "feeder 'users' has no more rows available", "Wrong error message format")
+}
@@ -0,0 +1,16 @@
+package feeder
+
+import "fmt"
+
+type ErrUnsupportedSourceType struct {
+	SourceType SourceType
+}
+
+func (ust ErrUnsupportedSourceType) Error() string {
+	return fmt.Sprintf(
+		"'%s' is not a supported feeder type. Supported types are: %s, %s, %s",
+		ust.SourceType,
+		CSV,
+		JSONLines,
+		Inline)
+}
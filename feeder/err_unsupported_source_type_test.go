@@ -0,0 +1,17 @@
+package feeder_test
+
+import (
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnsupportedSourceType_Error(t *testing.T) {
+	myError := feeder.ErrUnsupportedSourceType{SourceType: "xml"}
+
+	assert.EqualError(
+		t,
+		myError,
+		"'xml' is not a supported feeder type. Supported types are: csv, jsonl, inline",
+		"Wrong error message format")
+}
@@ -0,0 +1,85 @@
+package feeder
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type Feeder struct {
+	Name     string
+	Strategy Strategy
+	Sharing  Sharing
+
+	rows   []Row
+	cursor int
+	random *rand.Rand
+	mutex  *sync.Mutex
+}
+
+func New(name string, rows []Row, strategy Strategy, sharing Sharing) *Feeder {
+	output := new(Feeder)
+	output.Name = name
+	output.Strategy = strategy
+	output.Sharing = sharing
+	output.rows = rows
+	output.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	output.mutex = new(sync.Mutex)
+
+	return output
+}
+
+func (f *Feeder) Next() (Row, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.rows) == 0 {
+		return nil, ErrFeederExhausted{Name: f.Name}
+	}
+
+	switch f.Strategy {
+	case Random:
+		return f.rows[f.random.Intn(len(f.rows))], nil
+
+	case Unique:
+		if f.cursor >= len(f.rows) {
+			return nil, ErrFeederExhausted{Name: f.Name}
+		}
+
+		row := f.rows[f.cursor]
+		f.cursor++
+		return row, nil
+
+	case Circular:
+		row := f.rows[f.cursor%len(f.rows)]
+		f.cursor = (f.cursor + 1) % len(f.rows)
+		return row, nil
+
+	default:
+		// Sequential feeders keep on returning the last row once the data is over
+		row := f.rows[f.cursor]
+		if f.cursor < len(f.rows)-1 {
+			f.cursor++
+		}
+		return row, nil
+	}
+}
+
+func (f *Feeder) Len() int {
+	return len(f.rows)
+}
+
+func (f *Feeder) Clone() *Feeder {
+	return New(f.Name, f.rows, f.Strategy, f.Sharing)
+}
+
+func (f *Feeder) Partition(index int, count int) *Feeder {
+	var partitionRows []Row
+	for rowIndex, row := range f.rows {
+		if rowIndex%count == index {
+			partitionRows = append(partitionRows, row)
+		}
+	}
+
+	return New(f.Name, partitionRows, f.Strategy, f.Sharing)
+}
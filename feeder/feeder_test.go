@@ -0,0 +1,122 @@
+package feeder_test
+
+import (
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+var testRows = []feeder.Row{
+	{"username": "alice"},
+	{"username": "bob"},
+	{"username": "carol"},
+}
+
+func TestFeeder_NextSequential(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Sequential, feeder.PerShooter)
+
+	for _, expected := range []string{"alice", "bob", "carol", "carol"} {
+		row, err := testFeeder.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, row["username"])
+		}
+	}
+}
+
+func TestFeeder_NextCircular(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Circular, feeder.PerShooter)
+
+	for _, expected := range []string{"alice", "bob", "carol", "alice"} {
+		row, err := testFeeder.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, row["username"])
+		}
+	}
+}
+
+func TestFeeder_NextUnique(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Unique, feeder.PerShooter)
+
+	for _, expected := range []string{"alice", "bob", "carol"} {
+		row, err := testFeeder.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, row["username"])
+		}
+	}
+
+	row, err := testFeeder.Next()
+	assert.Nil(t, row)
+	assert.IsType(t, feeder.ErrFeederExhausted{}, err)
+}
+
+func TestFeeder_NextRandom(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Random, feeder.PerShooter)
+
+	for index := 0; index < 10; index++ {
+		row, err := testFeeder.Next()
+		if assert.NoError(t, err) {
+			assert.Contains(t, testRows, row)
+		}
+	}
+}
+
+func TestFeeder_NextEmpty(t *testing.T) {
+	testFeeder := feeder.New("users", []feeder.Row{}, feeder.Circular, feeder.PerShooter)
+
+	_, err := testFeeder.Next()
+	assert.IsType(t, feeder.ErrFeederExhausted{}, err)
+}
+
+func TestFeeder_NextConcurrentUnique(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Unique, feeder.PerInjector)
+	wg := sync.WaitGroup{}
+	results := make(chan interface{}, 10)
+
+	for index := 0; index < 10; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if row, err := testFeeder.Next(); err == nil {
+				results <- row["username"]
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	var usernames []interface{}
+	for username := range results {
+		usernames = append(usernames, username)
+	}
+	assert.ElementsMatch(t, []interface{}{"alice", "bob", "carol"}, usernames)
+}
+
+func TestFeeder_Clone(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Unique, feeder.PerShooter)
+	_, _ = testFeeder.Next()
+
+	clonedFeeder := testFeeder.Clone()
+	row, err := clonedFeeder.Next()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "alice", row["username"])
+	}
+	assert.Equal(t, testFeeder.Strategy, clonedFeeder.Strategy)
+	assert.Equal(t, testFeeder.Sharing, clonedFeeder.Sharing)
+}
+
+func TestFeeder_Partition(t *testing.T) {
+	testFeeder := feeder.New("users", testRows, feeder.Unique, feeder.PerTest)
+
+	firstPartition := testFeeder.Partition(0, 2)
+	secondPartition := testFeeder.Partition(1, 2)
+
+	assert.Equal(t, 2, firstPartition.Len())
+	assert.Equal(t, 1, secondPartition.Len())
+
+	row, err := secondPartition.Next()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "bob", row["username"])
+	}
+}
@@ -0,0 +1,3 @@
+package feeder
+
+type Row map[string]interface{}
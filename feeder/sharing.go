@@ -0,0 +1,9 @@
+package feeder
+
+type Sharing string
+
+const (
+	PerShooter  Sharing = "shooter"
+	PerInjector Sharing = "injector"
+	PerTest     Sharing = "test"
+)
@@ -0,0 +1,9 @@
+package feeder
+
+type SourceType string
+
+const (
+	CSV       SourceType = "csv"
+	JSONLines SourceType = "jsonl"
+	Inline    SourceType = "inline"
+)
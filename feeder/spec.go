@@ -0,0 +1,125 @@
+package feeder
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+type Spec struct {
+	Name      string     `yaml:"name"`
+	Type      SourceType `yaml:"type"`
+	Path      string     `yaml:"path,omitempty"`
+	Delimiter string     `yaml:"delimiter,omitempty"`
+	Rows      []Row      `yaml:"rows,omitempty"`
+	Strategy  Strategy   `yaml:"strategy,omitempty"`
+	Sharing   Sharing    `yaml:"sharing,omitempty"`
+}
+
+func (s Spec) Build() (*Feeder, error) {
+	var rows []Row
+	var err error
+
+	switch s.Type {
+	case CSV:
+		rows, err = s.loadCSV()
+	case JSONLines:
+		rows, err = s.loadJSONLines()
+	case Inline:
+		rows = s.Rows
+	default:
+		return nil, ErrUnsupportedSourceType{SourceType: s.Type}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := s.Strategy
+	if strategy == "" {
+		strategy = Sequential
+	}
+
+	sharing := s.Sharing
+	if sharing == "" {
+		sharing = PerShooter
+	}
+
+	return New(s.Name, rows, strategy, sharing), nil
+}
+
+func (s Spec) loadCSV() ([]Row, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	if s.Delimiter != "" {
+		reader.Comma = []rune(s.Delimiter)[0]
+	}
+
+	// The first record is always used as header
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []Row{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(Row)
+		for index, column := range header {
+			row[column] = record[index]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (s Spec) loadJSONLines() ([]Row, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var rows []Row
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		row := make(Row)
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
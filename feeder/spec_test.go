@@ -0,0 +1,107 @@
+package feeder_test
+
+import (
+	"github.com/Flaque/filet"
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"os"
+	"testing"
+)
+
+func TestSpec_BuildCSV(t *testing.T) {
+	testFile := filet.TmpFile(t, os.TempDir(), "username,password\nalice,secret1\nbob,secret2\n")
+	defer filet.CleanUp(t)
+
+	spec := feeder.Spec{Name: "users", Type: feeder.CSV, Path: testFile.Name()}
+	testFeeder, err := spec.Build()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, testFeeder.Len())
+		assert.Equal(t, feeder.Sequential, testFeeder.Strategy)
+		assert.Equal(t, feeder.PerShooter, testFeeder.Sharing)
+
+		row, _ := testFeeder.Next()
+		assert.Equal(t, feeder.Row{"username": "alice", "password": "secret1"}, row)
+	}
+}
+
+func TestSpec_BuildCSVWithDelimiter(t *testing.T) {
+	testFile := filet.TmpFile(t, os.TempDir(), "username;password\nalice;secret1\n")
+	defer filet.CleanUp(t)
+
+	spec := feeder.Spec{Name: "users", Type: feeder.CSV, Path: testFile.Name(), Delimiter: ";"}
+	testFeeder, err := spec.Build()
+
+	if assert.NoError(t, err) {
+		row, _ := testFeeder.Next()
+		assert.Equal(t, feeder.Row{"username": "alice", "password": "secret1"}, row)
+	}
+}
+
+func TestSpec_BuildJSONLines(t *testing.T) {
+	testFile := filet.TmpFile(t, os.TempDir(), "{\"username\": \"alice\", \"age\": 30}\n\n{\"username\": \"bob\", \"age\": 40}\n")
+	defer filet.CleanUp(t)
+
+	spec := feeder.Spec{Name: "users", Type: feeder.JSONLines, Path: testFile.Name(), Strategy: feeder.Unique}
+	testFeeder, err := spec.Build()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, testFeeder.Len())
+		assert.Equal(t, feeder.Unique, testFeeder.Strategy)
+
+		row, _ := testFeeder.Next()
+		assert.Equal(t, "alice", row["username"])
+		assert.EqualValues(t, 30, row["age"])
+	}
+}
+
+func TestSpec_BuildInline(t *testing.T) {
+	spec := feeder.Spec{
+		Name:    "users",
+		Type:    feeder.Inline,
+		Rows:    testRows,
+		Sharing: feeder.PerInjector,
+	}
+	testFeeder, err := spec.Build()
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3, testFeeder.Len())
+		assert.Equal(t, feeder.PerInjector, testFeeder.Sharing)
+	}
+}
+
+func TestSpec_BuildMissingFile(t *testing.T) {
+	spec := feeder.Spec{Name: "users", Type: feeder.CSV, Path: "non/existing/file.csv"}
+	_, err := spec.Build()
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSpec_BuildUnsupportedType(t *testing.T) {
+	spec := feeder.Spec{Name: "users", Type: "xml"}
+	_, err := spec.Build()
+
+	assert.IsType(t, feeder.ErrUnsupportedSourceType{}, err)
+}
+
+func TestSpec_UnmarshalYAML(t *testing.T) {
+	rawSpec := `
+name: users
+type: inline
+strategy: circular
+sharing: test
+rows:
+  - username: alice
+  - username: bob
+`
+	var spec feeder.Spec
+	err := yaml.Unmarshal([]byte(rawSpec), &spec)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, feeder.Inline, spec.Type)
+		assert.Equal(t, feeder.Circular, spec.Strategy)
+		assert.Equal(t, feeder.PerTest, spec.Sharing)
+		assert.Len(t, spec.Rows, 2)
+	}
+}
@@ -0,0 +1,10 @@
+package feeder
+
+type Strategy string
+
+const (
+	Sequential Strategy = "sequential"
+	Random     Strategy = "random"
+	Unique     Strategy = "unique"
+	Circular   Strategy = "circular"
+)
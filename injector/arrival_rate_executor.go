@@ -0,0 +1,256 @@
+package injector
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DroppedIterationsMetric = "dropped_iterations"
+	idleRateCheckInterval   = 100 * time.Millisecond
+	pauseCheckInterval      = 10 * time.Millisecond
+)
+
+type ArrivalRateExecutor struct {
+	Profile load.RateProfile
+
+	context         context.Context
+	clock           *load.Clock
+	logger          zerolog.Logger
+	newShooter      func() *shooter.Shooter
+	shooters        []*shooter.Shooter
+	shootersMutex   sync.Mutex
+	idleShooters    chan *shooter.Shooter
+	sampleCollector *telemetry.SampleCollector
+	waitGroup       sync.WaitGroup
+	finished        chan struct{}
+
+	startedIterations int64
+	droppedIterations int64
+}
+
+// sharedRate is the part of an arrival rate generated by a single injector, when the cockpit splits it among several ones
+type sharedRate struct {
+	load.RateProfile
+	fraction float64
+	poolSize int
+}
+
+func (s sharedRate) At(elapsed time.Duration) int {
+	if s.RateProfile.At(elapsed) == 0 {
+		return 0
+	}
+
+	return s.poolSize
+}
+
+func (s sharedRate) RateAt(elapsed time.Duration) float64 {
+	return s.RateProfile.RateAt(elapsed) * s.fraction
+}
+
+func (s sharedRate) PoolSize() int {
+	return s.poolSize
+}
+
+func (i *Injector) NewArrivalRateExecutor(profile load.RateProfile) *ArrivalRateExecutor {
+	executor := new(ArrivalRateExecutor)
+	executor.Profile = profile
+	executor.context = i.Context
+	executor.clock = i.clock
+	executor.logger = i.Logger.With().Str("component", "Arrival Rate Executor").Logger()
+	executor.newShooter = i.initShooter
+	executor.sampleCollector = telemetry.NewSampleCollector(i.settings.CollectorCapacity, i.settings.OverflowPolicy)
+	executor.sampleCollector.AddSink(i.telemetrySink(i.ID))
+	executor.finished = make(chan struct{})
+
+	return executor
+}
+
+func (e *ArrivalRateExecutor) Run() {
+	defer close(e.finished)
+
+	if !e.clock.Started() {
+		e.clock.Start()
+	}
+
+	e.allocateShooters()
+	defer e.releaseShooters()
+
+	// Iterations are scheduled on the test clock, which stands still while the test is paused
+	startTime := e.clock.Elapsed()
+	nextIterationTime := startTime
+
+	for {
+		elapsed := nextIterationTime - startTime
+		if elapsed >= e.Profile.TotalDuration() {
+			break
+		}
+
+		if !e.waitUntil(nextIterationTime) {
+			e.logger.Info().Msg("Context cancelled, stopping arrival rate executor")
+			break
+		}
+
+		rate := e.Profile.RateAt(elapsed)
+		if rate <= 0 {
+			nextIterationTime += idleRateCheckInterval
+			continue
+		}
+
+		e.startIteration()
+		nextIterationTime += time.Duration(float64(time.Second) / rate)
+	}
+
+	e.waitGroup.Wait()
+}
+
+func (e *ArrivalRateExecutor) StartedIterations() int64 {
+	return atomic.LoadInt64(&e.startedIterations)
+}
+
+func (e *ArrivalRateExecutor) DroppedIterations() int64 {
+	return atomic.LoadInt64(&e.droppedIterations)
+}
+
+func (e *ArrivalRateExecutor) SampleCollector() *telemetry.SampleCollector {
+	return e.sampleCollector
+}
+
+// Finished is closed once the executor has stopped starting iterations and its shooters are torn down
+func (e *ArrivalRateExecutor) Finished() <-chan struct{} {
+	return e.finished
+}
+
+func (e *ArrivalRateExecutor) allocateShooters() {
+	poolSize := e.Profile.PoolSize()
+	e.idleShooters = make(chan *shooter.Shooter, poolSize)
+
+	for index := 0; index < poolSize; index++ {
+		// Pooled shooters are not started, so they are tracked by the wait group until released
+		newShooter := e.newShooter()
+		if newShooter.WaitGroup != nil {
+			newShooter.WaitGroup.Add(1)
+		}
+
+		if !newShooter.SetUp() {
+			e.logger.Warn().Str("shooter", newShooter.ID()).Msg("Shooter setup failed, excluding it from the pool")
+			newShooter.Release()
+			continue
+		}
+
+		e.shootersMutex.Lock()
+		// Shooters allocated during a pause are kept on hold as well
+		if e.clock.IsPaused() {
+			newShooter.Pause()
+		}
+		e.shooters = append(e.shooters, newShooter)
+		e.shootersMutex.Unlock()
+		e.idleShooters <- newShooter
+	}
+
+	e.logger.Info().Msgf("Pre-allocated %d shooters out of %d", len(e.shooters), poolSize)
+}
+
+func (e *ArrivalRateExecutor) releaseShooters() {
+	e.shootersMutex.Lock()
+	allocatedShooters := e.shooters
+	e.shootersMutex.Unlock()
+
+	for _, allocatedShooter := range allocatedShooters {
+		allocatedShooter.TearDown()
+		allocatedShooter.Release()
+	}
+}
+
+// pause holds the pooled shooters, so that the iterations already started stop at the next boundary
+func (e *ArrivalRateExecutor) pause() {
+	e.shootersMutex.Lock()
+	defer e.shootersMutex.Unlock()
+
+	for _, pooledShooter := range e.shooters {
+		pooledShooter.Pause()
+	}
+}
+
+func (e *ArrivalRateExecutor) resume() {
+	e.shootersMutex.Lock()
+	defer e.shootersMutex.Unlock()
+
+	for _, pooledShooter := range e.shooters {
+		pooledShooter.Resume()
+	}
+}
+
+// waitUntil waits for the test clock to reach the given elapsed time, checking the pause often enough to resume on time
+func (e *ArrivalRateExecutor) waitUntil(instant time.Duration) bool {
+	for {
+		wait := instant - e.clock.Elapsed()
+		if wait <= 0 && !e.clock.IsPaused() {
+			return true
+		}
+		if e.clock.IsPaused() || wait > pauseCheckInterval {
+			wait = pauseCheckInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-e.context.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+func (e *ArrivalRateExecutor) startIteration() {
+	select {
+	case idleShooter := <-e.idleShooters:
+		atomic.AddInt64(&e.startedIterations, 1)
+		e.waitGroup.Add(1)
+
+		go func() {
+			defer e.waitGroup.Done()
+
+			// Shooters that cannot go on (e.g. exhausted feeders) are not given back to the pool
+			if idleShooter.Iterate() {
+				e.idleShooters <- idleShooter
+			}
+		}()
+
+	default:
+		// No shooter available, the iteration is lost and tracked as such
+		atomic.AddInt64(&e.droppedIterations, 1)
+		e.sampleCollector.Collect(telemetry.NewMetricSample(DroppedIterationsMetric, time.Now(), 1))
+		e.logger.Warn().Msg("No idle shooter available, dropping iteration")
+	}
+}
+
+// startExecutors runs every arrival rate profile on its own executor, instead of following it with closed-loop shooters
+func (i *Injector) startExecutors() *sync.WaitGroup {
+	executors := new(sync.WaitGroup)
+	for _, profile := range i.loadProfiles {
+		rateProfile, isRate := profile.(load.RateProfile)
+		if !isRate {
+			continue
+		}
+
+		executor := i.NewArrivalRateExecutor(rateProfile)
+		i.streamMutex.Lock()
+		i.executors = append(i.executors, executor)
+		i.streamMutex.Unlock()
+
+		executors.Add(1)
+		go func() {
+			defer executors.Done()
+			executor.Run()
+		}()
+	}
+
+	return executors
+}
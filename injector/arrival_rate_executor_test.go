@@ -0,0 +1,115 @@
+package injector_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ArrivalRateExecutorTestSuite struct {
+	suite.Suite
+	injector *injector.Injector
+}
+
+func (suite *ArrivalRateExecutorTestSuite) SetupTest() {
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{})
+}
+
+func (suite *ArrivalRateExecutorTestSuite) TestRunWithEnoughShooters() {
+	var executedIterations int64
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		atomic.AddInt64(&executedIterations, 1)
+		return nil
+	}}
+
+	profile, _ := load.ParseArrivalRate(100, "1s", "200ms", 5)
+	executor := suite.injector.NewArrivalRateExecutor(profile)
+	executor.Run()
+
+	assert.InDelta(suite.T(), 20, executor.StartedIterations(), 2)
+	assert.Equal(suite.T(), executor.StartedIterations(), atomic.LoadInt64(&executedIterations))
+	assert.Zero(suite.T(), executor.DroppedIterations())
+	assert.Empty(suite.T(), executor.SampleCollector().Flush())
+}
+
+func (suite *ArrivalRateExecutorTestSuite) TestRunWithExhaustedPool() {
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}}
+
+	profile, _ := load.ParseArrivalRate(50, "1s", "200ms", 1)
+	executor := suite.injector.NewArrivalRateExecutor(profile)
+	executor.Run()
+
+	assert.Greater(suite.T(), executor.DroppedIterations(), int64(0))
+	assert.Greater(suite.T(), executor.StartedIterations(), int64(0))
+
+	collectedSamples := executor.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, int(executor.DroppedIterations())) {
+		assert.IsType(suite.T(), telemetry.MetricSample{}, collectedSamples[0])
+		assert.Equal(suite.T(), injector.DroppedIterationsMetric, collectedSamples[0].Name())
+	}
+}
+
+func (suite *ArrivalRateExecutorTestSuite) TestRunCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.injector = injector.New(ctx, ioutil.Discard, injector.Settings{})
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return nil
+	}}
+
+	profile, _ := load.ParseArrivalRate(10, "1s", "1h", 1)
+	executor := suite.injector.NewArrivalRateExecutor(profile)
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	executor.Run()
+	assert.Less(suite.T(), time.Since(start), time.Second)
+}
+
+func (suite *ArrivalRateExecutorTestSuite) TestPauseHoldsIterations() {
+	var executedIterations int64
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		atomic.AddInt64(&executedIterations, 1)
+		return nil
+	}}
+	profile, _ := load.ParseArrivalRate(100, "1s", "400ms", 5)
+	suite.injector.AddLoadProfile(profile)
+
+	start := time.Now()
+	done := make(chan error)
+	go func() {
+		done <- suite.injector.Run()
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	suite.Require().NoError(suite.injector.Pause())
+	time.Sleep(20 * time.Millisecond)
+	pausedIterations := atomic.LoadInt64(&executedIterations)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(suite.T(), pausedIterations, atomic.LoadInt64(&executedIterations))
+	suite.Require().NoError(suite.injector.Resume())
+	assert.NoError(suite.T(), <-done)
+
+	// The paused time is added to the run, instead of being caught up with a burst of iterations
+	assert.Greater(suite.T(), time.Since(start), 700*time.Millisecond)
+	assert.InDelta(suite.T(), 40, atomic.LoadInt64(&executedIterations), 6)
+}
+
+func TestArrivalRateExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(ArrivalRateExecutorTestSuite))
+}
@@ -0,0 +1,19 @@
+package injector
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrFailedThresholds struct {
+	Violations []error
+}
+
+func (ft ErrFailedThresholds) Error() string {
+	messages := make([]string, 0, len(ft.Violations))
+	for _, violation := range ft.Violations {
+		messages = append(messages, violation.Error())
+	}
+
+	return fmt.Sprintf("%d threshold(s) failed: %s", len(ft.Violations), strings.Join(messages, "; "))
+}
@@ -0,0 +1,20 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrFailedThresholds_Error(t *testing.T) {
+	myError := injector.ErrFailedThresholds{Violations: []error{
+		telemetry.ErrThresholdViolated{Check: "logged in", Expected: 1, Actual: 0.5},
+	}}
+
+	assert.EqualError(
+		t,
+		myError,
+		"1 threshold(s) failed: threshold violated for 'logged in': expected pass rate of at least 100.00%, got 50.00%",
+		"Wrong error message format")
+}
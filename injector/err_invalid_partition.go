@@ -0,0 +1,12 @@
+package injector
+
+import "fmt"
+
+type ErrInvalidPartition struct {
+	Index int
+	Count int
+}
+
+func (ip ErrInvalidPartition) Error() string {
+	return fmt.Sprintf("invalid feeder partition %d of %d", ip.Index, ip.Count)
+}
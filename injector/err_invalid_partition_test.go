@@ -0,0 +1,15 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidPartition_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		injector.ErrInvalidPartition{Index: 2, Count: 2},
+		"invalid feeder partition 2 of 2",
+		"Wrong error message format")
+}
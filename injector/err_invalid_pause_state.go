@@ -0,0 +1,13 @@
+package injector
+
+type ErrInvalidPauseState struct {
+	Paused bool
+}
+
+func (ips ErrInvalidPauseState) Error() string {
+	if ips.Paused {
+		return "injector is already paused"
+	}
+
+	return "injector is not paused"
+}
@@ -0,0 +1,12 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidPauseState_Error(t *testing.T) {
+	assert.EqualError(t, injector.ErrInvalidPauseState{Paused: true}, "injector is already paused", "Wrong error message format")
+	assert.EqualError(t, injector.ErrInvalidPauseState{Paused: false}, "injector is not paused", "Wrong error message format")
+}
@@ -0,0 +1,12 @@
+package injector
+
+import "fmt"
+
+type ErrInvalidScriptSymbol struct {
+	Path   string
+	Symbol string
+}
+
+func (iss ErrInvalidScriptSymbol) Error() string {
+	return fmt.Sprintf("symbol '%s' of plugin '%s' is not a valid script function", iss.Symbol, iss.Path)
+}
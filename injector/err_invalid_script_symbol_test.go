@@ -0,0 +1,13 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidScriptSymbol_Error(t *testing.T) {
+	myError := injector.ErrInvalidScriptSymbol{Path: "build/login.so", Symbol: "Script"}
+
+	assert.EqualError(t, myError, "symbol 'Script' of plugin 'build/login.so' is not a valid script function", "Wrong error message format")
+}
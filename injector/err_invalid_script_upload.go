@@ -0,0 +1,12 @@
+package injector
+
+import "fmt"
+
+type ErrInvalidScriptUpload struct {
+	Name   string
+	Reason string
+}
+
+func (isu ErrInvalidScriptUpload) Error() string {
+	return fmt.Sprintf("invalid upload of script '%s': %s", isu.Name, isu.Reason)
+}
@@ -0,0 +1,13 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidScriptUpload_Error(t *testing.T) {
+	myError := injector.ErrInvalidScriptUpload{Name: "login", Reason: "unknown role 'warm_up'"}
+
+	assert.EqualError(t, myError, "invalid upload of script 'login': unknown role 'warm_up'", "Wrong error message format")
+}
@@ -0,0 +1,16 @@
+package injector
+
+import "fmt"
+
+type ErrInvalidTestState struct {
+	Operation string
+	Running   bool
+}
+
+func (its ErrInvalidTestState) Error() string {
+	if its.Running {
+		return fmt.Sprintf("cannot %s while the test is running", its.Operation)
+	}
+
+	return fmt.Sprintf("cannot %s while the test is not running", its.Operation)
+}
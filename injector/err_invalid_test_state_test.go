@@ -0,0 +1,20 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidTestState_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		injector.ErrInvalidTestState{Operation: "upload scripts", Running: true},
+		"cannot upload scripts while the test is running",
+		"Wrong error message format")
+	assert.EqualError(
+		t,
+		injector.ErrInvalidTestState{Operation: "stop", Running: false},
+		"cannot stop while the test is not running",
+		"Wrong error message format")
+}
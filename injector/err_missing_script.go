@@ -0,0 +1,11 @@
+package injector
+
+import "fmt"
+
+type ErrMissingScript struct {
+	Name string
+}
+
+func (ms ErrMissingScript) Error() string {
+	return fmt.Sprintf("script '%s' is declared in the specs but has not been uploaded", ms.Name)
+}
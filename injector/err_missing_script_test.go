@@ -0,0 +1,13 @@
+package injector_test
+
+import (
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrMissingScript_Error(t *testing.T) {
+	myError := injector.ErrMissingScript{Name: "checkout"}
+
+	assert.EqualError(t, myError, "script 'checkout' is declared in the specs but has not been uploaded", "Wrong error message format")
+}
@@ -0,0 +1,77 @@
+package injector
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/telemetry"
+	"strconv"
+	"time"
+)
+
+const (
+	FeedbackEvent             = "feedback_stopped"
+	defaultFeedbackWindow     = 10 * time.Second
+	defaultFeedbackPercentile = 95
+)
+
+func (i *Injector) feedbackReceivers() []load.FeedbackReceiver {
+	var receivers []load.FeedbackReceiver
+	for _, profile := range i.loadProfiles {
+		receivers = append(receivers, load.FeedbackReceivers(profile)...)
+	}
+
+	return receivers
+}
+
+func observeSample(window *telemetry.Window, source string) func(sample telemetry.Sample) {
+	return func(sample telemetry.Sample) {
+		window.Add(telemetry.NewRecord(source, sample))
+	}
+}
+
+// observeFeedback gives the goal-seeking profiles the latest view of the system under test
+func (i *Injector) observeFeedback(elapsed time.Duration) {
+	receivers := i.feedbackReceivers()
+	if len(receivers) == 0 {
+		return
+	}
+
+	percentile := i.FeedbackPercentile
+	if percentile <= 0 {
+		percentile = defaultFeedbackPercentile
+	}
+
+	summary := i.feedbackWindow.Summarize(time.Now(), percentile)
+	observation := load.Observation{
+		Elapsed:    elapsed,
+		Samples:    summary.Count,
+		Throughput: summary.Throughput,
+		Latency:    summary.Latency,
+		ErrorRate:  summary.ErrorRate,
+	}
+
+	for _, receiver := range receivers {
+		wasDone := isDone(receiver)
+		receiver.Observe(observation)
+		if wasDone || !isDone(receiver) {
+			continue
+		}
+
+		result, _ := receiver.Result()
+		i.Logger.Warn().Int("capacity", result.Capacity).Msgf("Feedback profile stopped: %s", result.Reason)
+
+		now := time.Now()
+		i.sampleCollector.Collect(telemetry.NewEventSample(FeedbackEvent, now, now, map[string]string{
+			"capacity": strconv.Itoa(result.Capacity),
+			"reason":   result.Reason,
+		}))
+	}
+}
+
+func isDone(receiver load.FeedbackReceiver) bool {
+	select {
+	case <-receiver.Done():
+		return true
+	default:
+		return false
+	}
+}
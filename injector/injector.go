@@ -7,27 +7,83 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/feeder"
 	"github.com/steromano87/harkonnen/load"
 	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
 	"io"
 	"net"
 	"sync"
 	"time"
 )
 
+var loggingSetup sync.Once
+
 type Injector struct {
-	Context context.Context
-	Logger  zerolog.Logger
+	ID           string
+	Context      context.Context
+	Logger       zerolog.Logger
+	ScriptLoader ScriptLoader
 
 	SetUpScript    shooter.Script
 	MainScripts    []shooter.Script
+	Selector       shooter.ScriptSelector
 	TearDownScript shooter.Script
-	loadProfiles   []load.Profile
+	Pacing         time.Duration
+	ErrorPolicy    shooter.ErrorPolicy
+
+	// Thresholds are evaluated once the load profiles are completed, a failed one makes the run fail
+	Thresholds []telemetry.CheckThreshold
+
+	GracePeriod     time.Duration
+	TearDownTimeout time.Duration
+	TickInterval    time.Duration
+	MaxSpawnRate    float64
+
+	// Tags added to all the samples collected by the shooters
+	Tags map[string]string
+
+	// Goal-seeking load profiles observe the latency percentile of the samples ended in the feedback window
+	FeedbackWindow     time.Duration
+	FeedbackPercentile float64
+
+	loadProfiles []load.Profile
+	feeders      []*feeder.Feeder
+	clock        *load.Clock
+	spawnLimiter spawnLimiter
+
+	shooters        []*shooter.Shooter
+	shootersMutex   sync.RWMutex
+	shooterStatuses map[string]shooter.Status
+	outcomes        map[string]shooter.ShutdownOutcome
+	violations      []string
+	statusMutex     sync.RWMutex
+	sharedVariables *shooter.VariablePool
+	checkCounter    *telemetry.CheckCounter
+	sampleCollector *telemetry.SampleCollector
+	aggregator      *telemetry.Aggregator
+	feedbackWindow  *telemetry.Window
+	waitGroup       sync.WaitGroup
+	stopGroup       sync.WaitGroup
+
+	override      *int
+	overrideMutex sync.RWMutex
+
+	baseContext context.Context
+	cancelFunc  context.CancelFunc
 
-	shooters  []shooter.Shooter
-	waitGroup sync.WaitGroup
+	remote        remoteSetup
+	remoteMutex   sync.Mutex
+	controlServer *control.Server
 
-	cancelFunc context.CancelFunc
+	streamedShooters []*shooter.Shooter
+	executors        []*ArrivalRateExecutor
+	reportedDrops    map[string]uint64
+	streamMutex      sync.Mutex
+	records          []telemetry.Record
+	recordsMutex     sync.Mutex
+	telemetry        *telemetryStreamer
 
 	settings    Settings
 	tcpListener net.Listener
@@ -35,11 +91,26 @@ type Injector struct {
 
 func New(ctx context.Context, logWriter io.Writer, settings Settings) *Injector {
 	output := new(Injector)
+	output.ID = uuid.NewString()
+	output.baseContext = ctx
 	output.Context, output.cancelFunc = context.WithCancel(ctx)
+	output.ScriptLoader = LoadPluginScript
 
-	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	// Logging settings are global, so they are configured only once to avoid racing with running shooters
+	loggingSetup.Do(func() {
+		zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+		zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	})
 	output.Logger = zerolog.New(logWriter).With().Timestamp().Logger()
+	output.sharedVariables = shooter.NewVariablePool(&output.Logger)
+	output.checkCounter = telemetry.NewCheckCounter()
+	output.shooterStatuses = make(map[string]shooter.Status)
+	output.outcomes = make(map[string]shooter.ShutdownOutcome)
+	output.reportedDrops = make(map[string]uint64)
+	output.clock = load.NewClock()
+	output.sampleCollector = new(telemetry.SampleCollector)
+	output.aggregator = telemetry.NewAggregator(settings.AggregationBucket)
+	output.sampleCollector.AddSink(output.telemetrySink(output.ID))
 	output.settings = settings
 
 	return output
@@ -53,23 +124,226 @@ func (i *Injector) Start() {
 	}
 	i.tcpListener = listener
 
-}
+	// Accept control connections from the cockpit
+	i.controlServer = control.NewServer(i.ID, i.handleControl, i.Logger)
+	go func() {
+		if err := i.controlServer.Serve(listener); err != nil {
+			i.Logger.Error().Err(err).Msg("Control server stopped unexpectedly")
+		}
+	}()
 
-func (i *Injector) Stop() {
-	err := i.tcpListener.Close()
+	i.Logger.Info().Str("address", listener.Addr().String()).Msg("Injector listening for control connections")
+
+	// Stream telemetry to the connected cockpits
+	i.telemetry, err = newTelemetryStreamer(i.ID, i.settings, i.controlServer, i.collectRecords, i.Logger)
 	if err != nil {
 		panic(err)
 	}
+	go i.telemetry.run()
+}
+
+func (i *Injector) Stop() {
+	if i.telemetry != nil {
+		i.telemetry.close()
+	}
+
+	// Nothing flushes the collectors anymore, so writers blocked on a full collector are released
+	i.streamMutex.Lock()
+	for _, streamedShooter := range i.streamedShooters {
+		streamedShooter.SampleCollector().Close()
+	}
+	for _, executor := range i.executors {
+		executor.SampleCollector().Close()
+	}
+	i.streamMutex.Unlock()
+
+	if i.controlServer != nil {
+		if err := i.controlServer.Close(); err != nil {
+			panic(err)
+		}
+	}
+
+	// The server may be closed before it starts serving, the port must be released anyway
+	if i.tcpListener != nil {
+		_ = i.tcpListener.Close()
+	}
+
+	i.cleanRemoteSetup()
+}
+
+func (i *Injector) Address() string {
+	if i.tcpListener == nil {
+		return ""
+	}
+
+	return i.tcpListener.Addr().String()
+}
+
+func (i *Injector) SharedVariables() *shooter.VariablePool {
+	return i.sharedVariables
+}
+
+func (i *Injector) CheckCounter() *telemetry.CheckCounter {
+	return i.checkCounter
+}
+
+func (i *Injector) VerifyThresholds(thresholds []telemetry.CheckThreshold) []error {
+	var violations []error
+	for _, threshold := range thresholds {
+		if err := threshold.Evaluate(i.checkCounter); err != nil {
+			violations = append(violations, err)
+		}
+	}
+
+	return violations
+}
+
+// ThresholdViolations returns the thresholds failed by the last completed run
+func (i *Injector) ThresholdViolations() []string {
+	i.statusMutex.RLock()
+	defer i.statusMutex.RUnlock()
+
+	return append([]string(nil), i.violations...)
+}
+
+func (i *Injector) verifyThresholds() error {
+	violations := i.VerifyThresholds(i.Thresholds)
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		i.Logger.Error().Err(violation).Msg("Threshold failed")
+		messages = append(messages, violation.Error())
+	}
+
+	i.statusMutex.Lock()
+	i.violations = messages
+	i.statusMutex.Unlock()
+
+	if len(violations) > 0 {
+		return ErrFailedThresholds{Violations: violations}
+	}
+
+	return nil
+}
+
+func (i *Injector) SampleCollector() *telemetry.SampleCollector {
+	return i.sampleCollector
+}
+
+// Aggregator holds the statistics of the samples collected so far
+func (i *Injector) Aggregator() *telemetry.Aggregator {
+	return i.aggregator
+}
+
+func (i *Injector) Clock() *load.Clock {
+	return i.clock
+}
+
+func (i *Injector) Elapsed() time.Duration {
+	return i.clock.Elapsed()
+}
+
+func (i *Injector) Pause() error {
+	i.shootersMutex.RLock()
+	defer i.shootersMutex.RUnlock()
+
+	if !i.clock.Pause() {
+		return ErrInvalidPauseState{Paused: true}
+	}
+
+	for _, activeShooter := range i.shooters {
+		activeShooter.Pause()
+	}
+
+	// Arrival rate executors stop starting iterations on their own, as they follow the same clock
+	i.streamMutex.Lock()
+	for _, executor := range i.executors {
+		executor.pause()
+	}
+	i.streamMutex.Unlock()
+
+	i.Logger.Info().Msgf("Test paused at %s", i.clock.Elapsed())
+	return nil
+}
+
+func (i *Injector) Resume() error {
+	i.shootersMutex.RLock()
+	defer i.shootersMutex.RUnlock()
+
+	pauseStart, pauseEnd, resumed := i.clock.Resume()
+	if !resumed {
+		return ErrInvalidPauseState{Paused: false}
+	}
+
+	for _, activeShooter := range i.shooters {
+		activeShooter.Resume()
+	}
+
+	i.streamMutex.Lock()
+	for _, executor := range i.executors {
+		executor.resume()
+	}
+	i.streamMutex.Unlock()
+
+	// The paused interval is tracked, so that reports can exclude it
+	i.sampleCollector.Collect(telemetry.NewEventSample(shooter.PauseEvent, pauseStart, pauseEnd, map[string]string{
+		"scope": "injector",
+	}))
+	i.Logger.Info().Msgf("Test resumed after a pause of %s", pauseEnd.Sub(pauseStart))
+	return nil
+}
+
+func (i *Injector) IsPaused() bool {
+	return i.clock.IsPaused()
 }
 
 func (i *Injector) AddLoadProfile(profile load.Profile) {
 	i.loadProfiles = append(i.loadProfiles, profile)
 }
 
+func (i *Injector) AddFeeder(dataFeeder *feeder.Feeder) {
+	i.feeders = append(i.feeders, dataFeeder)
+}
+
+func (i *Injector) OverrideShooters(shooters int) {
+	i.overrideMutex.Lock()
+	defer i.overrideMutex.Unlock()
+
+	i.override = &shooters
+	i.Logger.Info().Msgf("Shooters count overridden to %d", shooters)
+}
+
+func (i *Injector) ClearShooterOverride() {
+	i.overrideMutex.Lock()
+	defer i.overrideMutex.Unlock()
+
+	i.override = nil
+	i.Logger.Info().Msg("Shooters count override removed, following load profiles")
+}
+
+func (i *Injector) ShooterOverride() (int, bool) {
+	i.overrideMutex.RLock()
+	defer i.overrideMutex.RUnlock()
+
+	if i.override == nil {
+		return 0, false
+	}
+
+	return *i.override, true
+}
+
 func (i *Injector) ExpectedShooters(elapsed time.Duration) int {
+	if override, isSet := i.ShooterOverride(); isSet {
+		return override
+	}
+
 	expected := 0
 
 	for _, ramp := range i.loadProfiles {
+		// Shooters of arrival rate profiles are pooled by their executors
+		if _, isRate := ramp.(load.RateProfile); isRate {
+			continue
+		}
 		expected += ramp.At(elapsed)
 	}
 
@@ -80,52 +354,200 @@ func (i *Injector) AdjustScheduling(elapsed time.Duration) error {
 	expectedShooters := i.ExpectedShooters(elapsed)
 	activeShooters := i.ActiveShooters()
 
-	var err error
-
 	if activeShooters < expectedShooters {
-		err = i.addShooter()
+		toSpawn := i.spawnLimiter.take(i.MaxSpawnRate, expectedShooters-activeShooters)
+		for index := 0; index < toSpawn; index++ {
+			if err := i.addShooter(); err != nil {
+				return err
+			}
+		}
 	}
 
-	if activeShooters > expectedShooters {
-		err = i.removeShooter()
+	for index := expectedShooters; index < activeShooters; index++ {
+		if err := i.removeShooter(); err != nil {
+			return err
+		}
 	}
 
-	return err
+	return nil
 }
 
 func (i *Injector) ActiveShooters() int {
+	i.shootersMutex.RLock()
+	defer i.shootersMutex.RUnlock()
+
 	return len(i.shooters)
 }
 
+func (i *Injector) ShooterStatuses() map[string]shooter.Status {
+	i.statusMutex.RLock()
+	defer i.statusMutex.RUnlock()
+
+	output := make(map[string]shooter.Status, len(i.shooterStatuses))
+	for shooterID, status := range i.shooterStatuses {
+		output[shooterID] = status
+	}
+
+	return output
+}
+
+func (i *Injector) ShutdownOutcomes() map[string]shooter.ShutdownOutcome {
+	i.statusMutex.RLock()
+	defer i.statusMutex.RUnlock()
+
+	output := make(map[string]shooter.ShutdownOutcome, len(i.outcomes))
+	for shooterID, outcome := range i.outcomes {
+		output[shooterID] = outcome
+	}
+
+	return output
+}
+
+func (i *Injector) StopShooters() {
+	i.shootersMutex.Lock()
+	shootersToStop := i.shooters
+	i.shooters = nil
+	i.shootersMutex.Unlock()
+
+	for _, shooterToStop := range shootersToStop {
+		i.stopGroup.Add(1)
+		go i.stopShooter(shooterToStop)
+	}
+
+	// Shooters retired earlier by the scheduler are waited for as well
+	i.stopGroup.Wait()
+	i.waitGroup.Wait()
+}
+
 func (i *Injector) addShooter() error {
 	newShooter := i.initShooter()
+
+	i.shootersMutex.Lock()
+	defer i.shootersMutex.Unlock()
+
 	i.shooters = append(i.shooters, newShooter)
+	i.waitGroup.Add(1)
+
+	// Shooters spawned during a pause are kept on hold as well
+	if i.clock.IsPaused() {
+		newShooter.Pause()
+	}
 	newShooter.Start()
 
 	return nil
 }
 
 func (i *Injector) removeShooter() error {
+	i.shootersMutex.Lock()
+	// StopShooters may have emptied the list since the amount of shooters was checked
+	if len(i.shooters) == 0 {
+		i.shootersMutex.Unlock()
+		return nil
+	}
 	shooterToStop := i.shooters[0]
 	i.shooters = append(i.shooters[:0], i.shooters[1:]...)
+	i.shootersMutex.Unlock()
 
-	shooterToStop.ScheduleShutDown()
+	i.stopGroup.Add(1)
+	go i.stopShooter(shooterToStop)
 
 	return nil
 }
 
-func (i *Injector) initShooter() shooter.Shooter {
+func (i *Injector) stopShooter(shooterToStop *shooter.Shooter) {
+	defer i.stopGroup.Done()
+
+	outcome := shooterToStop.Stop(i.GracePeriod)
+	if outcome == shooter.Forced {
+		i.Logger.Warn().Str("shooter", shooterToStop.ID()).Msg("Shooter has been forcibly stopped")
+	}
+
+	i.statusMutex.Lock()
+	defer i.statusMutex.Unlock()
+	i.outcomes[shooterToStop.ID()] = outcome
+}
+
+func (i *Injector) initShooter() *shooter.Shooter {
 	shooterID := uuid.NewString()
 	shooterLogger := log.With().Str("ID", shooterID).Logger()
 
-	newShooter := shooter.Shooter{
-		Context:        shooter.NewContext(i.Context, shooterLogger, shooterID),
-		SetUpScript:    i.SetUpScript,
-		MainScripts:    i.MainScripts,
-		TearDownScript: i.TearDownScript,
-		MaxIterations:  0,
-		WaitGroup:      &i.waitGroup,
+	shooterContext := shooter.NewContext(i.Context, shooterLogger, shooterID)
+	shooterContext.UseSharedVariables(i.sharedVariables)
+	shooterContext.UseCheckCounter(i.checkCounter)
+	collector := telemetry.NewSampleCollector(i.settings.CollectorCapacity, i.settings.OverflowPolicy)
+	collector.AddSink(i.telemetrySink(shooterID))
+	shooterContext.UseSampleCollector(collector)
+	for key, value := range i.Tags {
+		shooterContext.SetTag(key, value)
 	}
+	shooterContext.SetTag(telemetry.InjectorTag, i.ID)
+
+	// Injector-wide and test-wide feeders are shared among all the shooters (test-wide ones only hold
+	// the rows assigned to this injector), while per-shooter feeders get their own cursor over the same data
+	shooterFeeders := make([]*feeder.Feeder, len(i.feeders))
+	for index, dataFeeder := range i.feeders {
+		if dataFeeder.Sharing == feeder.PerShooter {
+			shooterFeeders[index] = dataFeeder.Clone()
+		} else {
+			shooterFeeders[index] = dataFeeder
+		}
+	}
+
+	newShooter := &shooter.Shooter{
+		Context:         shooterContext,
+		SetUpScript:     i.SetUpScript,
+		MainScripts:     i.MainScripts,
+		Selector:        i.Selector,
+		TearDownScript:  i.TearDownScript,
+		MaxIterations:   0,
+		Pacing:          i.Pacing,
+		ErrorPolicy:     i.ErrorPolicy,
+		OnAbort:         i.abort,
+		TearDownTimeout: i.TearDownTimeout,
+		Feeders:         shooterFeeders,
+		WaitGroup:       &i.waitGroup,
+	}
+	newShooter.Subscribe(i.trackShooterStatus)
+
+	if i.feedbackWindow != nil {
+		shooterContext.SampleCollector().AddObserver(observeSample(i.feedbackWindow, shooterID))
+	}
+
+	i.streamMutex.Lock()
+	i.streamedShooters = append(i.streamedShooters, newShooter)
+	i.streamMutex.Unlock()
+
+	i.statusMutex.Lock()
+	i.shooterStatuses[shooterID] = newShooter.Status()
+	i.statusMutex.Unlock()
 
 	return newShooter
 }
+
+func (i *Injector) trackShooterStatus(change shooter.StatusChange) {
+	i.Logger.Debug().Str("shooter", change.ShooterID).Str("from", string(change.From)).Str("to", string(change.To)).
+		Msg("Shooter status changed")
+
+	i.statusMutex.Lock()
+	defer i.statusMutex.Unlock()
+
+	// Finished shooters are forgotten, late notifications of their previous changes cannot bring them back
+	if change.To.IsFinal() {
+		delete(i.shooterStatuses, change.ShooterID)
+		return
+	}
+	if _, isTracked := i.shooterStatuses[change.ShooterID]; isTracked {
+		i.shooterStatuses[change.ShooterID] = change.To
+	}
+}
+
+func (i *Injector) abort(err error) {
+	i.Logger.Error().Err(err).Msg("Test aborted by error policy, stopping all shooters")
+
+	// Every remote run replaces the cancel function, so it is read under the same lock
+	i.remoteMutex.Lock()
+	cancelFunc := i.cancelFunc
+	i.remoteMutex.Unlock()
+
+	cancelFunc()
+}
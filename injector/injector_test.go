@@ -0,0 +1,146 @@
+package injector_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type InjectorTestSuite struct {
+	suite.Suite
+	injector *injector.Injector
+}
+
+func (suite *InjectorTestSuite) SetupTest() {
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{})
+}
+
+func (suite *InjectorTestSuite) TestVerifyThresholds() {
+	suite.injector.CheckCounter().Record(telemetry.CheckResult{Name: "logged in", Passed: true})
+	suite.injector.CheckCounter().Record(telemetry.CheckResult{Name: "cart not empty", Passed: false})
+
+	violations := suite.injector.VerifyThresholds([]telemetry.CheckThreshold{
+		{Check: "logged in", MinPassRate: 1},
+		{Check: "cart not empty", MinPassRate: 0.9},
+	})
+
+	if assert.Len(suite.T(), violations, 1) {
+		assert.Equal(suite.T(), "cart not empty", violations[0].(telemetry.ErrThresholdViolated).Check)
+	}
+}
+
+func (suite *InjectorTestSuite) TestShooterStatusTracking() {
+	profile, err := load.ParseLinearRamp(1, "0s", "0s", "1m", "0s")
+	assert.NoError(suite.T(), err)
+
+	suite.injector.AddLoadProfile(profile)
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return ctx.Think(time.Millisecond)
+	}}
+
+	assert.NoError(suite.T(), suite.injector.AdjustScheduling(time.Second))
+	assert.Equal(suite.T(), 1, suite.injector.ActiveShooters())
+	assert.Eventually(suite.T(), func() bool {
+		return suite.hasShooterInStatus(shooter.Running)
+	}, time.Second, 5*time.Millisecond)
+
+	assert.NoError(suite.T(), suite.injector.AdjustScheduling(2*time.Minute))
+	assert.Equal(suite.T(), 0, suite.injector.ActiveShooters())
+
+	// Stopped shooters are not tracked anymore, only their shutdown outcome is kept
+	assert.Eventually(suite.T(), func() bool {
+		return len(suite.injector.ShooterStatuses()) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(suite.T(), func() bool {
+		return len(suite.injector.ShutdownOutcomes()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *InjectorTestSuite) TestStopShootersWithGracePeriod() {
+	profile, err := load.ParseLinearRamp(1, "0s", "0s", "1m", "0s")
+	assert.NoError(suite.T(), err)
+
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	suite.injector.AddLoadProfile(profile)
+	suite.injector.GracePeriod = 20 * time.Millisecond
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-unblock
+		return nil
+	}}
+
+	assert.NoError(suite.T(), suite.injector.AdjustScheduling(time.Second))
+	<-started
+	suite.injector.StopShooters()
+
+	outcomes := suite.injector.ShutdownOutcomes()
+	if assert.Len(suite.T(), outcomes, 1) {
+		for _, outcome := range outcomes {
+			assert.Equal(suite.T(), shooter.Forced, outcome)
+		}
+	}
+	assert.Equal(suite.T(), 0, suite.injector.ActiveShooters())
+}
+
+func (suite *InjectorTestSuite) TestPauseAndResume() {
+	profile, err := load.ParseLinearRamp(1, "0s", "0s", "1m", "0s")
+	assert.NoError(suite.T(), err)
+
+	suite.injector.AddLoadProfile(profile)
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return ctx.Think(time.Millisecond)
+	}}
+
+	suite.injector.Clock().Start()
+	assert.NoError(suite.T(), suite.injector.AdjustScheduling(suite.injector.Elapsed()))
+
+	assert.NoError(suite.T(), suite.injector.Pause())
+	assert.Equal(suite.T(), injector.ErrInvalidPauseState{Paused: true}, suite.injector.Pause())
+	assert.Eventually(suite.T(), func() bool {
+		return suite.hasShooterInStatus(shooter.Paused)
+	}, time.Second, time.Millisecond)
+
+	frozen := suite.injector.Elapsed()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(suite.T(), frozen, suite.injector.Elapsed())
+
+	assert.NoError(suite.T(), suite.injector.Resume())
+	assert.Eventually(suite.T(), func() bool {
+		return suite.hasShooterInStatus(shooter.Running)
+	}, time.Second, time.Millisecond)
+
+	pauses := suite.injector.SampleCollector().Flush()
+	if assert.Len(suite.T(), pauses, 1) {
+		assert.Equal(suite.T(), shooter.PauseEvent, pauses[0].Name())
+		assert.GreaterOrEqual(suite.T(), pauses[0].Duration(), 10*time.Millisecond)
+	}
+
+	suite.injector.StopShooters()
+}
+
+func (suite *InjectorTestSuite) hasShooterInStatus(expected shooter.Status) bool {
+	for _, status := range suite.injector.ShooterStatuses() {
+		if status == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestInjectorTestSuite(t *testing.T) {
+	suite.Run(t, new(InjectorTestSuite))
+}
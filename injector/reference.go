@@ -1,9 +1,19 @@
 package injector
 
+import "github.com/steromano87/harkonnen/project"
+
 type Reference struct {
 	Address string
 	Port    uint16
 	Weight  int
 	Labels  []string
 	Type
+
+	// Bounds of the shooters assigned to the injector, zero means no bound
+	MinShooters int
+	MaxShooters int
+}
+
+func (r Reference) Matches(selector project.LabelSelector) bool {
+	return selector.Matches(r.Labels)
 }
@@ -0,0 +1,394 @@
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/project"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type remoteSetup struct {
+	specs       *project.Specs
+	setUp       shooter.Script
+	tearDown    shooter.Script
+	mainScripts map[string]shooter.Script
+	mainOrder   []string
+	partition   control.PartitionPayload
+	rates       []control.RateShare
+	workdir     string
+	running     bool
+}
+
+func (i *Injector) handleControl(request control.Envelope) (interface{}, error) {
+	switch request.Type {
+	case control.UploadScript:
+		var payload control.ScriptPayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+		return nil, i.uploadScript(payload)
+
+	case control.UploadSpecs:
+		var payload control.SpecsPayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+		return nil, i.uploadSpecs(payload.Content)
+
+	case control.StartTest:
+		return nil, i.startRemoteTest()
+
+	case control.StopTest:
+		return nil, i.stopRemoteTest()
+
+	case control.PauseTest:
+		return nil, i.Pause()
+
+	case control.ResumeTest:
+		return nil, i.Resume()
+
+	case control.OverrideShooter:
+		var payload control.OverridePayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		if payload.Shooters == nil {
+			i.ClearShooterOverride()
+		} else {
+			i.OverrideShooters(*payload.Shooters)
+		}
+		return nil, nil
+
+	case control.AssignPartition:
+		var payload control.PartitionPayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+		return nil, i.assignPartition(payload)
+
+	case control.AssignRates:
+		var payload control.RatesPayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+		return nil, i.assignRates(payload)
+
+	case control.QueryStatus:
+		return i.Status(), nil
+
+	case control.QueryAggregates:
+		// Samples still buffered by the shooters are flushed first, so that the aggregates are up to date
+		i.FlushTelemetry()
+
+		data, err := json.Marshal(i.aggregator.Export())
+		if err != nil {
+			return nil, err
+		}
+		return control.AggregatesPayload{Data: data}, nil
+
+	case control.TelemetryAck:
+		var payload control.TelemetryAckPayload
+		if err := request.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		if i.telemetry != nil {
+			i.telemetry.ack(payload.Sequence)
+		}
+		return nil, nil
+
+	default:
+		return nil, control.ErrUnsupportedMessageType{Type: request.Type}
+	}
+}
+
+func (i *Injector) Status() control.StatusPayload {
+	i.remoteMutex.Lock()
+	running := i.remote.running
+	i.remoteMutex.Unlock()
+
+	shooters := make(map[string]string)
+	for shooterID, status := range i.ShooterStatuses() {
+		shooters[shooterID] = string(status)
+	}
+
+	output := control.StatusPayload{
+		InjectorID:          i.ID,
+		Running:             running,
+		Paused:              i.IsPaused(),
+		Elapsed:             i.Elapsed(),
+		ActiveShooters:      i.ActiveShooters(),
+		Shooters:            shooters,
+		ThresholdViolations: i.ThresholdViolations(),
+	}
+
+	if override, isSet := i.ShooterOverride(); isSet {
+		output.Override = &override
+	}
+
+	return output
+}
+
+func (i *Injector) uploadScript(payload control.ScriptPayload) error {
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.running {
+		return ErrInvalidTestState{Operation: "upload scripts", Running: true}
+	}
+
+	if i.remote.workdir == "" {
+		workdir, err := ioutil.TempDir("", "harkonnen-injector-")
+		if err != nil {
+			return err
+		}
+		i.remote.workdir = workdir
+	}
+
+	// Only the base name is kept, so that a remote cockpit cannot write outside the working directory
+	fileName := filepath.Base(payload.Name)
+	if payload.Name == "" || fileName == "." || fileName == string(filepath.Separator) {
+		return ErrInvalidScriptUpload{Name: payload.Name, Reason: "missing script name"}
+	}
+
+	if payload.Role != control.SetUpRole && payload.Role != control.MainRole && payload.Role != control.TearDownRole {
+		return ErrInvalidScriptUpload{Name: payload.Name, Reason: fmt.Sprintf("unknown role '%s'", payload.Role)}
+	}
+
+	scriptPath := filepath.Join(i.remote.workdir, fileName)
+	if err := ioutil.WriteFile(scriptPath, payload.Content, 0600); err != nil {
+		return err
+	}
+
+	script, err := i.ScriptLoader(scriptPath)
+	if err != nil {
+		return err
+	}
+
+	switch payload.Role {
+	case control.SetUpRole:
+		i.remote.setUp = script
+	case control.TearDownRole:
+		i.remote.tearDown = script
+	case control.MainRole:
+		if i.remote.mainScripts == nil {
+			i.remote.mainScripts = make(map[string]shooter.Script)
+		}
+		if _, isPresent := i.remote.mainScripts[payload.Name]; !isPresent {
+			i.remote.mainOrder = append(i.remote.mainOrder, payload.Name)
+		}
+		i.remote.mainScripts[payload.Name] = script
+	}
+
+	i.Logger.Info().Str("script", payload.Name).Str("role", string(payload.Role)).Msg("Script uploaded")
+	return nil
+}
+
+func (i *Injector) uploadSpecs(content []byte) error {
+	var specs project.Specs
+	if err := yaml.Unmarshal(content, &specs); err != nil {
+		return err
+	}
+
+	if err := specs.Validate(); err != nil {
+		return err
+	}
+
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.running {
+		return ErrInvalidTestState{Operation: "upload specs", Running: true}
+	}
+
+	i.remote.specs = &specs
+	i.Logger.Info().Str("test", specs.Name).Msg("Specs uploaded")
+	return nil
+}
+
+func (i *Injector) assignPartition(payload control.PartitionPayload) error {
+	if payload.Count < 1 || payload.Index < 0 || payload.Index >= payload.Count {
+		return ErrInvalidPartition{Index: payload.Index, Count: payload.Count}
+	}
+
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.running {
+		return ErrInvalidTestState{Operation: "assign partition", Running: true}
+	}
+
+	i.remote.partition = payload
+	return nil
+}
+
+func (i *Injector) assignRates(payload control.RatesPayload) error {
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.running {
+		return ErrInvalidTestState{Operation: "assign rates", Running: true}
+	}
+
+	i.remote.rates = payload.Shares
+	return nil
+}
+
+func (i *Injector) startRemoteTest() error {
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.running {
+		return ErrInvalidTestState{Operation: "start", Running: true}
+	}
+
+	if err := i.applyRemoteSetup(); err != nil {
+		return err
+	}
+
+	// Every run gets a fresh context and check counter, so that a stopped injector can run another test
+	i.Context, i.cancelFunc = context.WithCancel(i.baseContext)
+	i.checkCounter = telemetry.NewCheckCounter()
+	i.clock.Start()
+	i.remote.running = true
+
+	go func() {
+		if err := i.Run(); err != nil && err != context.Canceled {
+			i.Logger.Error().Err(err).Msg("Test run failed")
+		}
+
+		i.remoteMutex.Lock()
+		i.remote.running = false
+		i.remoteMutex.Unlock()
+	}()
+
+	return nil
+}
+
+func (i *Injector) stopRemoteTest() error {
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if !i.remote.running {
+		return ErrInvalidTestState{Operation: "stop", Running: false}
+	}
+
+	i.cancelFunc()
+	return nil
+}
+
+func (i *Injector) applyRemoteSetup() error {
+	mainScripts := make([]shooter.Script, 0, len(i.remote.mainOrder))
+	setUp := i.remote.setUp
+	tearDown := i.remote.tearDown
+
+	if i.remote.specs == nil {
+		for _, name := range i.remote.mainOrder {
+			mainScripts = append(mainScripts, i.remote.mainScripts[name])
+		}
+	} else {
+		// Specs define both the order of the main scripts and their error policies
+		specs := i.remote.specs
+		for _, scriptFile := range specs.Scripts.Main {
+			script, isPresent := i.remote.mainScripts[scriptFile.Name]
+			if !isPresent {
+				return ErrMissingScript{Name: scriptFile.Name}
+			}
+			mainScripts = append(mainScripts, scriptFile.Apply(script))
+		}
+
+		if setUp != nil {
+			setUp = specs.Scripts.SetUp.Apply(setUp)
+		}
+		if tearDown != nil {
+			tearDown = specs.Scripts.TearDown.Apply(tearDown)
+		}
+
+		selector, err := specs.ScriptSelector()
+		if err != nil {
+			return err
+		}
+
+		i.Selector = selector
+		i.Pacing = specs.Pacing
+		i.ErrorPolicy = specs.ErrorPolicy
+		i.Thresholds = specs.Thresholds
+		i.GracePeriod = specs.GracePeriod
+		i.TearDownTimeout = specs.TearDownTimeout
+		i.MaxSpawnRate = specs.MaxSpawnRate
+
+		shares := make(map[int]control.RateShare, len(i.remote.rates))
+		for _, share := range i.remote.rates {
+			shares[share.Ramp] = share
+		}
+
+		i.loadProfiles = nil
+		var scenarios []string
+		for index, ramp := range specs.Ramps {
+			// Scenarios placed on other injectors are not run here
+			if !ramp.Selector.Matches(i.settings.Labels) {
+				continue
+			}
+
+			// Arrival rates split by the cockpit are generated only for the share of this injector
+			profile := ramp.Profile
+			if rateProfile, isRate := profile.(load.RateProfile); isRate {
+				if share, isShared := shares[index]; isShared {
+					profile = sharedRate{RateProfile: rateProfile, fraction: share.Fraction, poolSize: share.PoolSize}
+				}
+			}
+
+			i.AddLoadProfile(profile)
+			scenarios = append(scenarios, ramp.Name)
+		}
+
+		// Shooters are not bound to a scenario, so samples can be tagged only when there is just one
+		if i.Tags == nil {
+			i.Tags = make(map[string]string)
+		}
+		delete(i.Tags, telemetry.ScenarioTag)
+		if len(scenarios) == 1 && scenarios[0] != "" {
+			i.Tags[telemetry.ScenarioTag] = scenarios[0]
+		}
+
+		i.feeders = nil
+		for _, feederSpec := range specs.Feeders {
+			dataFeeder, err := feederSpec.Build()
+			if err != nil {
+				return err
+			}
+
+			// Test-wide feeders are split among the injectors, so that no row is used twice in the same test
+			partition := i.remote.partition
+			if dataFeeder.Sharing == feeder.PerTest && partition.Count > 1 {
+				dataFeeder = dataFeeder.Partition(partition.Index, partition.Count)
+			}
+			i.AddFeeder(dataFeeder)
+		}
+	}
+
+	i.SetUpScript = setUp
+	i.MainScripts = mainScripts
+	i.TearDownScript = tearDown
+	return nil
+}
+
+func (i *Injector) cleanRemoteSetup() {
+	i.remoteMutex.Lock()
+	defer i.remoteMutex.Unlock()
+
+	if i.remote.workdir != "" {
+		_ = os.RemoveAll(i.remote.workdir)
+		i.remote.workdir = ""
+	}
+}
@@ -0,0 +1,105 @@
+package injector
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
+)
+
+const (
+	SchedulingLagMetric  = "scheduling_lag"
+	ShooterDeficitMetric = "shooter_deficit"
+	defaultTickInterval  = 100 * time.Millisecond
+)
+
+func (i *Injector) TotalDuration() time.Duration {
+	var totalDuration time.Duration
+	for _, profile := range i.loadProfiles {
+		if profile.TotalDuration() > totalDuration {
+			totalDuration = profile.TotalDuration()
+		}
+	}
+
+	return totalDuration
+}
+
+// Run follows the load profiles until their end, then waits for all the shooters to complete
+func (i *Injector) Run() error {
+	tickInterval := i.TickInterval
+	if tickInterval <= 0 {
+		tickInterval = defaultTickInterval
+	}
+
+	if !i.clock.Started() {
+		i.clock.Start()
+	}
+
+	// Samples are observed only when a profile needs them, so that the window does not grow unbounded
+	i.feedbackWindow = nil
+	if len(i.feedbackReceivers()) > 0 {
+		feedbackWindow := i.FeedbackWindow
+		if feedbackWindow <= 0 {
+			feedbackWindow = defaultFeedbackWindow
+		}
+		i.feedbackWindow = telemetry.NewWindow(feedbackWindow, telemetry.GenericRecord, telemetry.TransactionRecord)
+	}
+
+	// Samples are forwarded once more after the executors, so that the last ones are aggregated as well
+	defer i.forwardTelemetry()
+	executors := i.startExecutors()
+	defer executors.Wait()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	i.Logger.Info().Msgf("Scheduler started, test will last %s", i.TotalDuration())
+
+	for {
+		select {
+		case <-i.Context.Done():
+			i.Logger.Info().Msg("Context cancelled, stopping scheduler")
+			i.StopShooters()
+			return i.Context.Err()
+
+		case tick := <-ticker.C:
+			i.trackSchedulingLag(tick, tickInterval)
+			i.forwardTelemetry()
+
+			elapsed := i.clock.Elapsed()
+			i.observeFeedback(elapsed)
+			if elapsed >= i.TotalDuration() {
+				i.Logger.Info().Msg("Load profiles completed, waiting for shooters to stop")
+				i.StopShooters()
+				return i.verifyThresholds()
+			}
+
+			if err := i.AdjustScheduling(elapsed); err != nil {
+				i.Logger.Error().Err(err).Msg("Error while adjusting shooters scheduling")
+				i.StopShooters()
+				return err
+			}
+
+			i.trackShooterDeficit(elapsed)
+		}
+	}
+}
+
+func (i *Injector) trackSchedulingLag(tick time.Time, tickInterval time.Duration) {
+	// Ticks are dropped when the loop is slower than the ticker, so a lag longer than the interval means missed ticks
+	lag := time.Since(tick)
+	if lag <= tickInterval {
+		return
+	}
+
+	i.Logger.Warn().Msgf("Scheduler is lagging behind by %s", lag)
+	i.sampleCollector.Collect(telemetry.NewMetricSample(SchedulingLagMetric, tick, lag.Seconds()))
+}
+
+func (i *Injector) trackShooterDeficit(elapsed time.Duration) {
+	deficit := i.ExpectedShooters(elapsed) - i.ActiveShooters()
+	if deficit <= 0 {
+		return
+	}
+
+	i.Logger.Debug().Msgf("Scheduler cannot keep up with the load profiles, %d shooters missing", deficit)
+	i.sampleCollector.Collect(telemetry.NewMetricSample(ShooterDeficitMetric, time.Now(), float64(deficit)))
+}
@@ -0,0 +1,175 @@
+package injector_test
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	injector *injector.Injector
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	suite.injector = injector.New(context.Background(), ioutil.Discard, injector.Settings{})
+	suite.injector.TickInterval = 10 * time.Millisecond
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return ctx.Think(2 * time.Millisecond)
+	}}
+}
+
+func (suite *SchedulerTestSuite) TestRunFollowsProfiles() {
+	firstRamp, _ := load.ParseLinearRamp(4, "0s", "50ms", "150ms", "50ms")
+	secondRamp, _ := load.ParseLinearRamp(2, "0s", "0s", "100ms", "0s")
+	suite.injector.AddLoadProfile(firstRamp)
+	suite.injector.AddLoadProfile(secondRamp)
+
+	assert.Equal(suite.T(), 250*time.Millisecond, suite.injector.TotalDuration())
+
+	start := time.Now()
+	assert.NoError(suite.T(), suite.injector.Run())
+	assert.GreaterOrEqual(suite.T(), time.Since(start), 250*time.Millisecond)
+
+	assert.Equal(suite.T(), 0, suite.injector.ActiveShooters())
+	assert.Empty(suite.T(), suite.injector.ShooterStatuses(), "shooter not completed at the end of the test")
+	assert.GreaterOrEqual(suite.T(), len(suite.injector.ShutdownOutcomes()), 6)
+}
+
+func (suite *SchedulerTestSuite) TestRunWithMaxSpawnRate() {
+	ramp, _ := load.ParseLinearRamp(20, "0s", "0s", "200ms", "0s")
+	suite.injector.AddLoadProfile(ramp)
+	suite.injector.MaxSpawnRate = 25
+
+	assert.NoError(suite.T(), suite.injector.Run())
+
+	// With 25 shooters per second at most, less than 10 shooters can be spawned in 200ms
+	assert.Less(suite.T(), len(suite.injector.ShutdownOutcomes()), 10)
+
+	deficits := 0
+	for _, sample := range suite.injector.SampleCollector().Flush() {
+		if sample.Name() == injector.ShooterDeficitMetric {
+			deficits++
+		}
+	}
+	assert.Greater(suite.T(), deficits, 0)
+}
+
+func (suite *SchedulerTestSuite) TestRunWithArrivalRate() {
+	var iterations int64
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		atomic.AddInt64(&iterations, 1)
+		return nil
+	}}
+
+	arrivalRate, _ := load.ParseArrivalRate(100, "1s", "200ms", 2)
+	suite.injector.AddLoadProfile(arrivalRate)
+	assert.Equal(suite.T(), 0, suite.injector.ExpectedShooters(0), "arrival rates are not followed by closed-loop shooters")
+
+	assert.NoError(suite.T(), suite.injector.Run())
+
+	// Only the pool of the executor is allocated, iterations are started at the given rate
+	assert.InDelta(suite.T(), 20, atomic.LoadInt64(&iterations), 3)
+	assert.Equal(suite.T(), 0, suite.injector.ActiveShooters())
+	assert.Empty(suite.T(), suite.injector.ShooterStatuses(), "pooled shooters are released at the end of the test")
+}
+
+func (suite *SchedulerTestSuite) TestRunFeedsTheAggregator() {
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		end := time.Now()
+		ctx.SampleCollector().Collect(telemetry.NewBaseSample("GET /", end.Add(-time.Millisecond), end, 0, 0))
+		return ctx.Think(2 * time.Millisecond)
+	}}
+
+	constant, _ := load.ParseConstant(2, "0s", "50ms")
+	suite.injector.AddLoadProfile(constant)
+	assert.NoError(suite.T(), suite.injector.Run())
+
+	// Without a cockpit nothing is streamed, the samples are aggregated locally anyway
+	summary, isPresent := suite.injector.Aggregator().Overall()[telemetry.AggregationKey{Name: "GET /"}]
+	if assert.True(suite.T(), isPresent) {
+		assert.Greater(suite.T(), summary.Count, int64(2))
+	}
+}
+
+func (suite *SchedulerTestSuite) TestRunEvaluatesThresholds() {
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		ctx.Check("logged in", true)
+		ctx.Check("cart not empty", false)
+		return ctx.Think(2 * time.Millisecond)
+	}}
+	suite.injector.Thresholds = []telemetry.CheckThreshold{
+		{Check: "logged in", MinPassRate: 1},
+		{Check: "cart not empty", MinPassRate: 0.9},
+	}
+
+	constant, _ := load.ParseConstant(1, "0s", "50ms")
+	suite.injector.AddLoadProfile(constant)
+
+	err := suite.injector.Run()
+	if assert.IsType(suite.T(), injector.ErrFailedThresholds{}, err) {
+		assert.Len(suite.T(), err.(injector.ErrFailedThresholds).Violations, 1)
+	}
+	violations := suite.injector.ThresholdViolations()
+	if assert.Len(suite.T(), violations, 1) {
+		assert.Contains(suite.T(), violations[0], "cart not empty")
+	}
+}
+
+func (suite *SchedulerTestSuite) TestRunStopsWhenFeedbackLimitIsBreached() {
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		end := time.Now()
+		ctx.SampleCollector().Collect(telemetry.NewBaseSample("GET /", end.Add(-50*time.Millisecond), end, 0, 0))
+		return ctx.Think(2 * time.Millisecond)
+	}}
+
+	feedback, _ := load.ParseFeedback(load.StepSearch, 2, 10, "1m")
+	feedback.TargetLatency = 5 * time.Millisecond
+	feedback.MaxLatency = 10 * time.Millisecond
+	suite.injector.AddLoadProfile(feedback)
+
+	start := time.Now()
+	assert.NoError(suite.T(), suite.injector.Run())
+	assert.Less(suite.T(), time.Since(start), 10*time.Second)
+
+	result, stopped := feedback.Result()
+	if assert.True(suite.T(), stopped) {
+		assert.Contains(suite.T(), result.Reason, "exceeded the limit of 10ms")
+	}
+
+	events := 0
+	for _, sample := range suite.injector.SampleCollector().Flush() {
+		if sample.Name() == injector.FeedbackEvent {
+			events++
+		}
+	}
+	assert.Equal(suite.T(), 1, events)
+}
+
+func (suite *SchedulerTestSuite) TestRunStopsOnCancellation() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.injector = injector.New(ctx, ioutil.Discard, injector.Settings{})
+	suite.injector.TickInterval = 10 * time.Millisecond
+	suite.injector.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		return ctx.Think(2 * time.Millisecond)
+	}}
+
+	ramp, _ := load.ParseLinearRamp(2, "0s", "0s", "1m", "0s")
+	suite.injector.AddLoadProfile(ramp)
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	assert.ErrorIs(suite.T(), suite.injector.Run(), context.Canceled)
+	assert.Equal(suite.T(), 0, suite.injector.ActiveShooters())
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
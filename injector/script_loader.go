@@ -0,0 +1,34 @@
+package injector
+
+import (
+	"github.com/steromano87/harkonnen/shooter"
+	"plugin"
+)
+
+const ScriptSymbol = "Script"
+
+type ScriptLoader func(path string) (shooter.Script, error)
+
+// LoadPluginScript opens a script compiled as Go plugin and looks up its exported Script function
+func LoadPluginScript(path string) (shooter.Script, error) {
+	scriptPlugin, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, err := scriptPlugin.Lookup(ScriptSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	switch script := symbol.(type) {
+	case func(shooter.Context) error:
+		return script, nil
+	case *shooter.Script:
+		return *script, nil
+	case *func(shooter.Context) error:
+		return *script, nil
+	default:
+		return nil, ErrInvalidScriptSymbol{Path: path, Symbol: ScriptSymbol}
+	}
+}
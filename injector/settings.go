@@ -1,6 +1,25 @@
 package injector
 
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
+)
+
 type Settings struct {
 	BindAddress string
 	Port        uint
+	Labels      []string
+
+	// Telemetry streaming towards the cockpit
+	FlushInterval  time.Duration
+	BatchSize      int
+	AckTimeout     time.Duration
+	SpoolDirectory string
+
+	// Samples buffered by each shooter between two flushes, Block stalls the scripts until the streamer catches up
+	CollectorCapacity int
+	OverflowPolicy    telemetry.OverflowPolicy
+
+	// Time span of the buckets the streamed samples are aggregated in
+	AggregationBucket time.Duration
 }
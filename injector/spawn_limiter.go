@@ -0,0 +1,37 @@
+package injector
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type spawnLimiter struct {
+	tokens     float64
+	lastRefill time.Time
+	mutex      sync.Mutex
+}
+
+// take returns how many of the requested shooters can be spawned right now without exceeding the given rate
+func (l *spawnLimiter) take(rate float64, requested int) int {
+	if rate <= 0 || requested <= 0 {
+		return requested
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The bucket starts almost empty, so that a test start does not spawn all the shooters at once
+	now := time.Now()
+	burst := math.Max(1, rate)
+	if l.lastRefill.IsZero() {
+		l.tokens = 1
+	} else {
+		l.tokens = math.Min(burst, l.tokens+rate*now.Sub(l.lastRefill).Seconds())
+	}
+	l.lastRefill = now
+
+	granted := int(math.Min(float64(requested), math.Floor(l.tokens)))
+	l.tokens -= float64(granted)
+	return granted
+}
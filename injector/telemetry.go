@@ -0,0 +1,105 @@
+package injector
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
+)
+
+const DroppedSamplesMetric = "dropped_samples"
+
+// FlushTelemetry immediately sends the samples collected so far, without waiting for the next flush interval
+func (i *Injector) FlushTelemetry() {
+	if i.telemetry != nil {
+		i.telemetry.flush()
+	}
+}
+
+// PendingTelemetry returns the amount of telemetry batches not yet acknowledged by the cockpit
+func (i *Injector) PendingTelemetry() int {
+	if i.telemetry == nil {
+		return 0
+	}
+
+	return i.telemetry.pending()
+}
+
+func (i *Injector) collectRecords() []telemetry.Record {
+	// Samples of the injector itself are only streamed, locally they are left to the owner of the injector
+	_ = i.sampleCollector.Forward()
+	i.forwardTelemetry()
+
+	i.recordsMutex.Lock()
+	defer i.recordsMutex.Unlock()
+
+	records := i.records
+	i.records = nil
+	return records
+}
+
+// telemetrySink feeds the aggregator with the samples of a source and, while streaming, queues them for the cockpit
+func (i *Injector) telemetrySink(source string) telemetry.Sink {
+	return telemetry.MultiSink{i.aggregator, telemetry.SinkFunc(func(samples []telemetry.Sample) error {
+		if i.telemetry == nil {
+			return nil
+		}
+
+		i.recordsMutex.Lock()
+		defer i.recordsMutex.Unlock()
+
+		for _, sample := range samples {
+			i.records = append(i.records, telemetry.NewRecord(source, sample))
+		}
+		return nil
+	})}
+}
+
+// forwardTelemetry moves the samples collected by the shooters and the executors to their sinks
+func (i *Injector) forwardTelemetry() {
+	i.streamMutex.Lock()
+	defer i.streamMutex.Unlock()
+
+	activeShooters := i.streamedShooters[:0]
+	for _, streamedShooter := range i.streamedShooters {
+		// Once done, shooters cannot collect any more samples and can be dropped after a last flush
+		finished := false
+		select {
+		case <-streamedShooter.Finished():
+			finished = true
+		default:
+		}
+
+		collector := streamedShooter.SampleCollector()
+		_ = collector.Forward()
+
+		// Only the samples dropped since the previous flush are reported, so that the metric can be summed up
+		if dropped := collector.Dropped(); dropped > i.reportedDrops[streamedShooter.ID()] {
+			metric := telemetry.NewMetricSample(DroppedSamplesMetric, time.Now(), float64(dropped-i.reportedDrops[streamedShooter.ID()]))
+			_ = i.telemetrySink(streamedShooter.ID()).Write([]telemetry.Sample{metric})
+			i.reportedDrops[streamedShooter.ID()] = dropped
+		}
+
+		if finished {
+			delete(i.reportedDrops, streamedShooter.ID())
+		} else {
+			activeShooters = append(activeShooters, streamedShooter)
+		}
+	}
+	i.streamedShooters = activeShooters
+
+	activeExecutors := i.executors[:0]
+	for _, executor := range i.executors {
+		finished := false
+		select {
+		case <-executor.Finished():
+			finished = true
+		default:
+		}
+
+		_ = executor.SampleCollector().Forward()
+
+		if !finished {
+			activeExecutors = append(activeExecutors, executor)
+		}
+	}
+	i.executors = activeExecutors
+}
@@ -0,0 +1,326 @@
+package injector
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/control"
+	"github.com/steromano87/harkonnen/telemetry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFlushInterval = time.Second
+	defaultBatchSize     = 500
+	defaultAckTimeout    = 10 * time.Second
+	spoolFileExtension   = ".batch"
+)
+
+type telemetryPusher interface {
+	Push(messageType control.MessageType, payload interface{}) error
+	Sessions() int
+}
+
+type inflightBatch struct {
+	data   []byte
+	sentAt time.Time
+}
+
+type telemetryStreamer struct {
+	source         string
+	flushInterval  time.Duration
+	batchSize      int
+	ackTimeout     time.Duration
+	spoolDirectory string
+	temporarySpool bool
+
+	pusher    telemetryPusher
+	collect   func() []telemetry.Record
+	logger    zerolog.Logger
+	sequence  uint64
+	inflight  map[uint64]inflightBatch
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	mutex     sync.Mutex
+}
+
+func newTelemetryStreamer(source string, settings Settings, pusher telemetryPusher, collect func() []telemetry.Record, logger zerolog.Logger) (*telemetryStreamer, error) {
+	output := new(telemetryStreamer)
+	output.source = source
+	output.flushInterval = settings.FlushInterval
+	output.batchSize = settings.BatchSize
+	output.ackTimeout = settings.AckTimeout
+	output.spoolDirectory = settings.SpoolDirectory
+	output.pusher = pusher
+	output.collect = collect
+	output.logger = logger.With().Str("component", "Telemetry Streamer").Logger()
+	output.inflight = make(map[uint64]inflightBatch)
+	output.stop = make(chan struct{})
+	output.done = make(chan struct{})
+
+	if output.flushInterval <= 0 {
+		output.flushInterval = defaultFlushInterval
+	}
+	if output.batchSize <= 0 {
+		output.batchSize = defaultBatchSize
+	}
+	if output.ackTimeout <= 0 {
+		output.ackTimeout = defaultAckTimeout
+	}
+	if output.spoolDirectory == "" {
+		output.spoolDirectory, output.temporarySpool = defaultSpoolDirectory(source, settings.Port)
+	}
+
+	if err := os.MkdirAll(output.spoolDirectory, 0700); err != nil {
+		return nil, err
+	}
+
+	// Batches spooled by a previous run keep their sequence numbers, new ones must not clash with them
+	spooled, err := output.spooledSequences()
+	if err != nil {
+		return nil, err
+	}
+	if len(spooled) > 0 {
+		output.sequence = spooled[len(spooled)-1]
+	}
+
+	return output, nil
+}
+
+func (ts *telemetryStreamer) run() {
+	defer close(ts.done)
+
+	ticker := time.NewTicker(ts.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ts.stop:
+			return
+		case <-ticker.C:
+			ts.flush()
+		}
+	}
+}
+
+// close stops the streaming loop and moves all the data not yet acknowledged to disk
+func (ts *telemetryStreamer) close() {
+	ts.closeOnce.Do(ts.closeAndSpool)
+}
+
+func (ts *telemetryStreamer) closeAndSpool() {
+	close(ts.stop)
+	<-ts.done
+
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	for _, batch := range ts.newBatches() {
+		ts.spool(batch.Sequence, batch.data)
+	}
+
+	for sequence, batch := range ts.inflight {
+		ts.spool(sequence, batch.data)
+		delete(ts.inflight, sequence)
+	}
+
+	// Batches never acknowledged stay on disk until a later run delivers them
+	if spooled, _ := ts.spooledSequences(); len(spooled) > 0 {
+		ts.logger.Warn().Msgf("%d telemetry batches never acknowledged, kept in %s", len(spooled), ts.spoolDirectory)
+	} else if ts.temporarySpool {
+		_ = os.Remove(ts.spoolDirectory)
+	}
+}
+
+// defaultSpoolDirectory is bound to the listening port, which identifies the injector across restarts.
+// Injectors listening on a random port have no such identity, so their spool is removed once empty.
+func defaultSpoolDirectory(source string, port uint) (string, bool) {
+	if port != 0 {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("harkonnen-spool-%d", port)), false
+	}
+
+	return filepath.Join(os.TempDir(), "harkonnen-spool-"+source), true
+}
+
+func (ts *telemetryStreamer) flush() {
+	ts.mutex.Lock()
+	ts.expireInflight()
+	connected := ts.pusher.Sessions() > 0
+
+	var outgoing []outgoingBatch
+	if connected {
+		outgoing = ts.spooledBatches()
+	}
+
+	for _, batch := range ts.newBatches() {
+		if !connected {
+			ts.spool(batch.Sequence, batch.data)
+			continue
+		}
+
+		outgoing = append(outgoing, outgoingBatch{sequence: batch.Sequence, data: batch.data})
+	}
+
+	// Batches are tracked before being sent, so that the acknowledgements received in the meantime find them
+	for _, batch := range outgoing {
+		ts.inflight[batch.sequence] = inflightBatch{data: batch.data, sentAt: time.Now()}
+	}
+	ts.mutex.Unlock()
+
+	// Sending can take long on a slow connection, so acknowledgements are not held back by the lock
+	for index, batch := range outgoing {
+		err := ts.pusher.Push(control.TelemetryBatch, control.TelemetryPayload{Sequence: batch.sequence, Data: batch.data})
+		if err == nil {
+			continue
+		}
+
+		ts.logger.Warn().Err(err).Msgf("Cannot send telemetry batch %d, spooling it to disk with the following ones", batch.sequence)
+		ts.mutex.Lock()
+		for _, unsent := range outgoing[index:] {
+			delete(ts.inflight, unsent.sequence)
+			if !unsent.spooled {
+				ts.spool(unsent.sequence, unsent.data)
+			}
+		}
+		ts.mutex.Unlock()
+		return
+	}
+}
+
+func (ts *telemetryStreamer) ack(sequence uint64) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	delete(ts.inflight, sequence)
+	if err := os.Remove(ts.spoolPath(sequence)); err != nil && !os.IsNotExist(err) {
+		ts.logger.Warn().Err(err).Msgf("Cannot remove spooled telemetry batch %d", sequence)
+	}
+}
+
+func (ts *telemetryStreamer) pending() int {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	spooled, _ := ts.spooledSequences()
+	output := len(ts.inflight)
+	for _, sequence := range spooled {
+		if _, isInflight := ts.inflight[sequence]; !isInflight {
+			output++
+		}
+	}
+
+	return output
+}
+
+type encodedBatch struct {
+	telemetry.Batch
+	data []byte
+}
+
+type outgoingBatch struct {
+	sequence uint64
+	data     []byte
+	spooled  bool
+}
+
+func (ts *telemetryStreamer) newBatches() []encodedBatch {
+	records := ts.collect()
+	var output []encodedBatch
+
+	for start := 0; start < len(records); start += ts.batchSize {
+		end := start + ts.batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		ts.sequence++
+		batch := telemetry.Batch{Source: ts.source, Sequence: ts.sequence, Records: records[start:end]}
+		data, err := batch.Encode()
+		if err != nil {
+			ts.logger.Error().Err(err).Msgf("Cannot encode telemetry batch %d, %d records lost", batch.Sequence, len(batch.Records))
+			continue
+		}
+
+		output = append(output, encodedBatch{Batch: batch, data: data})
+	}
+
+	return output
+}
+
+func (ts *telemetryStreamer) expireInflight() {
+	for sequence, batch := range ts.inflight {
+		if time.Since(batch.sentAt) > ts.ackTimeout {
+			ts.logger.Warn().Msgf("Telemetry batch %d not acknowledged within %s, spooling it to disk", sequence, ts.ackTimeout)
+			ts.spool(sequence, batch.data)
+			delete(ts.inflight, sequence)
+		}
+	}
+}
+
+// spooledBatches reads the batches on disk that are not waiting for an acknowledgement already
+func (ts *telemetryStreamer) spooledBatches() []outgoingBatch {
+	spooled, err := ts.spooledSequences()
+	if err != nil {
+		ts.logger.Error().Err(err).Msg("Cannot read telemetry spool")
+		return nil
+	}
+
+	var output []outgoingBatch
+	for _, sequence := range spooled {
+		if _, isInflight := ts.inflight[sequence]; isInflight {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(ts.spoolPath(sequence))
+		if err != nil {
+			ts.logger.Error().Err(err).Msgf("Cannot read spooled telemetry batch %d", sequence)
+			continue
+		}
+
+		// The spool file is removed only when the cockpit acknowledges the batch
+		output = append(output, outgoingBatch{sequence: sequence, data: data, spooled: true})
+	}
+
+	return output
+}
+
+func (ts *telemetryStreamer) spool(sequence uint64, data []byte) {
+	if err := ioutil.WriteFile(ts.spoolPath(sequence), data, 0600); err != nil {
+		ts.logger.Error().Err(err).Msgf("Cannot spool telemetry batch %d, its data is lost", sequence)
+	}
+}
+
+func (ts *telemetryStreamer) spoolPath(sequence uint64) string {
+	return filepath.Join(ts.spoolDirectory, fmt.Sprintf("%020d%s", sequence, spoolFileExtension))
+}
+
+func (ts *telemetryStreamer) spooledSequences() ([]uint64, error) {
+	entries, err := ioutil.ReadDir(ts.spoolDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []uint64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spoolFileExtension) {
+			continue
+		}
+
+		sequence, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), spoolFileExtension), 10, 64)
+		if err == nil {
+			output = append(output, sequence)
+		}
+	}
+
+	sort.Slice(output, func(a, b int) bool {
+		return output[a] < output[b]
+	})
+	return output, nil
+}
@@ -4,5 +4,5 @@ type Type string
 
 const (
 	LocalInjector  Type = "local"
-	RemoteInjector      = "remote"
+	RemoteInjector Type = "remote"
 )
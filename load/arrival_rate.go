@@ -0,0 +1,95 @@
+package load
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+type ArrivalRate struct {
+	Rate                 float64       `yaml:"rate"`
+	TimeUnit             time.Duration `yaml:"time_unit"`
+	Duration             time.Duration `yaml:"duration"`
+	PreAllocatedShooters int           `yaml:"pre_allocated_shooters"`
+}
+
+func ParseArrivalRate(rate float64, timeUnit string, duration string, preAllocatedShooters int) (ArrivalRate, error) {
+	output := new(ArrivalRate)
+	var err error
+
+	output.Rate = rate
+	output.PreAllocatedShooters = preAllocatedShooters
+
+	output.TimeUnit, err = time.ParseDuration(timeUnit)
+	if err != nil {
+		return ArrivalRate{}, err
+	}
+
+	output.Duration, err = time.ParseDuration(duration)
+	if err != nil {
+		return ArrivalRate{}, err
+	}
+
+	return *output, nil
+}
+
+func (r ArrivalRate) At(elapsed time.Duration) int {
+	if elapsed < 0 || elapsed >= r.Duration {
+		return 0
+	}
+
+	return r.PreAllocatedShooters
+}
+
+func (r ArrivalRate) RateAt(elapsed time.Duration) float64 {
+	if elapsed < 0 || elapsed >= r.Duration || r.TimeUnit <= 0 {
+		return 0
+	}
+
+	return r.Rate / r.TimeUnit.Seconds()
+}
+
+func (r ArrivalRate) PoolSize() int {
+	return r.PreAllocatedShooters
+}
+
+func (r ArrivalRate) TotalDuration() time.Duration {
+	return r.Duration
+}
+
+func (r *ArrivalRate) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Rate                 float64 `yaml:"rate"`
+		TimeUnit             string  `yaml:"time_unit"`
+		Duration             string  `yaml:"duration"`
+		PreAllocatedShooters int     `yaml:"pre_allocated_shooters"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	// Rates are expressed per second unless otherwise specified
+	if temp.TimeUnit == "" {
+		temp.TimeUnit = "1s"
+	}
+
+	parsed, err := ParseArrivalRate(temp.Rate, temp.TimeUnit, temp.Duration, temp.PreAllocatedShooters)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+func (r ArrivalRate) Validate() error {
+	checker := checkProfile("arrival_rate")
+	if r.Rate <= 0 {
+		checker.fail("rate", fmt.Sprintf("must be positive, got %g", r.Rate))
+	}
+	checker.positiveDuration("time_unit", r.TimeUnit)
+	checker.positiveDuration("duration", r.Duration)
+	checker.positive("pre_allocated_shooters", r.PreAllocatedShooters)
+	return checker.err
+}
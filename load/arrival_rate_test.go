@@ -0,0 +1,61 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testArrivalRate, _ = load.ParseArrivalRate(300, "1m", "10s", 20)
+
+func TestArrivalRateCreation(t *testing.T) {
+	assert.IsType(t, load.ArrivalRate{}, testArrivalRate)
+	assert.Implements(t, (*load.RateProfile)(nil), testArrivalRate)
+}
+
+func TestArrivalRate_At(t *testing.T) {
+	assert.Equal(t, 0, testArrivalRate.At(-1*time.Second))
+	assert.Equal(t, 20, testArrivalRate.At(5*time.Second))
+	assert.Equal(t, 0, testArrivalRate.At(10*time.Second))
+}
+
+func TestArrivalRate_RateAt(t *testing.T) {
+	assert.Equal(t, float64(0), testArrivalRate.RateAt(-1*time.Second))
+	assert.Equal(t, float64(5), testArrivalRate.RateAt(5*time.Second))
+	assert.Equal(t, float64(0), testArrivalRate.RateAt(12*time.Second))
+}
+
+func TestArrivalRate_PoolSize(t *testing.T) {
+	assert.Equal(t, 20, testArrivalRate.PoolSize())
+}
+
+func TestArrivalRate_TotalDuration(t *testing.T) {
+	assert.Equal(t, 10*time.Second, testArrivalRate.TotalDuration())
+}
+
+func TestArrivalRate_UnmarshalYAML(t *testing.T) {
+	rawProfile := `
+rate: 200
+duration: 1m
+pre_allocated_shooters: 50
+`
+	var outputRate load.ArrivalRate
+	err := yaml.Unmarshal([]byte(rawProfile), &outputRate)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, float64(200), outputRate.Rate)
+		assert.Equal(t, time.Second, outputRate.TimeUnit)
+		assert.Equal(t, time.Minute, outputRate.Duration)
+		assert.Equal(t, 50, outputRate.PreAllocatedShooters)
+		assert.Equal(t, float64(200), outputRate.RateAt(time.Second))
+	}
+}
+
+func TestArrivalRate_UnmarshalYAMLBadDuration(t *testing.T) {
+	var outputRate load.ArrivalRate
+	err := yaml.Unmarshal([]byte("rate: 10\nduration: forever\n"), &outputRate)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package load
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock struct {
+	startTime   time.Time
+	pauseTime   time.Time
+	pausedTotal time.Duration
+	paused      bool
+	mutex       sync.RWMutex
+}
+
+// ClockState is a copy of the clock internals, used to roll back a pause or a resume that could not be completed
+type ClockState struct {
+	startTime   time.Time
+	pauseTime   time.Time
+	pausedTotal time.Duration
+	paused      bool
+}
+
+func NewClock() *Clock {
+	return new(Clock)
+}
+
+func (c *Clock) Start() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.startTime = time.Now()
+	c.pauseTime = time.Time{}
+	c.pausedTotal = 0
+	c.paused = false
+}
+
+func (c *Clock) Started() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return !c.startTime.IsZero()
+}
+
+// Elapsed returns the time passed since the clock start, net of the time spent in pause
+func (c *Clock) Elapsed() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.startTime.IsZero() {
+		return 0
+	}
+
+	now := time.Now()
+	if c.paused {
+		now = c.pauseTime
+	}
+
+	return now.Sub(c.startTime) - c.pausedTotal
+}
+
+func (c *Clock) Pause() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.paused {
+		return false
+	}
+
+	c.paused = true
+	c.pauseTime = time.Now()
+	return true
+}
+
+// Resume restarts the clock and returns the interval spent in pause
+func (c *Clock) Resume() (time.Time, time.Time, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.paused {
+		return time.Time{}, time.Time{}, false
+	}
+
+	resumeTime := time.Now()
+	c.pausedTotal += resumeTime.Sub(c.pauseTime)
+	c.paused = false
+	return c.pauseTime, resumeTime, true
+}
+
+func (c *Clock) IsPaused() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.paused
+}
+
+func (c *Clock) PausedTime() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.paused {
+		return c.pausedTotal + time.Since(c.pauseTime)
+	}
+
+	return c.pausedTotal
+}
+
+func (c *Clock) Snapshot() ClockState {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return ClockState{startTime: c.startTime, pauseTime: c.pauseTime, pausedTotal: c.pausedTotal, paused: c.paused}
+}
+
+func (c *Clock) Restore(state ClockState) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.startTime = state.startTime
+	c.pauseTime = state.pauseTime
+	c.pausedTotal = state.pausedTotal
+	c.paused = state.paused
+}
@@ -0,0 +1,65 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClock_NotStarted(t *testing.T) {
+	clock := load.NewClock()
+
+	assert.False(t, clock.Started())
+	assert.Equal(t, time.Duration(0), clock.Elapsed())
+}
+
+func TestClock_PauseFreezesElapsed(t *testing.T) {
+	clock := load.NewClock()
+	clock.Start()
+	time.Sleep(10 * time.Millisecond)
+
+	assert.True(t, clock.Pause())
+	assert.False(t, clock.Pause(), "clock cannot be paused twice")
+	assert.True(t, clock.IsPaused())
+
+	frozen := clock.Elapsed()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, frozen, clock.Elapsed())
+
+	pauseStart, pauseEnd, resumed := clock.Resume()
+	assert.True(t, resumed)
+	assert.GreaterOrEqual(t, pauseEnd.Sub(pauseStart), 20*time.Millisecond)
+	assert.GreaterOrEqual(t, clock.PausedTime(), 20*time.Millisecond)
+
+	// Elapsed time restarts from the value it had when paused
+	assert.GreaterOrEqual(t, clock.Elapsed(), frozen)
+	assert.Less(t, clock.Elapsed(), frozen+15*time.Millisecond)
+}
+
+func TestClock_ResumeWithoutPause(t *testing.T) {
+	clock := load.NewClock()
+	clock.Start()
+
+	_, _, resumed := clock.Resume()
+	assert.False(t, resumed)
+}
+
+func TestClock_Restore(t *testing.T) {
+	clock := load.NewClock()
+	clock.Start()
+	assert.True(t, clock.Pause())
+	time.Sleep(10 * time.Millisecond)
+
+	snapshot := clock.Snapshot()
+	frozen := clock.Elapsed()
+	_, _, resumed := clock.Resume()
+	assert.True(t, resumed)
+	time.Sleep(10 * time.Millisecond)
+
+	// The clock goes back to its paused state, as if it had never been resumed
+	clock.Restore(snapshot)
+	assert.True(t, clock.IsPaused())
+	assert.Equal(t, frozen, clock.Elapsed())
+	assert.GreaterOrEqual(t, clock.PausedTime(), 20*time.Millisecond)
+}
@@ -0,0 +1,80 @@
+package load
+
+import (
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+type Constant struct {
+	Shooters     int           `yaml:"shooters"`
+	InitialDelay time.Duration `yaml:"initial_delay"`
+	Duration     time.Duration `yaml:"duration"`
+}
+
+func ParseConstant(shooters int, initialDelay string, duration string) (Constant, error) {
+	output := new(Constant)
+	var err error
+
+	output.Shooters = shooters
+
+	output.InitialDelay, err = parseOptionalDuration(initialDelay)
+	if err != nil {
+		return Constant{}, err
+	}
+
+	output.Duration, err = time.ParseDuration(duration)
+	if err != nil {
+		return Constant{}, err
+	}
+
+	return *output, nil
+}
+
+func (c Constant) At(elapsed time.Duration) int {
+	if elapsed < c.InitialDelay || elapsed >= c.TotalDuration() {
+		return 0
+	}
+
+	return c.Shooters
+}
+
+func (c Constant) TotalDuration() time.Duration {
+	return c.InitialDelay + c.Duration
+}
+
+func (c *Constant) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Shooters     int    `yaml:"shooters"`
+		InitialDelay string `yaml:"initial_delay"`
+		Duration     string `yaml:"duration"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	parsed, err := ParseConstant(temp.Shooters, temp.InitialDelay, temp.Duration)
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+func parseOptionalDuration(raw string) (time.Duration, error) {
+	// Delays and offsets can be omitted, meaning no delay at all
+	if raw == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(raw)
+}
+
+func (c Constant) Validate() error {
+	checker := checkProfile("constant")
+	checker.positive("shooters", c.Shooters)
+	checker.nonNegativeDuration("initial_delay", c.InitialDelay)
+	checker.positiveDuration("duration", c.Duration)
+	return checker.err
+}
@@ -0,0 +1,57 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testConstant, _ = load.ParseConstant(12, "2s", "10s")
+
+func TestConstantCreation(t *testing.T) {
+	assert.IsType(t, load.Constant{}, testConstant)
+	assert.Implements(t, (*load.Profile)(nil), testConstant)
+
+	_, err := load.ParseConstant(12, "2s", "forever")
+	assert.Error(t, err)
+}
+
+func TestConstant_At(t *testing.T) {
+	assert.Equal(t, 0, testConstant.At(-1*time.Second))
+	assert.Equal(t, 0, testConstant.At(1*time.Second))
+	assert.Equal(t, 12, testConstant.At(2*time.Second))
+	assert.Equal(t, 12, testConstant.At(11*time.Second))
+	assert.Equal(t, 0, testConstant.At(12*time.Second))
+}
+
+func TestConstant_TotalDuration(t *testing.T) {
+	assert.Equal(t, 12*time.Second, testConstant.TotalDuration())
+}
+
+func TestConstant_Marshalling(t *testing.T) {
+	output, err := yaml.Marshal(testConstant)
+
+	if assert.NoError(t, err) {
+		var outputConstant load.Constant
+		assert.NoError(t, yaml.Unmarshal(output, &outputConstant))
+		assert.Equal(t, testConstant, outputConstant)
+	}
+}
+
+func TestConstant_UnmarshalYAML(t *testing.T) {
+	var constant load.Constant
+	err := yaml.Unmarshal([]byte(`
+shooters: 5
+duration: 1m
+`), &constant)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5, constant.Shooters)
+		assert.Equal(t, time.Duration(0), constant.InitialDelay)
+		assert.Equal(t, time.Minute, constant.Duration)
+	}
+
+	assert.Error(t, yaml.Unmarshal([]byte(`shooters: 5`), &constant), "duration is mandatory")
+}
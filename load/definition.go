@@ -0,0 +1,75 @@
+package load
+
+import (
+	"gopkg.in/yaml.v3"
+	"reflect"
+)
+
+// Definition wraps a profile whose implementation is chosen by the "type" field of its YAML representation
+type Definition struct {
+	Profile
+}
+
+func (d *Definition) UnmarshalYAML(value *yaml.Node) error {
+	var discriminator struct {
+		Type string `yaml:"type"`
+	}
+
+	if err := value.Decode(&discriminator); err != nil {
+		return err
+	}
+
+	if discriminator.Type == "" {
+		discriminator.Type = DefaultProfileType
+	}
+
+	target, profile, isRegistered := newRegisteredProfile(discriminator.Type)
+	if !isRegistered {
+		return ErrUnknownProfileType{Type: discriminator.Type}
+	}
+
+	if err := value.Decode(target); err != nil {
+		return err
+	}
+
+	d.Profile = profile()
+	return nil
+}
+
+func (d Definition) MarshalYAML() (interface{}, error) {
+	profileType, isRegistered := registeredName(d.Profile)
+	if !isRegistered {
+		return nil, ErrUnknownProfileType{Type: reflect.TypeOf(d.Profile).String()}
+	}
+
+	// The profile is encoded on its own first, so that the type can be added to its fields
+	encoded, err := yaml.Marshal(d.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(encoded, &document); err != nil {
+		return nil, err
+	}
+	node := document.Content[0]
+
+	typeKey := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "type"}
+	typeValue := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: profileType}
+	node.Content = append([]*yaml.Node{typeKey, typeValue}, node.Content...)
+
+	return node, nil
+}
+
+func definitionsOf(profiles []Profile) []Definition {
+	output := make([]Definition, len(profiles))
+	for index, profile := range profiles {
+		output[index] = Definition{Profile: profile}
+	}
+
+	return output
+}
+
+func (d Definition) Validate() error {
+	return Validate(d.Profile)
+}
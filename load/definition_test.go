@@ -0,0 +1,159 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+type customProfile struct {
+	Shooters int `yaml:"shooters"`
+}
+
+func (c customProfile) At(elapsed time.Duration) int {
+	return c.Shooters
+}
+
+func (c customProfile) TotalDuration() time.Duration {
+	return time.Minute
+}
+
+func TestDefinition_UnmarshalYAML(t *testing.T) {
+	var definitions []load.Definition
+	err := yaml.Unmarshal([]byte(`
+- shooters: 10
+  initial_delay: 0s
+  ramp_up_time: 10s
+  sustain_time: 1m
+  ramp_down_time: 10s
+- type: constant
+  shooters: 5
+  duration: 1m
+- type: stair_step
+  steps: 3
+  shooters_per_step: 2
+  step_duration: 10s
+- type: spike
+  baseline: 1
+  peak: 10
+  duration: 1m
+  spike_duration: 5s
+- type: sine
+  min: 1
+  max: 5
+  period: 1m
+  duration: 2m
+- type: piecewise
+  points:
+    - [0s, 0]
+    - [1m, 10]
+- type: arrival_rate
+  rate: 10
+  duration: 1m
+  pre_allocated_shooters: 4
+`), &definitions)
+
+	if assert.NoError(t, err) && assert.Len(t, definitions, 7) {
+		assert.IsType(t, load.LinearRamp{}, definitions[0].Profile, "profiles are linear ramps unless otherwise specified")
+		assert.IsType(t, load.Constant{}, definitions[1].Profile)
+		assert.IsType(t, load.StairStep{}, definitions[2].Profile)
+		assert.IsType(t, load.Spike{}, definitions[3].Profile)
+		assert.IsType(t, load.Sine{}, definitions[4].Profile)
+		assert.IsType(t, load.Piecewise{}, definitions[5].Profile)
+		assert.Implements(t, (*load.RateProfile)(nil), definitions[6].Profile)
+
+		assert.Equal(t, 5, definitions[1].At(30*time.Second), "definitions can be used as profiles")
+	}
+}
+
+func TestDefinition_UnknownType(t *testing.T) {
+	var definition load.Definition
+	err := yaml.Unmarshal([]byte(`type: zigzag`), &definition)
+	assert.Equal(t, load.ErrUnknownProfileType{Type: "zigzag"}, err)
+}
+
+func TestDefinition_CustomType(t *testing.T) {
+	load.Register("custom", customProfile{})
+	assert.Contains(t, load.RegisteredTypes(), "custom")
+
+	var definition load.Definition
+	err := yaml.Unmarshal([]byte(`
+type: custom
+shooters: 7
+`), &definition)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, customProfile{Shooters: 7}, definition.Profile)
+	}
+
+	output, err := yaml.Marshal(definition)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(output), "type: custom")
+	}
+}
+
+func TestDefinition_Composition(t *testing.T) {
+	var definition load.Definition
+	err := yaml.Unmarshal([]byte(`
+type: sequence
+profiles:
+  - type: constant
+    shooters: 5
+    duration: 1m
+  - type: sum
+    profiles:
+      - type: scale
+        factor: 2
+        profile:
+          type: constant
+          shooters: 5
+          duration: 1m
+      - type: offset
+        delay: 30s
+        profile:
+          type: repeat
+          times: 2
+          profile:
+            type: stair_step
+            steps: 2
+            shooters_per_step: 1
+            step_duration: 10s
+`), &definition)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2*time.Minute+10*time.Second, definition.TotalDuration())
+		assert.Equal(t, 5, definition.At(30*time.Second))
+		assert.Equal(t, 10, definition.At(time.Minute))
+		assert.Equal(t, 11, definition.At(time.Minute+30*time.Second))
+		assert.Equal(t, 12, definition.At(time.Minute+40*time.Second))
+		assert.Equal(t, 11, definition.At(time.Minute+50*time.Second))
+		assert.Equal(t, 2, definition.At(2*time.Minute))
+		assert.Equal(t, 0, definition.At(2*time.Minute+10*time.Second))
+	}
+}
+
+func TestDefinition_Marshalling(t *testing.T) {
+	constant, _ := load.ParseConstant(5, "", "1m")
+	original := load.Definition{Profile: load.NewSequence(
+		testRamp,
+		load.NewOffset(10*time.Second, load.NewScale(1.5, constant)),
+		load.NewRepeat(3, testPiecewise),
+	)}
+
+	output, err := yaml.Marshal(original)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(output), "type: sequence")
+		assert.Contains(t, string(output), "type: offset")
+
+		var decoded load.Definition
+		assert.NoError(t, yaml.Unmarshal(output, &decoded))
+		assert.Equal(t, original, decoded)
+	}
+}
+
+func TestDefinition_MarshalUnregistered(t *testing.T) {
+	_, err := yaml.Marshal(load.Definition{Profile: &customProfile{}})
+	assert.IsType(t, load.ErrUnknownProfileType{}, err)
+}
@@ -0,0 +1,12 @@
+package load
+
+import "fmt"
+
+type ErrInvalidPoint struct {
+	Line   int
+	Reason string
+}
+
+func (ip ErrInvalidPoint) Error() string {
+	return fmt.Sprintf("invalid profile point at line %d: %s", ip.Line, ip.Reason)
+}
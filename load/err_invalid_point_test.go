@@ -0,0 +1,15 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidPoint_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		load.ErrInvalidPoint{Line: 4, Reason: "expected a time and an amount of shooters"},
+		"invalid profile point at line 4: expected a time and an amount of shooters",
+		"Wrong error message format")
+}
@@ -0,0 +1,13 @@
+package load
+
+import "fmt"
+
+type ErrInvalidProfile struct {
+	Profile string
+	Field   string
+	Reason  string
+}
+
+func (ip ErrInvalidProfile) Error() string {
+	return fmt.Sprintf("invalid %s profile: %s %s", ip.Profile, ip.Field, ip.Reason)
+}
@@ -0,0 +1,15 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidProfile_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		load.ErrInvalidProfile{Profile: "linear", Field: "shooters", Reason: "must be positive"},
+		"invalid linear profile: shooters must be positive",
+		"Wrong error message format")
+}
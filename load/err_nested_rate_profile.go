@@ -0,0 +1,12 @@
+package load
+
+import "fmt"
+
+type ErrNestedRateProfile struct {
+	Profile string
+	Field   string
+}
+
+func (nr ErrNestedRateProfile) Error() string {
+	return fmt.Sprintf("invalid %s profile: %s cannot be an arrival rate, which is supported only as a whole ramp", nr.Profile, nr.Field)
+}
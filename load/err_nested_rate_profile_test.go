@@ -0,0 +1,15 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrNestedRateProfile_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		load.ErrNestedRateProfile{Profile: "sum", Field: "profiles[1]"},
+		"invalid sum profile: profiles[1] cannot be an arrival rate, which is supported only as a whole ramp",
+		"Wrong error message format")
+}
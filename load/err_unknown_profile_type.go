@@ -0,0 +1,11 @@
+package load
+
+import "fmt"
+
+type ErrUnknownProfileType struct {
+	Type string
+}
+
+func (upt ErrUnknownProfileType) Error() string {
+	return fmt.Sprintf("unknown load profile type '%s'", upt.Type)
+}
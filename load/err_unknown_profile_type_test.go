@@ -0,0 +1,15 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnknownProfileType_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		load.ErrUnknownProfileType{Type: "zigzag"},
+		"unknown load profile type 'zigzag'",
+		"Wrong error message format")
+}
@@ -0,0 +1,414 @@
+package load
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math"
+	"sync"
+	"time"
+)
+
+type FeedbackStrategy string
+
+const (
+	StepSearch FeedbackStrategy = "step"
+	PIDControl FeedbackStrategy = "pid"
+)
+
+const (
+	defaultAdjustInterval   = 10 * time.Second
+	defaultProportionalGain = 0.5
+	defaultIntegralGain     = 0.1
+)
+
+// Observation is the aggregated view of the system under test at a given moment of the test
+type Observation struct {
+	Elapsed    time.Duration
+	Samples    int
+	Throughput float64
+	Latency    time.Duration
+	ErrorRate  float64
+}
+
+// FeedbackReceiver is implemented by the profiles whose shooters depend on the live telemetry
+type FeedbackReceiver interface {
+	Profile
+	Observe(observation Observation)
+	Done() <-chan struct{}
+	Result() (FeedbackResult, bool)
+}
+
+// FeedbackReceivers finds the goal-seeking profiles a profile is made of, looking into the composite profiles as well.
+// Receivers nested in sequences and offsets only observe the test while they run, on their own elapsed time.
+func FeedbackReceivers(profile Profile) []FeedbackReceiver {
+	switch typed := profile.(type) {
+	case FeedbackReceiver:
+		return []FeedbackReceiver{typed}
+	case Definition:
+		return FeedbackReceivers(typed.Profile)
+	case Scale:
+		return FeedbackReceivers(typed.Profile.Profile)
+	case Repeat:
+		return FeedbackReceivers(typed.Profile.Profile)
+	case Offset:
+		return shiftReceivers(FeedbackReceivers(typed.Profile.Profile), typed.Delay, typed.TotalDuration())
+	case Sum:
+		var output []FeedbackReceiver
+		for _, nested := range typed.Profiles {
+			output = append(output, FeedbackReceivers(nested.Profile)...)
+		}
+		return output
+	case Sequence:
+		var output []FeedbackReceiver
+		var start time.Duration
+		for _, nested := range typed.Profiles {
+			end := start + nested.TotalDuration()
+			output = append(output, shiftReceivers(FeedbackReceivers(nested.Profile), start, end)...)
+			start = end
+		}
+		return output
+	default:
+		return nil
+	}
+}
+
+func shiftReceivers(receivers []FeedbackReceiver, start time.Duration, end time.Duration) []FeedbackReceiver {
+	output := make([]FeedbackReceiver, 0, len(receivers))
+	for _, receiver := range receivers {
+		output = append(output, shiftedReceiver{FeedbackReceiver: receiver, start: start, end: end})
+	}
+
+	return output
+}
+
+// shiftedReceiver is a receiver running from the start to the end of the test elapsed time
+type shiftedReceiver struct {
+	FeedbackReceiver
+	start time.Duration
+	end   time.Duration
+}
+
+func (sr shiftedReceiver) Observe(observation Observation) {
+	if observation.Elapsed < sr.start || observation.Elapsed >= sr.end {
+		return
+	}
+
+	observation.Elapsed -= sr.start
+	sr.FeedbackReceiver.Observe(observation)
+}
+
+type FeedbackResult struct {
+	Capacity    int
+	StoppedAt   time.Duration
+	Reason      string
+	Observation Observation
+}
+
+// Feedback changes the amount of shooters to hold a target throughput or latency,
+// stopping the test as soon as the latency or the error rate exceed their limits.
+type Feedback struct {
+	Strategy         FeedbackStrategy `yaml:"strategy,omitempty"`
+	TargetThroughput float64          `yaml:"target_throughput,omitempty"`
+	TargetLatency    time.Duration    `yaml:"target_latency,omitempty"`
+	MaxLatency       time.Duration    `yaml:"max_latency,omitempty"`
+	MaxErrorRate     float64          `yaml:"max_error_rate,omitempty"`
+	InitialShooters  int              `yaml:"initial_shooters"`
+	MinShooters      int              `yaml:"min_shooters,omitempty"`
+	MaxShooters      int              `yaml:"max_shooters"`
+	StepSize         int              `yaml:"step_size,omitempty"`
+	AdjustInterval   time.Duration    `yaml:"adjust_interval,omitempty"`
+	MinSamples       int              `yaml:"min_samples,omitempty"`
+	ProportionalGain float64          `yaml:"proportional_gain,omitempty"`
+	IntegralGain     float64          `yaml:"integral_gain,omitempty"`
+	DerivativeGain   float64          `yaml:"derivative_gain,omitempty"`
+	Duration         time.Duration    `yaml:"duration"`
+
+	shooters      int
+	step          int
+	initialized   bool
+	capacity      int
+	lastAdjust    time.Duration
+	lastDirection int
+	integral      float64
+	lastError     float64
+	result        *FeedbackResult
+	done          chan struct{}
+	mutex         sync.Mutex
+}
+
+// ParseFeedback creates a goal-seeking profile, targets and limits are then set on the returned profile
+func ParseFeedback(strategy FeedbackStrategy, initialShooters int, maxShooters int, duration string) (*Feedback, error) {
+	output := new(Feedback)
+	var err error
+
+	output.Strategy = strategy
+	output.InitialShooters = initialShooters
+	output.MaxShooters = maxShooters
+
+	output.Duration, err = time.ParseDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (f *Feedback) At(elapsed time.Duration) int {
+	if elapsed < 0 || elapsed >= f.TotalDuration() {
+		return 0
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.initialize()
+	return f.shooters
+}
+
+// TotalDuration shrinks to the moment a limit was breached, so that the test ends there
+func (f *Feedback) TotalDuration() time.Duration {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.result != nil {
+		return f.result.StoppedAt
+	}
+
+	return f.Duration
+}
+
+func (f *Feedback) Observe(observation Observation) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.initialize()
+	if f.result != nil || observation.Samples < f.MinSamples || observation.Samples == 0 {
+		return
+	}
+
+	if reason := f.breachedLimit(observation); reason != "" {
+		f.result = &FeedbackResult{
+			Capacity:    f.capacity,
+			StoppedAt:   observation.Elapsed,
+			Reason:      reason,
+			Observation: observation,
+		}
+		close(f.done)
+		return
+	}
+
+	// The current amount of shooters is within the limits, so the system can sustain at least as many
+	if f.shooters > f.capacity {
+		f.capacity = f.shooters
+	}
+
+	if observation.Elapsed-f.lastAdjust < f.adjustInterval() {
+		return
+	}
+
+	if f.Strategy == PIDControl {
+		f.adjustWithPID(observation)
+	} else {
+		f.adjustWithStepSearch(observation)
+	}
+	f.lastAdjust = observation.Elapsed
+}
+
+func (f *Feedback) Done() <-chan struct{} {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.initialize()
+	return f.done
+}
+
+// Result returns the highest amount of shooters found within the limits, the flag tells whether a limit stopped the search
+func (f *Feedback) Result() (FeedbackResult, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.result != nil {
+		return *f.result, true
+	}
+
+	return FeedbackResult{Capacity: f.capacity}, false
+}
+
+func (f *Feedback) Current() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.initialize()
+	return f.shooters
+}
+
+func (f *Feedback) initialize() {
+	if f.initialized {
+		return
+	}
+
+	f.shooters = f.clamp(f.InitialShooters)
+	f.done = make(chan struct{})
+	f.initialized = true
+}
+
+func (f *Feedback) breachedLimit(observation Observation) string {
+	if f.MaxLatency > 0 && observation.Latency > f.MaxLatency {
+		return fmt.Sprintf("latency %s exceeded the limit of %s", observation.Latency, f.MaxLatency)
+	}
+
+	if f.MaxErrorRate > 0 && observation.ErrorRate > f.MaxErrorRate {
+		return fmt.Sprintf("error rate %.2f%% exceeded the limit of %.2f%%", observation.ErrorRate*100, f.MaxErrorRate*100)
+	}
+
+	return ""
+}
+
+// relativeError is positive when the system is below the target, i.e. when there is room for more shooters
+func (f *Feedback) relativeError(observation Observation) float64 {
+	if f.TargetLatency > 0 {
+		return float64(f.TargetLatency-observation.Latency) / float64(f.TargetLatency)
+	}
+
+	return (f.TargetThroughput - observation.Throughput) / f.TargetThroughput
+}
+
+func (f *Feedback) adjustWithStepSearch(observation Observation) {
+	direction := 1
+	if f.relativeError(observation) < 0 {
+		direction = -1
+	}
+
+	// Every change of direction halves the step, so that the search converges around the target
+	if f.step <= 0 {
+		f.step = f.StepSize
+	}
+	if f.step <= 0 {
+		f.step = int(math.Max(1, float64(f.MaxShooters)/10))
+	}
+	if f.lastDirection != 0 && direction != f.lastDirection {
+		f.step = int(math.Max(1, float64(f.step)/2))
+	}
+
+	f.lastDirection = direction
+	f.shooters = f.clamp(f.shooters + direction*f.step)
+}
+
+func (f *Feedback) adjustWithPID(observation Observation) {
+	proportionalGain := f.ProportionalGain
+	integralGain := f.IntegralGain
+	if proportionalGain == 0 && integralGain == 0 && f.DerivativeGain == 0 {
+		proportionalGain = defaultProportionalGain
+		integralGain = defaultIntegralGain
+	}
+
+	interval := (observation.Elapsed - f.lastAdjust).Seconds()
+	currentError := f.relativeError(observation)
+	f.integral += currentError * interval
+
+	derivative := 0.0
+	if interval > 0 {
+		derivative = (currentError - f.lastError) / interval
+	}
+	f.lastError = currentError
+
+	output := proportionalGain*currentError + integralGain*f.integral + f.DerivativeGain*derivative
+	f.shooters = f.clamp(f.InitialShooters + int(math.Round(output*float64(f.MaxShooters))))
+}
+
+func (f *Feedback) clamp(shooters int) int {
+	if shooters < f.MinShooters {
+		shooters = f.MinShooters
+	}
+	if f.MaxShooters > 0 && shooters > f.MaxShooters {
+		shooters = f.MaxShooters
+	}
+	if shooters < 1 {
+		shooters = 1
+	}
+
+	return shooters
+}
+
+func (f *Feedback) adjustInterval() time.Duration {
+	if f.AdjustInterval <= 0 {
+		return defaultAdjustInterval
+	}
+
+	return f.AdjustInterval
+}
+
+func (f *Feedback) Validate() error {
+	checker := checkProfile("feedback")
+	if f.Strategy != "" && f.Strategy != StepSearch && f.Strategy != PIDControl {
+		checker.fail("strategy", fmt.Sprintf("must be either '%s' or '%s', got '%s'", StepSearch, PIDControl, f.Strategy))
+	}
+	if (f.TargetLatency > 0) == (f.TargetThroughput > 0) {
+		checker.fail("target", "must be either a throughput or a latency")
+	}
+	if f.MaxErrorRate < 0 || f.MaxErrorRate > 1 {
+		checker.fail("max_error_rate", fmt.Sprintf("must be between 0 and 1, got %g", f.MaxErrorRate))
+	}
+	checker.nonNegativeDuration("max_latency", f.MaxLatency)
+	checker.positive("initial_shooters", f.InitialShooters)
+	checker.nonNegative("min_shooters", f.MinShooters)
+	checker.positive("max_shooters", f.MaxShooters)
+	if f.MaxShooters < f.MinShooters {
+		checker.fail("max_shooters", fmt.Sprintf("cannot be lower than min_shooters (%d)", f.MinShooters))
+	}
+	checker.nonNegative("step_size", f.StepSize)
+	checker.nonNegativeDuration("adjust_interval", f.AdjustInterval)
+	checker.positiveDuration("duration", f.Duration)
+	return checker.err
+}
+
+func (f *Feedback) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Strategy         FeedbackStrategy `yaml:"strategy"`
+		TargetThroughput float64          `yaml:"target_throughput"`
+		TargetLatency    string           `yaml:"target_latency"`
+		MaxLatency       string           `yaml:"max_latency"`
+		MaxErrorRate     float64          `yaml:"max_error_rate"`
+		InitialShooters  int              `yaml:"initial_shooters"`
+		MinShooters      int              `yaml:"min_shooters"`
+		MaxShooters      int              `yaml:"max_shooters"`
+		StepSize         int              `yaml:"step_size"`
+		AdjustInterval   string           `yaml:"adjust_interval"`
+		MinSamples       int              `yaml:"min_samples"`
+		ProportionalGain float64          `yaml:"proportional_gain"`
+		IntegralGain     float64          `yaml:"integral_gain"`
+		DerivativeGain   float64          `yaml:"derivative_gain"`
+		Duration         string           `yaml:"duration"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	var err error
+	if f.TargetLatency, err = parseOptionalDuration(temp.TargetLatency); err != nil {
+		return err
+	}
+	if f.MaxLatency, err = parseOptionalDuration(temp.MaxLatency); err != nil {
+		return err
+	}
+	if f.AdjustInterval, err = parseOptionalDuration(temp.AdjustInterval); err != nil {
+		return err
+	}
+	if f.Duration, err = time.ParseDuration(temp.Duration); err != nil {
+		return err
+	}
+
+	f.Strategy = temp.Strategy
+	f.TargetThroughput = temp.TargetThroughput
+	f.MaxErrorRate = temp.MaxErrorRate
+	f.InitialShooters = temp.InitialShooters
+	f.MinShooters = temp.MinShooters
+	f.MaxShooters = temp.MaxShooters
+	f.StepSize = temp.StepSize
+	f.MinSamples = temp.MinSamples
+	f.ProportionalGain = temp.ProportionalGain
+	f.IntegralGain = temp.IntegralGain
+	f.DerivativeGain = temp.DerivativeGain
+	return nil
+}
@@ -0,0 +1,238 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+func newStepSearch(t *testing.T) *load.Feedback {
+	feedback, err := load.ParseFeedback(load.StepSearch, 10, 100, "10m")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	feedback.TargetLatency = 500 * time.Millisecond
+	feedback.MaxLatency = 800 * time.Millisecond
+	feedback.MaxErrorRate = 0.05
+	feedback.StepSize = 8
+	feedback.AdjustInterval = 10 * time.Second
+	return feedback
+}
+
+func TestFeedbackCreation(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	assert.Implements(t, (*load.Profile)(nil), feedback)
+	assert.Implements(t, (*load.FeedbackReceiver)(nil), feedback)
+	assert.NoError(t, load.Validate(feedback))
+
+	_, err := load.ParseFeedback(load.StepSearch, 10, 100, "forever")
+	assert.Error(t, err)
+}
+
+func TestFeedback_At(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	assert.Equal(t, 0, feedback.At(-1*time.Second))
+	assert.Equal(t, 10, feedback.At(0))
+	assert.Equal(t, 10, feedback.At(5*time.Minute))
+	assert.Equal(t, 0, feedback.At(10*time.Minute))
+	assert.Equal(t, 10*time.Minute, feedback.TotalDuration())
+}
+
+func TestFeedback_StepSearchIncreasesBelowTarget(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 100, Latency: 200 * time.Millisecond})
+	assert.Equal(t, 18, feedback.Current())
+
+	// Adjustments are not made before the interval has passed
+	feedback.Observe(load.Observation{Elapsed: 15 * time.Second, Samples: 100, Latency: 200 * time.Millisecond})
+	assert.Equal(t, 18, feedback.Current())
+
+	feedback.Observe(load.Observation{Elapsed: 20 * time.Second, Samples: 100, Latency: 300 * time.Millisecond})
+	assert.Equal(t, 26, feedback.At(20*time.Second))
+}
+
+func TestFeedback_StepSearchConverges(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 100, Latency: 200 * time.Millisecond})
+	assert.Equal(t, 18, feedback.Current())
+
+	// Overshooting the target reverses the direction and halves the step
+	feedback.Observe(load.Observation{Elapsed: 20 * time.Second, Samples: 100, Latency: 600 * time.Millisecond})
+	assert.Equal(t, 14, feedback.Current())
+
+	feedback.Observe(load.Observation{Elapsed: 30 * time.Second, Samples: 100, Latency: 400 * time.Millisecond})
+	assert.Equal(t, 16, feedback.Current())
+
+	// The configured step is kept, so that the profile can be reused and serialized as it was defined
+	assert.Equal(t, 8, feedback.StepSize)
+}
+
+func TestFeedbackReceivers(t *testing.T) {
+	warmUp, _ := load.ParseConstant(5, "0s", "30s")
+	feedback := newStepSearch(t)
+
+	assert.Equal(t, []load.FeedbackReceiver{feedback}, load.FeedbackReceivers(feedback))
+	assert.Empty(t, load.FeedbackReceivers(warmUp))
+
+	for name, profile := range map[string]load.Profile{
+		"sum":    load.NewSum(warmUp, feedback),
+		"scale":  load.NewScale(2, feedback),
+		"repeat": load.NewRepeat(2, feedback),
+		"nested": load.NewScale(2, load.NewSum(warmUp, load.NewRepeat(1, feedback))),
+	} {
+		receivers := load.FeedbackReceivers(profile)
+		if assert.Len(t, receivers, 1, name) {
+			assert.Same(t, feedback, receivers[0], name)
+		}
+	}
+}
+
+func TestFeedbackReceivers_ObserveOnTheirOwnTime(t *testing.T) {
+	warmUp, _ := load.ParseConstant(5, "0s", "30s")
+	feedback := newStepSearch(t)
+
+	receivers := load.FeedbackReceivers(load.NewSequence(warmUp, load.NewOffset(10*time.Second, feedback)))
+	if !assert.Len(t, receivers, 1) {
+		t.FailNow()
+	}
+
+	// The warm-up and the delay of the offset are not observed by the feedback profile
+	receivers[0].Observe(load.Observation{Elapsed: 35 * time.Second, Samples: 100, Latency: 900 * time.Millisecond})
+	_, stopped := receivers[0].Result()
+	assert.False(t, stopped)
+
+	receivers[0].Observe(load.Observation{Elapsed: 55 * time.Second, Samples: 100, Latency: 900 * time.Millisecond})
+	result, stopped := feedback.Result()
+	if assert.True(t, stopped) {
+		assert.Equal(t, 15*time.Second, result.StoppedAt)
+	}
+}
+
+func TestFeedback_Clamping(t *testing.T) {
+	feedback := newStepSearch(t)
+	feedback.MaxShooters = 12
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 100, Latency: 100 * time.Millisecond})
+	assert.Equal(t, 12, feedback.Current())
+}
+
+func TestFeedback_MinSamples(t *testing.T) {
+	feedback := newStepSearch(t)
+	feedback.MinSamples = 50
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 10, Latency: 2 * time.Second})
+	assert.Equal(t, 10, feedback.Current())
+
+	_, stopped := feedback.Result()
+	assert.False(t, stopped)
+}
+
+func TestFeedback_StopsOnLatencyLimit(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 100, Latency: 200 * time.Millisecond})
+	feedback.Observe(load.Observation{Elapsed: 20 * time.Second, Samples: 100, Latency: 300 * time.Millisecond})
+	feedback.Observe(load.Observation{Elapsed: 25 * time.Second, Samples: 100, Latency: 900 * time.Millisecond})
+
+	result, stopped := feedback.Result()
+	if assert.True(t, stopped) {
+		assert.Equal(t, 18, result.Capacity)
+		assert.Equal(t, 25*time.Second, result.StoppedAt)
+		assert.Equal(t, "latency 900ms exceeded the limit of 800ms", result.Reason)
+	}
+
+	assert.Equal(t, 25*time.Second, feedback.TotalDuration())
+	assert.Equal(t, 0, feedback.At(30*time.Second))
+
+	select {
+	case <-feedback.Done():
+	default:
+		assert.Fail(t, "Feedback profile not done after the limit was breached")
+	}
+
+	// Further observations do not change the outcome
+	feedback.Observe(load.Observation{Elapsed: 30 * time.Second, Samples: 100, Latency: 100 * time.Millisecond})
+	secondResult, _ := feedback.Result()
+	assert.Equal(t, result, secondResult)
+}
+
+func TestFeedback_StopsOnErrorRate(t *testing.T) {
+	feedback := newStepSearch(t)
+
+	feedback.Observe(load.Observation{Elapsed: 10 * time.Second, Samples: 100, Latency: 100 * time.Millisecond, ErrorRate: 0.1})
+
+	result, stopped := feedback.Result()
+	if assert.True(t, stopped) {
+		assert.Equal(t, 0, result.Capacity)
+		assert.Equal(t, "error rate 10.00% exceeded the limit of 5.00%", result.Reason)
+	}
+}
+
+func TestFeedback_PIDControl(t *testing.T) {
+	feedback, err := load.ParseFeedback(load.PIDControl, 10, 100, "10m")
+	if !assert.NoError(t, err) {
+		return
+	}
+	feedback.TargetThroughput = 200
+	feedback.ProportionalGain = 0.5
+	feedback.AdjustInterval = time.Second
+
+	// Half of the target throughput is reached, so the shooters grow by a quarter of the maximum
+	feedback.Observe(load.Observation{Elapsed: time.Second, Samples: 100, Throughput: 100})
+	assert.Equal(t, 35, feedback.Current())
+
+	feedback.Observe(load.Observation{Elapsed: 2 * time.Second, Samples: 100, Throughput: 300})
+	assert.Equal(t, 1, feedback.Current())
+
+	feedback.Observe(load.Observation{Elapsed: 3 * time.Second, Samples: 100, Throughput: 200})
+	assert.Equal(t, 10, feedback.Current())
+}
+
+func TestFeedback_Validate(t *testing.T) {
+	feedback := newStepSearch(t)
+	feedback.TargetThroughput = 100
+	assert.EqualError(t, load.Validate(feedback), "invalid feedback profile: target must be either a throughput or a latency")
+
+	feedback = newStepSearch(t)
+	feedback.Strategy = "random"
+	assert.EqualError(t, load.Validate(feedback), "invalid feedback profile: strategy must be either 'step' or 'pid', got 'random'")
+
+	feedback = newStepSearch(t)
+	feedback.MinShooters = 200
+	assert.EqualError(t, load.Validate(feedback), "invalid feedback profile: max_shooters cannot be lower than min_shooters (200)")
+
+	feedback = newStepSearch(t)
+	feedback.MaxErrorRate = 5
+	assert.EqualError(t, load.Validate(feedback), "invalid feedback profile: max_error_rate must be between 0 and 1, got 5")
+}
+
+func TestFeedback_UnmarshalYAML(t *testing.T) {
+	var definition load.Definition
+	err := yaml.Unmarshal([]byte(`
+type: feedback
+strategy: step
+target_latency: 500ms
+max_latency: 800ms
+max_error_rate: 0.05
+initial_shooters: 10
+max_shooters: 100
+step_size: 8
+adjust_interval: 10s
+duration: 10m
+`), &definition)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, newStepSearch(t), definition.Profile)
+	}
+
+	var feedback load.Feedback
+	assert.Error(t, yaml.Unmarshal([]byte("target_latency: soon\nduration: 10m"), &feedback))
+	assert.Error(t, yaml.Unmarshal([]byte("initial_shooters: 10"), &feedback))
+}
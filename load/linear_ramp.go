@@ -114,3 +114,14 @@ func (r *LinearRamp) UnmarshalYAML(value *yaml.Node) error {
 
 	return nil
 }
+
+func (r LinearRamp) Validate() error {
+	checker := checkProfile("linear")
+	checker.positive("shooters", r.Shooters)
+	checker.nonNegativeDuration("initial_delay", r.InitialDelay)
+	checker.nonNegativeDuration("ramp_up_time", r.RampUpTime)
+	checker.nonNegativeDuration("sustain_time", r.SustainTime)
+	checker.nonNegativeDuration("ramp_down_time", r.RampDownTime)
+	checker.positiveDuration("total duration", r.RampUpTime+r.SustainTime+r.RampDownTime)
+	return checker.err
+}
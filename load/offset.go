@@ -0,0 +1,55 @@
+package load
+
+import (
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+// Offset delays the start of a profile
+type Offset struct {
+	Delay   time.Duration `yaml:"delay"`
+	Profile Definition    `yaml:"profile"`
+}
+
+func NewOffset(delay time.Duration, profile Profile) Offset {
+	return Offset{Delay: delay, Profile: Definition{Profile: profile}}
+}
+
+func (o Offset) At(elapsed time.Duration) int {
+	if elapsed < o.Delay {
+		return 0
+	}
+
+	return o.Profile.At(elapsed - o.Delay)
+}
+
+func (o Offset) TotalDuration() time.Duration {
+	return o.Delay + o.Profile.TotalDuration()
+}
+
+func (o *Offset) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Delay   string     `yaml:"delay"`
+		Profile Definition `yaml:"profile"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	delay, err := time.ParseDuration(temp.Delay)
+	if err != nil {
+		return err
+	}
+
+	o.Delay = delay
+	o.Profile = temp.Profile
+	return nil
+}
+
+func (o Offset) Validate() error {
+	checker := checkProfile("offset")
+	checker.nonNegativeDuration("delay", o.Delay)
+	checker.nested("profile", o.Profile.Profile)
+	return checker.err
+}
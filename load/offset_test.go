@@ -0,0 +1,41 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+func TestOffset_At(t *testing.T) {
+	constant, _ := load.ParseConstant(5, "", "10s")
+	offset := load.NewOffset(time.Minute, constant)
+
+	assert.Implements(t, (*load.Profile)(nil), offset)
+	assert.Equal(t, 0, offset.At(59*time.Second))
+	assert.Equal(t, 5, offset.At(time.Minute))
+	assert.Equal(t, 0, offset.At(70*time.Second))
+}
+
+func TestOffset_TotalDuration(t *testing.T) {
+	assert.Equal(t, time.Minute+testRamp.TotalDuration(), load.NewOffset(time.Minute, testRamp).TotalDuration())
+}
+
+func TestOffset_UnmarshalYAML(t *testing.T) {
+	var offset load.Offset
+	err := yaml.Unmarshal([]byte(`
+delay: 1m
+profile:
+  type: constant
+  shooters: 5
+  duration: 10s
+`), &offset)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Minute, offset.Delay)
+		assert.Equal(t, 5, offset.At(time.Minute))
+	}
+
+	assert.Error(t, yaml.Unmarshal([]byte(`delay: later`), &offset))
+}
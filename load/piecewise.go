@@ -0,0 +1,113 @@
+package load
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math"
+	"sort"
+	"time"
+)
+
+type Point struct {
+	At       time.Duration `yaml:"at"`
+	Shooters int           `yaml:"shooters"`
+}
+
+// Piecewise interpolates linearly the amount of shooters between arbitrary points
+type Piecewise struct {
+	Points []Point `yaml:"points"`
+}
+
+func NewPiecewise(points ...Point) Piecewise {
+	sorted := make([]Point, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].At < sorted[b].At
+	})
+
+	return Piecewise{Points: sorted}
+}
+
+func (p Piecewise) At(elapsed time.Duration) int {
+	if len(p.Points) == 0 || elapsed < p.Points[0].At || elapsed >= p.TotalDuration() {
+		return 0
+	}
+
+	next := sort.Search(len(p.Points), func(index int) bool {
+		return p.Points[index].At > elapsed
+	})
+	start := p.Points[next-1]
+	end := p.Points[next]
+
+	progress := float64(elapsed-start.At) / float64(end.At-start.At)
+	return int(math.Round(float64(start.Shooters) + float64(end.Shooters-start.Shooters)*progress))
+}
+
+func (p Piecewise) TotalDuration() time.Duration {
+	if len(p.Points) == 0 {
+		return 0
+	}
+
+	return p.Points[len(p.Points)-1].At
+}
+
+func (p *Piecewise) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Points []Point `yaml:"points"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	*p = NewPiecewise(temp.Points...)
+	return nil
+}
+
+func (p *Point) UnmarshalYAML(value *yaml.Node) error {
+	// Points can be written both as mappings and in the compact "[time, shooters]" form
+	if value.Kind == yaml.SequenceNode {
+		if len(value.Content) != 2 {
+			return ErrInvalidPoint{Line: value.Line, Reason: "expected a time and an amount of shooters"}
+		}
+
+		at, err := time.ParseDuration(value.Content[0].Value)
+		if err != nil {
+			return err
+		}
+
+		p.At = at
+		return value.Content[1].Decode(&p.Shooters)
+	}
+
+	var temp struct {
+		At       string `yaml:"at"`
+		Shooters int    `yaml:"shooters"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	at, err := time.ParseDuration(temp.At)
+	if err != nil {
+		return err
+	}
+
+	p.At = at
+	p.Shooters = temp.Shooters
+	return nil
+}
+
+func (p Piecewise) Validate() error {
+	checker := checkProfile("piecewise")
+	if len(p.Points) < 2 {
+		checker.fail("points", fmt.Sprintf("must be at least 2, got %d", len(p.Points)))
+	}
+
+	for index, point := range p.Points {
+		checker.nonNegativeDuration(fmt.Sprintf("points[%d].at", index), point.At)
+		checker.nonNegative(fmt.Sprintf("points[%d].shooters", index), point.Shooters)
+	}
+	return checker.err
+}
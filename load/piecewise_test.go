@@ -0,0 +1,77 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testPiecewise = load.NewPiecewise(
+	load.Point{At: 30 * time.Second, Shooters: 20},
+	load.Point{At: 0, Shooters: 0},
+	load.Point{At: 90 * time.Second, Shooters: 20},
+	load.Point{At: 90 * time.Second, Shooters: 40},
+	load.Point{At: 2 * time.Minute, Shooters: 0},
+)
+
+func TestPiecewiseCreation(t *testing.T) {
+	assert.IsType(t, load.Piecewise{}, testPiecewise)
+	assert.Implements(t, (*load.Profile)(nil), testPiecewise)
+	assert.Equal(t, time.Duration(0), testPiecewise.Points[0].At, "points are sorted by time")
+}
+
+func TestPiecewise_At(t *testing.T) {
+	assert.Equal(t, 0, testPiecewise.At(-1*time.Second))
+	assert.Equal(t, 0, testPiecewise.At(0))
+	assert.Equal(t, 10, testPiecewise.At(15*time.Second))
+	assert.Equal(t, 20, testPiecewise.At(30*time.Second))
+	assert.Equal(t, 20, testPiecewise.At(89*time.Second))
+	assert.Equal(t, 40, testPiecewise.At(90*time.Second), "points sharing the same time produce a step")
+	assert.Equal(t, 20, testPiecewise.At(105*time.Second))
+	assert.Equal(t, 0, testPiecewise.At(2*time.Minute))
+}
+
+func TestPiecewise_Empty(t *testing.T) {
+	empty := load.NewPiecewise()
+	assert.Equal(t, 0, empty.At(0))
+	assert.Equal(t, time.Duration(0), empty.TotalDuration())
+}
+
+func TestPiecewise_TotalDuration(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, testPiecewise.TotalDuration())
+}
+
+func TestPiecewise_Marshalling(t *testing.T) {
+	output, err := yaml.Marshal(testPiecewise)
+
+	if assert.NoError(t, err) {
+		var outputPiecewise load.Piecewise
+		assert.NoError(t, yaml.Unmarshal(output, &outputPiecewise))
+		assert.Equal(t, testPiecewise, outputPiecewise)
+	}
+}
+
+func TestPiecewise_UnmarshalYAML(t *testing.T) {
+	var piecewise load.Piecewise
+	err := yaml.Unmarshal([]byte(`
+points:
+  - [1m, 50]
+  - at: 0s
+    shooters: 10
+  - [2m, 50]
+`), &piecewise)
+
+	if assert.NoError(t, err) && assert.Len(t, piecewise.Points, 3) {
+		assert.Equal(t, load.Point{At: 0, Shooters: 10}, piecewise.Points[0])
+		assert.Equal(t, 30, piecewise.At(30*time.Second))
+		assert.Equal(t, 2*time.Minute, piecewise.TotalDuration())
+	}
+
+	err = yaml.Unmarshal([]byte(`
+points:
+  - [1m, 50, 20]
+`), &piecewise)
+	assert.IsType(t, load.ErrInvalidPoint{}, err)
+}
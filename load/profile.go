@@ -6,3 +6,9 @@ type Profile interface {
 	At(elapsed time.Duration) int
 	TotalDuration() time.Duration
 }
+
+type RateProfile interface {
+	Profile
+	RateAt(elapsed time.Duration) float64
+	PoolSize() int
+}
@@ -0,0 +1,85 @@
+package load
+
+import (
+	"reflect"
+	"sync"
+)
+
+const DefaultProfileType = "linear"
+
+var registry = struct {
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+	mutex sync.RWMutex
+}{
+	types: make(map[string]reflect.Type),
+	names: make(map[reflect.Type]string),
+}
+
+func init() {
+	Register(DefaultProfileType, LinearRamp{})
+	Register("constant", Constant{})
+	Register("stair_step", StairStep{})
+	Register("spike", Spike{})
+	Register("sine", Sine{})
+	Register("piecewise", Piecewise{})
+	Register("arrival_rate", ArrivalRate{})
+	Register("sequence", Sequence{})
+	Register("sum", Sum{})
+	Register("scale", Scale{})
+	Register("offset", Offset{})
+	Register("repeat", Repeat{})
+	Register("feedback", &Feedback{})
+}
+
+// Register makes a profile implementation available to the "type" field of profile definitions.
+// The prototype is only used to know the concrete type, which is decoded from YAML like any other value.
+func Register(profileType string, prototype Profile) {
+	concreteType := reflect.TypeOf(prototype)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.types[profileType] = concreteType
+	registry.names[concreteType] = profileType
+}
+
+func RegisteredTypes() []string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	output := make([]string, 0, len(registry.types))
+	for profileType := range registry.types {
+		output = append(output, profileType)
+	}
+
+	return output
+}
+
+// newRegisteredProfile returns the pointer to decode the YAML into and the function extracting the profile from it
+func newRegisteredProfile(profileType string) (interface{}, func() Profile, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	concreteType, isPresent := registry.types[profileType]
+	if !isPresent {
+		return nil, nil, false
+	}
+
+	// Pointer prototypes are returned as they are, value prototypes are decoded through a pointer
+	if concreteType.Kind() == reflect.Ptr {
+		target := reflect.New(concreteType.Elem())
+		return target.Interface(), func() Profile { return target.Interface().(Profile) }, true
+	}
+
+	target := reflect.New(concreteType)
+	return target.Interface(), func() Profile { return target.Elem().Interface().(Profile) }, true
+}
+
+func registeredName(profile Profile) (string, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	name, isPresent := registry.names[reflect.TypeOf(profile)]
+	return name, isPresent
+}
@@ -0,0 +1,33 @@
+package load
+
+import "time"
+
+// Repeat runs a profile again and again for the given amount of times
+type Repeat struct {
+	Times   int        `yaml:"times"`
+	Profile Definition `yaml:"profile"`
+}
+
+func NewRepeat(times int, profile Profile) Repeat {
+	return Repeat{Times: times, Profile: Definition{Profile: profile}}
+}
+
+func (r Repeat) At(elapsed time.Duration) int {
+	cycle := r.Profile.TotalDuration()
+	if elapsed < 0 || elapsed >= r.TotalDuration() || cycle <= 0 {
+		return 0
+	}
+
+	return r.Profile.At(elapsed % cycle)
+}
+
+func (r Repeat) TotalDuration() time.Duration {
+	return time.Duration(r.Times) * r.Profile.TotalDuration()
+}
+
+func (r Repeat) Validate() error {
+	checker := checkProfile("repeat")
+	checker.positive("times", r.Times)
+	checker.nested("profile", r.Profile.Profile)
+	return checker.err
+}
@@ -0,0 +1,27 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepeat_At(t *testing.T) {
+	stairStep, _ := load.ParseStairStep(2, 5, "", "10s")
+	repeat := load.NewRepeat(3, stairStep)
+
+	assert.Implements(t, (*load.Profile)(nil), repeat)
+	assert.Equal(t, 0, repeat.At(-1*time.Second))
+	assert.Equal(t, 5, repeat.At(0))
+	assert.Equal(t, 10, repeat.At(15*time.Second))
+	assert.Equal(t, 5, repeat.At(20*time.Second))
+	assert.Equal(t, 10, repeat.At(59*time.Second))
+	assert.Equal(t, 0, repeat.At(time.Minute))
+}
+
+func TestRepeat_TotalDuration(t *testing.T) {
+	stairStep, _ := load.ParseStairStep(2, 5, "", "10s")
+	assert.Equal(t, time.Minute, load.NewRepeat(3, stairStep).TotalDuration())
+	assert.Equal(t, time.Duration(0), load.NewRepeat(0, stairStep).TotalDuration())
+}
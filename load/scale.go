@@ -0,0 +1,34 @@
+package load
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Scale multiplies the shooters of a profile by a factor
+type Scale struct {
+	Factor  float64    `yaml:"factor"`
+	Profile Definition `yaml:"profile"`
+}
+
+func NewScale(factor float64, profile Profile) Scale {
+	return Scale{Factor: factor, Profile: Definition{Profile: profile}}
+}
+
+func (s Scale) At(elapsed time.Duration) int {
+	return int(math.Round(float64(s.Profile.At(elapsed)) * s.Factor))
+}
+
+func (s Scale) TotalDuration() time.Duration {
+	return s.Profile.TotalDuration()
+}
+
+func (s Scale) Validate() error {
+	checker := checkProfile("scale")
+	if s.Factor <= 0 {
+		checker.fail("factor", fmt.Sprintf("must be positive, got %g", s.Factor))
+	}
+	checker.nested("profile", s.Profile.Profile)
+	return checker.err
+}
@@ -0,0 +1,22 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestScale_At(t *testing.T) {
+	scale := load.NewScale(1.5, testRamp)
+
+	assert.Implements(t, (*load.Profile)(nil), scale)
+	assert.Equal(t, 0, scale.At(3*time.Second))
+	assert.Equal(t, 3, scale.At(6*time.Second))
+	assert.Equal(t, 12, scale.At(12*time.Second))
+	assert.Equal(t, 4, load.NewScale(0.5, testRamp).At(12*time.Second))
+}
+
+func TestScale_TotalDuration(t *testing.T) {
+	assert.Equal(t, testRamp.TotalDuration(), load.NewScale(3, testRamp).TotalDuration())
+}
@@ -0,0 +1,42 @@
+package load
+
+import "time"
+
+// Sequence runs its profiles one after the other
+type Sequence struct {
+	Profiles []Definition `yaml:"profiles"`
+}
+
+func NewSequence(profiles ...Profile) Sequence {
+	return Sequence{Profiles: definitionsOf(profiles)}
+}
+
+func (s Sequence) At(elapsed time.Duration) int {
+	if elapsed < 0 {
+		return 0
+	}
+
+	for _, profile := range s.Profiles {
+		if elapsed < profile.TotalDuration() {
+			return profile.At(elapsed)
+		}
+		elapsed -= profile.TotalDuration()
+	}
+
+	return 0
+}
+
+func (s Sequence) TotalDuration() time.Duration {
+	var output time.Duration
+	for _, profile := range s.Profiles {
+		output += profile.TotalDuration()
+	}
+
+	return output
+}
+
+func (s Sequence) Validate() error {
+	checker := checkProfile("sequence")
+	checker.nestedAll("profiles", s.Profiles)
+	return checker.err
+}
@@ -0,0 +1,28 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSequence_At(t *testing.T) {
+	first, _ := load.ParseConstant(5, "", "10s")
+	second, _ := load.ParseConstant(8, "", "20s")
+	sequence := load.NewSequence(first, second)
+
+	assert.Implements(t, (*load.Profile)(nil), sequence)
+	assert.Equal(t, 0, sequence.At(-1*time.Second))
+	assert.Equal(t, 5, sequence.At(0))
+	assert.Equal(t, 5, sequence.At(9*time.Second))
+	assert.Equal(t, 8, sequence.At(10*time.Second))
+	assert.Equal(t, 8, sequence.At(29*time.Second))
+	assert.Equal(t, 0, sequence.At(30*time.Second))
+}
+
+func TestSequence_TotalDuration(t *testing.T) {
+	first, _ := load.ParseConstant(5, "", "10s")
+	assert.Equal(t, 31*time.Second, load.NewSequence(first, testRamp).TotalDuration())
+	assert.Equal(t, time.Duration(0), load.NewSequence().TotalDuration())
+}
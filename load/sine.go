@@ -0,0 +1,94 @@
+package load
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math"
+	"time"
+)
+
+// Sine oscillates between a minimum and a maximum amount of shooters, starting from the minimum.
+// It is meant to simulate cyclic traffic, such as a daily pattern compressed into a shorter period.
+type Sine struct {
+	Min      int           `yaml:"min"`
+	Max      int           `yaml:"max"`
+	Period   time.Duration `yaml:"period"`
+	Phase    time.Duration `yaml:"phase,omitempty"`
+	Duration time.Duration `yaml:"duration"`
+}
+
+func ParseSine(min int, max int, period string, phase string, duration string) (Sine, error) {
+	output := new(Sine)
+	var err error
+
+	output.Min = min
+	output.Max = max
+
+	output.Period, err = time.ParseDuration(period)
+	if err != nil {
+		return Sine{}, err
+	}
+
+	output.Phase, err = parseOptionalDuration(phase)
+	if err != nil {
+		return Sine{}, err
+	}
+
+	output.Duration, err = time.ParseDuration(duration)
+	if err != nil {
+		return Sine{}, err
+	}
+
+	return *output, nil
+}
+
+func (s Sine) At(elapsed time.Duration) int {
+	if elapsed < 0 || elapsed >= s.Duration {
+		return 0
+	}
+
+	if s.Period <= 0 {
+		return s.Min
+	}
+
+	angle := 2 * math.Pi * float64(elapsed+s.Phase) / float64(s.Period)
+	return int(math.Round(float64(s.Min) + float64(s.Max-s.Min)*(1-math.Cos(angle))/2))
+}
+
+func (s Sine) TotalDuration() time.Duration {
+	return s.Duration
+}
+
+func (s *Sine) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Min      int    `yaml:"min"`
+		Max      int    `yaml:"max"`
+		Period   string `yaml:"period"`
+		Phase    string `yaml:"phase"`
+		Duration string `yaml:"duration"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	parsed, err := ParseSine(temp.Min, temp.Max, temp.Period, temp.Phase, temp.Duration)
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+	return nil
+}
+
+func (s Sine) Validate() error {
+	checker := checkProfile("sine")
+	checker.nonNegative("min", s.Min)
+	checker.positive("max", s.Max)
+	if s.Max < s.Min {
+		checker.fail("max", fmt.Sprintf("cannot be lower than min (%d)", s.Min))
+	}
+	checker.positiveDuration("period", s.Period)
+	checker.positiveDuration("duration", s.Duration)
+	return checker.err
+}
@@ -0,0 +1,69 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testSine, _ = load.ParseSine(10, 30, "24m", "", "48m")
+
+func TestSineCreation(t *testing.T) {
+	assert.IsType(t, load.Sine{}, testSine)
+	assert.Implements(t, (*load.Profile)(nil), testSine)
+
+	_, err := load.ParseSine(10, 30, "one day", "", "48m")
+	assert.Error(t, err)
+}
+
+func TestSine_At(t *testing.T) {
+	assert.Equal(t, 0, testSine.At(-1*time.Second))
+	assert.Equal(t, 10, testSine.At(0), "the cycle starts from the minimum")
+	assert.Equal(t, 20, testSine.At(6*time.Minute))
+	assert.Equal(t, 30, testSine.At(12*time.Minute), "the maximum is reached at half period")
+	assert.Equal(t, 20, testSine.At(18*time.Minute))
+	assert.Equal(t, 10, testSine.At(24*time.Minute))
+	assert.Equal(t, 30, testSine.At(36*time.Minute))
+	assert.Equal(t, 0, testSine.At(48*time.Minute))
+}
+
+func TestSine_Phase(t *testing.T) {
+	shifted, err := load.ParseSine(10, 30, "24m", "12m", "48m")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 30, shifted.At(0))
+		assert.Equal(t, 10, shifted.At(12*time.Minute))
+	}
+}
+
+func TestSine_TotalDuration(t *testing.T) {
+	assert.Equal(t, 48*time.Minute, testSine.TotalDuration())
+}
+
+func TestSine_Marshalling(t *testing.T) {
+	output, err := yaml.Marshal(testSine)
+
+	if assert.NoError(t, err) {
+		var outputSine load.Sine
+		assert.NoError(t, yaml.Unmarshal(output, &outputSine))
+		assert.Equal(t, testSine, outputSine)
+	}
+}
+
+func TestSine_UnmarshalYAML(t *testing.T) {
+	var sine load.Sine
+	err := yaml.Unmarshal([]byte(`
+min: 100
+max: 400
+period: 24h
+duration: 72h
+`), &sine)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 100, sine.At(0))
+		assert.Equal(t, 400, sine.At(12*time.Hour))
+		assert.Equal(t, 72*time.Hour, sine.TotalDuration())
+	}
+}
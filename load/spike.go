@@ -0,0 +1,114 @@
+package load
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+// Spike keeps a baseline of shooters and raises it to the peak during periodic bursts
+type Spike struct {
+	Baseline      int           `yaml:"baseline"`
+	Peak          int           `yaml:"peak"`
+	Duration      time.Duration `yaml:"duration"`
+	FirstSpike    time.Duration `yaml:"first_spike"`
+	SpikeDuration time.Duration `yaml:"spike_duration"`
+	Interval      time.Duration `yaml:"interval,omitempty"`
+}
+
+func ParseSpike(baseline int, peak int, duration string, firstSpike string, spikeDuration string, interval string) (Spike, error) {
+	output := new(Spike)
+	var err error
+
+	output.Baseline = baseline
+	output.Peak = peak
+
+	output.Duration, err = time.ParseDuration(duration)
+	if err != nil {
+		return Spike{}, err
+	}
+
+	output.FirstSpike, err = parseOptionalDuration(firstSpike)
+	if err != nil {
+		return Spike{}, err
+	}
+
+	output.SpikeDuration, err = time.ParseDuration(spikeDuration)
+	if err != nil {
+		return Spike{}, err
+	}
+
+	output.Interval, err = parseOptionalDuration(interval)
+	if err != nil {
+		return Spike{}, err
+	}
+
+	return *output, nil
+}
+
+func (s Spike) At(elapsed time.Duration) int {
+	if elapsed < 0 || elapsed >= s.Duration {
+		return 0
+	}
+
+	if s.isSpiking(elapsed) {
+		return s.Peak
+	}
+
+	return s.Baseline
+}
+
+func (s Spike) TotalDuration() time.Duration {
+	return s.Duration
+}
+
+func (s Spike) isSpiking(elapsed time.Duration) bool {
+	sinceFirstSpike := elapsed - s.FirstSpike
+	if sinceFirstSpike < 0 {
+		return false
+	}
+
+	// Without an interval there is a single burst
+	if s.Interval <= 0 {
+		return sinceFirstSpike < s.SpikeDuration
+	}
+
+	return sinceFirstSpike%s.Interval < s.SpikeDuration
+}
+
+func (s *Spike) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Baseline      int    `yaml:"baseline"`
+		Peak          int    `yaml:"peak"`
+		Duration      string `yaml:"duration"`
+		FirstSpike    string `yaml:"first_spike"`
+		SpikeDuration string `yaml:"spike_duration"`
+		Interval      string `yaml:"interval"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	parsed, err := ParseSpike(temp.Baseline, temp.Peak, temp.Duration, temp.FirstSpike, temp.SpikeDuration, temp.Interval)
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+	return nil
+}
+
+func (s Spike) Validate() error {
+	checker := checkProfile("spike")
+	checker.nonNegative("baseline", s.Baseline)
+	checker.positive("peak", s.Peak)
+	checker.positiveDuration("duration", s.Duration)
+	checker.nonNegativeDuration("first_spike", s.FirstSpike)
+	checker.positiveDuration("spike_duration", s.SpikeDuration)
+	checker.nonNegativeDuration("interval", s.Interval)
+	if s.Interval > 0 && s.SpikeDuration >= s.Interval {
+		checker.fail("spike_duration", fmt.Sprintf("must be shorter than the interval (%s)", s.Interval))
+	}
+	return checker.err
+}
@@ -0,0 +1,72 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testSpike, _ = load.ParseSpike(10, 50, "5m", "1m", "10s", "2m")
+
+func TestSpikeCreation(t *testing.T) {
+	assert.IsType(t, load.Spike{}, testSpike)
+	assert.Implements(t, (*load.Profile)(nil), testSpike)
+
+	_, err := load.ParseSpike(10, 50, "5m", "1m", "a while", "2m")
+	assert.Error(t, err)
+}
+
+func TestSpike_At(t *testing.T) {
+	assert.Equal(t, 0, testSpike.At(-1*time.Second))
+	assert.Equal(t, 10, testSpike.At(0))
+	assert.Equal(t, 10, testSpike.At(59*time.Second))
+	assert.Equal(t, 50, testSpike.At(time.Minute))
+	assert.Equal(t, 50, testSpike.At(69*time.Second))
+	assert.Equal(t, 10, testSpike.At(70*time.Second))
+	assert.Equal(t, 50, testSpike.At(3*time.Minute+5*time.Second))
+	assert.Equal(t, 10, testSpike.At(4*time.Minute))
+	assert.Equal(t, 0, testSpike.At(5*time.Minute))
+}
+
+func TestSpike_SingleBurst(t *testing.T) {
+	singleSpike, err := load.ParseSpike(10, 50, "5m", "1m", "10s", "")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 50, singleSpike.At(time.Minute+5*time.Second))
+		assert.Equal(t, 10, singleSpike.At(3*time.Minute+5*time.Second))
+	}
+}
+
+func TestSpike_TotalDuration(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, testSpike.TotalDuration())
+}
+
+func TestSpike_Marshalling(t *testing.T) {
+	output, err := yaml.Marshal(testSpike)
+
+	if assert.NoError(t, err) {
+		var outputSpike load.Spike
+		assert.NoError(t, yaml.Unmarshal(output, &outputSpike))
+		assert.Equal(t, testSpike, outputSpike)
+	}
+}
+
+func TestSpike_UnmarshalYAML(t *testing.T) {
+	var spike load.Spike
+	err := yaml.Unmarshal([]byte(`
+baseline: 5
+peak: 100
+duration: 10m
+first_spike: 2m
+spike_duration: 30s
+`), &spike)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5, spike.Baseline)
+		assert.Equal(t, 100, spike.Peak)
+		assert.Equal(t, 2*time.Minute, spike.FirstSpike)
+		assert.Equal(t, time.Duration(0), spike.Interval)
+	}
+}
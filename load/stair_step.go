@@ -0,0 +1,77 @@
+package load
+
+import (
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+// StairStep adds the same amount of shooters at every step, keeping them until the end of the profile
+type StairStep struct {
+	InitialDelay    time.Duration `yaml:"initial_delay"`
+	Steps           int           `yaml:"steps"`
+	ShootersPerStep int           `yaml:"shooters_per_step"`
+	StepDuration    time.Duration `yaml:"step_duration"`
+}
+
+func ParseStairStep(steps int, shootersPerStep int, initialDelay string, stepDuration string) (StairStep, error) {
+	output := new(StairStep)
+	var err error
+
+	output.Steps = steps
+	output.ShootersPerStep = shootersPerStep
+
+	output.InitialDelay, err = parseOptionalDuration(initialDelay)
+	if err != nil {
+		return StairStep{}, err
+	}
+
+	output.StepDuration, err = time.ParseDuration(stepDuration)
+	if err != nil {
+		return StairStep{}, err
+	}
+
+	return *output, nil
+}
+
+func (s StairStep) At(elapsed time.Duration) int {
+	if elapsed < s.InitialDelay || elapsed >= s.TotalDuration() || s.StepDuration <= 0 {
+		return 0
+	}
+
+	currentStep := int((elapsed - s.InitialDelay) / s.StepDuration)
+	return (currentStep + 1) * s.ShootersPerStep
+}
+
+func (s StairStep) TotalDuration() time.Duration {
+	return s.InitialDelay + time.Duration(s.Steps)*s.StepDuration
+}
+
+func (s *StairStep) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		InitialDelay    string `yaml:"initial_delay"`
+		Steps           int    `yaml:"steps"`
+		ShootersPerStep int    `yaml:"shooters_per_step"`
+		StepDuration    string `yaml:"step_duration"`
+	}
+
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+
+	parsed, err := ParseStairStep(temp.Steps, temp.ShootersPerStep, temp.InitialDelay, temp.StepDuration)
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+	return nil
+}
+
+func (s StairStep) Validate() error {
+	checker := checkProfile("stair_step")
+	checker.positive("steps", s.Steps)
+	checker.positive("shooters_per_step", s.ShootersPerStep)
+	checker.nonNegativeDuration("initial_delay", s.InitialDelay)
+	checker.positiveDuration("step_duration", s.StepDuration)
+	return checker.err
+}
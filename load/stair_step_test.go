@@ -0,0 +1,62 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+var testStairStep, _ = load.ParseStairStep(4, 5, "10s", "30s")
+
+func TestStairStepCreation(t *testing.T) {
+	assert.IsType(t, load.StairStep{}, testStairStep)
+	assert.Implements(t, (*load.Profile)(nil), testStairStep)
+
+	_, err := load.ParseStairStep(4, 5, "10s", "")
+	assert.Error(t, err)
+}
+
+func TestStairStep_At(t *testing.T) {
+	assert.Equal(t, 0, testStairStep.At(-1*time.Second))
+	assert.Equal(t, 0, testStairStep.At(9*time.Second))
+	assert.Equal(t, 5, testStairStep.At(10*time.Second))
+	assert.Equal(t, 5, testStairStep.At(39*time.Second))
+	assert.Equal(t, 10, testStairStep.At(40*time.Second))
+	assert.Equal(t, 15, testStairStep.At(70*time.Second))
+	assert.Equal(t, 20, testStairStep.At(129*time.Second))
+	assert.Equal(t, 0, testStairStep.At(130*time.Second))
+}
+
+func TestStairStep_TotalDuration(t *testing.T) {
+	assert.Equal(t, 130*time.Second, testStairStep.TotalDuration())
+}
+
+func TestStairStep_Marshalling(t *testing.T) {
+	output, err := yaml.Marshal(testStairStep)
+
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(output), "shooters_per_step: 5")
+		assert.Contains(t, string(output), "step_duration: 30s")
+
+		var outputStairStep load.StairStep
+		assert.NoError(t, yaml.Unmarshal(output, &outputStairStep))
+		assert.Equal(t, testStairStep, outputStairStep)
+	}
+}
+
+func TestStairStep_UnmarshalYAML(t *testing.T) {
+	var stairStep load.StairStep
+	err := yaml.Unmarshal([]byte(`
+steps: 3
+shooters_per_step: 10
+step_duration: 1m
+`), &stairStep)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10, stairStep.At(0))
+		assert.Equal(t, 30, stairStep.At(2*time.Minute))
+		assert.Equal(t, 3*time.Minute, stairStep.TotalDuration())
+	}
+}
@@ -0,0 +1,38 @@
+package load
+
+import "time"
+
+// Sum runs its profiles at the same time, adding up their shooters
+type Sum struct {
+	Profiles []Definition `yaml:"profiles"`
+}
+
+func NewSum(profiles ...Profile) Sum {
+	return Sum{Profiles: definitionsOf(profiles)}
+}
+
+func (s Sum) At(elapsed time.Duration) int {
+	output := 0
+	for _, profile := range s.Profiles {
+		output += profile.At(elapsed)
+	}
+
+	return output
+}
+
+func (s Sum) TotalDuration() time.Duration {
+	var output time.Duration
+	for _, profile := range s.Profiles {
+		if profile.TotalDuration() > output {
+			output = profile.TotalDuration()
+		}
+	}
+
+	return output
+}
+
+func (s Sum) Validate() error {
+	checker := checkProfile("sum")
+	checker.nestedAll("profiles", s.Profiles)
+	return checker.err
+}
@@ -0,0 +1,26 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSum_At(t *testing.T) {
+	short, _ := load.ParseConstant(5, "", "10s")
+	long, _ := load.ParseConstant(3, "5s", "20s")
+	sum := load.NewSum(short, long)
+
+	assert.Implements(t, (*load.Profile)(nil), sum)
+	assert.Equal(t, 5, sum.At(0))
+	assert.Equal(t, 8, sum.At(5*time.Second))
+	assert.Equal(t, 3, sum.At(10*time.Second))
+	assert.Equal(t, 0, sum.At(25*time.Second))
+}
+
+func TestSum_TotalDuration(t *testing.T) {
+	short, _ := load.ParseConstant(5, "", "10s")
+	long, _ := load.ParseConstant(3, "5s", "20s")
+	assert.Equal(t, 25*time.Second, load.NewSum(short, long).TotalDuration())
+}
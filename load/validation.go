@@ -0,0 +1,94 @@
+package load
+
+import (
+	"fmt"
+	"time"
+)
+
+type Validator interface {
+	Validate() error
+}
+
+// Validate checks the profile if it is able to, profiles registered by third parties may not implement any check
+func Validate(profile Profile) error {
+	if profile == nil {
+		return ErrInvalidProfile{Profile: "undefined", Field: "type", Reason: "must be specified"}
+	}
+
+	if validator, canValidate := profile.(Validator); canValidate {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+type profileChecker struct {
+	profile string
+	err     error
+}
+
+func checkProfile(profile string) *profileChecker {
+	return &profileChecker{profile: profile}
+}
+
+func (pc *profileChecker) fail(field string, reason string) {
+	if pc.err == nil {
+		pc.err = ErrInvalidProfile{Profile: pc.profile, Field: field, Reason: reason}
+	}
+}
+
+func (pc *profileChecker) positive(field string, value int) {
+	if value <= 0 {
+		pc.fail(field, fmt.Sprintf("must be positive, got %d", value))
+	}
+}
+
+func (pc *profileChecker) nonNegative(field string, value int) {
+	if value < 0 {
+		pc.fail(field, fmt.Sprintf("cannot be negative, got %d", value))
+	}
+}
+
+func (pc *profileChecker) positiveDuration(field string, value time.Duration) {
+	if value <= 0 {
+		pc.fail(field, fmt.Sprintf("must be positive, got %s", value))
+	}
+}
+
+func (pc *profileChecker) nonNegativeDuration(field string, value time.Duration) {
+	if value < 0 {
+		pc.fail(field, fmt.Sprintf("cannot be negative, got %s", value))
+	}
+}
+
+func (pc *profileChecker) nested(field string, profile Profile) {
+	// Arrival rates are generated by executors only when they are the whole ramp, nested ones would be run as plain shooters
+	if _, isRate := profile.(RateProfile); isRate {
+		if pc.err == nil {
+			pc.err = ErrNestedRateProfile{Profile: pc.profile, Field: field}
+		}
+		return
+	}
+
+	err := Validate(profile)
+	if _, isNestedRate := err.(ErrNestedRateProfile); isNestedRate {
+		if pc.err == nil {
+			pc.err = err
+		}
+		return
+	}
+
+	if err != nil {
+		pc.fail(field, fmt.Sprintf("is not valid (%s)", err))
+	}
+}
+
+func (pc *profileChecker) nestedAll(field string, profiles []Definition) {
+	if len(profiles) == 0 {
+		pc.fail(field, "must contain at least one profile")
+	}
+
+	for index, definition := range profiles {
+		pc.nested(fmt.Sprintf("%s[%d]", field, index), definition.Profile)
+	}
+}
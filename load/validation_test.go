@@ -0,0 +1,99 @@
+package load_test
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidate_ValidProfiles(t *testing.T) {
+	constant, _ := load.ParseConstant(5, "", "10s")
+	stairStep, _ := load.ParseStairStep(2, 5, "", "10s")
+
+	profiles := []load.Profile{
+		testRamp,
+		testConstant,
+		testStairStep,
+		testSpike,
+		testSine,
+		testPiecewise,
+		testArrivalRate,
+		load.NewSequence(constant, stairStep),
+		load.NewSum(constant, stairStep),
+		load.NewScale(0.5, constant),
+		load.NewOffset(time.Minute, constant),
+		load.NewRepeat(2, constant),
+		load.Definition{Profile: constant},
+		customProfile{},
+	}
+
+	for _, profile := range profiles {
+		assert.NoError(t, load.Validate(profile), "%T should be valid", profile)
+	}
+}
+
+func TestValidate_InvalidProfiles(t *testing.T) {
+	negativeRamp, _ := load.ParseLinearRamp(5, "-1s", "0s", "10s", "0s")
+	emptyRamp, _ := load.ParseLinearRamp(5, "10s", "0s", "0s", "0s")
+	zeroConstant, _ := load.ParseConstant(0, "", "10s")
+	overlappingSpike, _ := load.ParseSpike(1, 10, "1m", "", "20s", "10s")
+	invertedSine, _ := load.ParseSine(10, 5, "1m", "", "5m")
+
+	testCases := []struct {
+		profile  load.Profile
+		expected string
+	}{
+		{nil, "invalid undefined profile: type must be specified"},
+		{load.LinearRamp{}, "invalid linear profile: shooters must be positive, got 0"},
+		{negativeRamp, "invalid linear profile: initial_delay cannot be negative, got -1s"},
+		{emptyRamp, "invalid linear profile: total duration must be positive, got 0s"},
+		{zeroConstant, "invalid constant profile: shooters must be positive, got 0"},
+		{load.StairStep{Steps: 2, ShootersPerStep: 5}, "invalid stair_step profile: step_duration must be positive, got 0s"},
+		{overlappingSpike, "invalid spike profile: spike_duration must be shorter than the interval (10s)"},
+		{invertedSine, "invalid sine profile: max cannot be lower than min (10)"},
+		{load.NewPiecewise(load.Point{At: time.Second, Shooters: 5}), "invalid piecewise profile: points must be at least 2, got 1"},
+		{load.ArrivalRate{TimeUnit: time.Second, Duration: time.Minute, PreAllocatedShooters: 1}, "invalid arrival_rate profile: rate must be positive, got 0"},
+		{load.NewSequence(), "invalid sequence profile: profiles must contain at least one profile"},
+		{
+			load.NewSum(testConstant, zeroConstant),
+			"invalid sum profile: profiles[1] is not valid (invalid constant profile: shooters must be positive, got 0)",
+		},
+		{load.NewScale(0, testConstant), "invalid scale profile: factor must be positive, got 0"},
+		{load.NewOffset(-time.Second, testConstant), "invalid offset profile: delay cannot be negative, got -1s"},
+		{load.NewRepeat(0, testConstant), "invalid repeat profile: times must be positive, got 0"},
+		{load.Definition{}, "invalid undefined profile: type must be specified"},
+	}
+
+	for _, testCase := range testCases {
+		err := load.Validate(testCase.profile)
+		if assert.Error(t, err, "%T should not be valid", testCase.profile) {
+			assert.IsType(t, load.ErrInvalidProfile{}, err)
+			assert.EqualError(t, err, testCase.expected)
+		}
+	}
+}
+
+func TestValidate_NestedRateProfiles(t *testing.T) {
+	arrivalRate, _ := load.ParseArrivalRate(10, "1s", "1m", 4)
+
+	testCases := []struct {
+		profile  load.Profile
+		expected string
+	}{
+		{load.NewSum(testConstant, arrivalRate), "invalid sum profile: profiles[1] cannot be an arrival rate, which is supported only as a whole ramp"},
+		{load.NewScale(2, arrivalRate), "invalid scale profile: profile cannot be an arrival rate, which is supported only as a whole ramp"},
+		{
+			load.NewSequence(testConstant, load.NewOffset(time.Second, arrivalRate)),
+			"invalid offset profile: profile cannot be an arrival rate, which is supported only as a whole ramp",
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := load.Validate(testCase.profile)
+		if assert.Error(t, err, "%T should not be valid", testCase.profile) {
+			assert.IsType(t, load.ErrNestedRateProfile{}, err)
+			assert.EqualError(t, err, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package preview
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+var asciiSymbols = []rune{'#', '*', '+', 'o', '=', 'x', '%', '@'}
+
+// WriteASCII draws the shooters as a bar chart, stacking the quota of every injector when there are any
+func (p Preview) WriteASCII(w io.Writer, width int, height int) error {
+	if len(p.Points) == 0 || width <= 0 || height <= 0 {
+		_, err := fmt.Fprintln(w, "Empty load profile")
+		return err
+	}
+
+	if width > len(p.Points) {
+		width = len(p.Points)
+	}
+
+	peak := p.Peak()
+	columns := make([][]rune, width)
+	for column := range columns {
+		columns[column] = p.asciiColumn(p.Points[column*len(p.Points)/width], peak, height)
+	}
+
+	labelWidth := len(fmt.Sprint(peak))
+	var builder strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		label := ""
+		if row == height-1 {
+			label = fmt.Sprint(peak)
+		} else if row == 0 {
+			label = "0"
+		}
+
+		builder.WriteString(fmt.Sprintf("%*s |", labelWidth, label))
+		for _, column := range columns {
+			builder.WriteRune(column[row])
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("%*s +%s\n", labelWidth, "", strings.Repeat("-", width)))
+	end := p.Duration().String()
+	padding := width - len("0s") - len(end)
+	if padding < 1 {
+		padding = 1
+	}
+	builder.WriteString(fmt.Sprintf("%*s  0s%s%s\n", labelWidth, "", strings.Repeat(" ", padding), end))
+
+	for index, id := range p.InjectorIDs {
+		builder.WriteString(fmt.Sprintf("%c %s\n", asciiSymbols[index%len(asciiSymbols)], id))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func (p Preview) asciiColumn(point Point, peak int, height int) []rune {
+	column := []rune(strings.Repeat(" ", height))
+	if peak == 0 {
+		return column
+	}
+
+	if len(p.InjectorIDs) == 0 {
+		fillColumn(column, 0, scaleTo(point.Total, peak, height), asciiSymbols[0])
+		return column
+	}
+
+	stacked := 0
+	for index, id := range p.InjectorIDs {
+		from := scaleTo(stacked, peak, height)
+		stacked += point.Injectors[id]
+		fillColumn(column, from, scaleTo(stacked, peak, height), asciiSymbols[index%len(asciiSymbols)])
+	}
+
+	return column
+}
+
+func fillColumn(column []rune, from int, to int, symbol rune) {
+	for row := from; row < to && row < len(column); row++ {
+		column[row] = symbol
+	}
+}
+
+func scaleTo(value int, peak int, height int) int {
+	return int(math.Round(float64(value) * float64(height) / float64(peak)))
+}
@@ -0,0 +1,33 @@
+package preview
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+func (p Preview) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := append([]string{"elapsed_seconds", "total"}, p.InjectorIDs...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, point := range p.Points {
+		row := []string{
+			strconv.FormatFloat(point.Elapsed.Seconds(), 'f', -1, 64),
+			strconv.Itoa(point.Total),
+		}
+		for _, id := range p.InjectorIDs {
+			row = append(row, strconv.Itoa(point.Injectors[id]))
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
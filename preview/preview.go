@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"github.com/steromano87/harkonnen/cockpit"
+	"sort"
+	"time"
+)
+
+type Point struct {
+	Elapsed   time.Duration
+	Total     int
+	Injectors map[string]int
+}
+
+// Preview is the shooters schedule of a test, sampled at regular intervals
+type Preview struct {
+	Step        time.Duration
+	InjectorIDs []string
+	Points      []Point
+}
+
+func New(cc *cockpit.Cockpit, step time.Duration) Preview {
+	output := Preview{Step: step}
+	for id := range cc.Injectors {
+		output.InjectorIDs = append(output.InjectorIDs, id)
+	}
+	sort.Strings(output.InjectorIDs)
+
+	if step <= 0 {
+		return output
+	}
+
+	// The point at the total duration is included, so that the chart always ends at zero
+	for elapsed := time.Duration(0); elapsed <= cc.TotalDuration(); elapsed += step {
+		point := Point{Elapsed: elapsed, Total: cc.At(elapsed)}
+		if len(output.InjectorIDs) > 0 {
+			point.Injectors = cc.AtForEachInjector(elapsed)
+		}
+		output.Points = append(output.Points, point)
+	}
+
+	return output
+}
+
+func (p Preview) Duration() time.Duration {
+	if len(p.Points) == 0 {
+		return 0
+	}
+
+	return p.Points[len(p.Points)-1].Elapsed
+}
+
+func (p Preview) Peak() int {
+	output := 0
+	for _, point := range p.Points {
+		if point.Total > output {
+			output = point.Total
+		}
+	}
+
+	return output
+}
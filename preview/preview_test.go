@@ -0,0 +1,130 @@
+package preview_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/steromano87/harkonnen/cockpit"
+	"github.com/steromano87/harkonnen/injector"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/preview"
+	"github.com/steromano87/harkonnen/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PreviewTestSuite struct {
+	suite.Suite
+	cockpit *cockpit.Cockpit
+}
+
+func (suite *PreviewTestSuite) SetupTest() {
+	ramp, err := load.ParseLinearRamp(6, "0s", "2s", "4s", "2s")
+	suite.Require().NoError(err)
+
+	suite.cockpit = cockpit.New(context.Background(), injector.RemoteInjector)
+	suite.cockpit.LoadProfiles = []load.Profile{ramp}
+	suite.cockpit.Injectors["first"] = injector.Reference{Weight: 1}
+	suite.cockpit.Injectors["second"] = injector.Reference{Weight: 2}
+}
+
+func (suite *PreviewTestSuite) TestNew() {
+	schedule := preview.New(suite.cockpit, time.Second)
+
+	assert.Equal(suite.T(), []string{"first", "second"}, schedule.InjectorIDs)
+	if assert.Len(suite.T(), schedule.Points, 9) {
+		assert.Equal(suite.T(), preview.Point{Elapsed: 0, Total: 0, Injectors: map[string]int{"first": 0, "second": 0}}, schedule.Points[0])
+		assert.Equal(suite.T(), 6, schedule.Points[3].Total)
+		assert.Equal(suite.T(), map[string]int{"first": 2, "second": 4}, schedule.Points[3].Injectors)
+		assert.Equal(suite.T(), 0, schedule.Points[8].Total)
+	}
+
+	assert.Equal(suite.T(), 6, schedule.Peak())
+	assert.Equal(suite.T(), 8*time.Second, schedule.Duration())
+	assert.Empty(suite.T(), preview.New(suite.cockpit, 0).Points)
+}
+
+func (suite *PreviewTestSuite) TestWriteCSV() {
+	var output bytes.Buffer
+	assert.NoError(suite.T(), preview.New(suite.cockpit, 2*time.Second).WriteCSV(&output))
+
+	assert.Equal(suite.T(), strings.Join([]string{
+		"elapsed_seconds,total,first,second",
+		"0,0,0,0",
+		"2,6,2,4",
+		"4,6,2,4",
+		"6,6,2,4",
+		"8,0,0,0",
+		"",
+	}, "\n"), output.String())
+}
+
+func (suite *PreviewTestSuite) TestWriteASCII() {
+	var output bytes.Buffer
+	assert.NoError(suite.T(), preview.New(suite.cockpit, time.Second).WriteASCII(&output, 20, 3))
+
+	assert.Equal(suite.T(), strings.Join([]string{
+		"6 |  *****  ",
+		"  | ******* ",
+		"0 | ####### ",
+		"  +---------",
+		"   0s     8s",
+		"# first",
+		"* second",
+		"",
+	}, "\n"), output.String())
+}
+
+func (suite *PreviewTestSuite) TestWriteASCII_Empty() {
+	var output bytes.Buffer
+	assert.NoError(suite.T(), preview.Preview{}.WriteASCII(&output, 20, 3))
+	assert.Equal(suite.T(), "Empty load profile\n", output.String())
+}
+
+func (suite *PreviewTestSuite) TestWriteSVG() {
+	var output bytes.Buffer
+	assert.NoError(suite.T(), preview.New(suite.cockpit, time.Second).WriteSVG(&output, 400, 200))
+
+	svg := output.String()
+	assert.True(suite.T(), strings.HasPrefix(svg, "<svg "))
+	assert.True(suite.T(), strings.HasSuffix(svg, "</svg>\n"))
+	assert.Equal(suite.T(), 3, strings.Count(svg, "<polyline"), "total and one line per injector")
+	assert.Contains(suite.T(), svg, ">second</text>")
+}
+
+func (suite *PreviewTestSuite) TestNew_SpecsWithoutInjectors() {
+	var specs project.Specs
+	suite.Require().NoError(yaml.Unmarshal([]byte(`
+name: local test
+ramps:
+  - name: browsing
+    selector: role=load
+    shooters: 4
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 2s
+    ramp_down_time: 0s
+  - shooters: 2
+    initial_delay: 0s
+    ramp_up_time: 0s
+    sustain_time: 2s
+    ramp_down_time: 0s
+`), &specs))
+
+	localCockpit := cockpit.New(context.Background(), injector.RemoteInjector)
+	localCockpit.Injectors = cockpit.ReferencesFromSpecs(specs)
+	localCockpit.Scenarios = cockpit.ScenariosFromSpecs(specs)
+	suite.Require().NoError(localCockpit.Validate())
+
+	schedule := preview.New(localCockpit, time.Second)
+	assert.Empty(suite.T(), schedule.InjectorIDs)
+	assert.Equal(suite.T(), 6, schedule.Peak())
+	assert.Equal(suite.T(), 2*time.Second, schedule.Duration())
+}
+
+func TestPreviewTestSuite(t *testing.T) {
+	suite.Run(t, new(PreviewTestSuite))
+}
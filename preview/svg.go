@@ -0,0 +1,74 @@
+package preview
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const svgMargin = 40
+
+var svgColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+// WriteSVG draws the total shooters and the quota of every injector as lines
+func (p Preview) WriteSVG(w io.Writer, width int, height int) error {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width, height, width, height))
+	builder.WriteString(fmt.Sprintf("<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height))
+
+	plotWidth := float64(width - 2*svgMargin)
+	plotHeight := float64(height - 2*svgMargin)
+	bottom := float64(height - svgMargin)
+
+	// Axes and their boundary labels
+	builder.WriteString(fmt.Sprintf(
+		"<path d=\"M%d %d V%.0f H%.0f\" stroke=\"black\" fill=\"none\"/>\n",
+		svgMargin, svgMargin, bottom, float64(svgMargin)+plotWidth))
+	builder.WriteString(fmt.Sprintf(
+		"<text x=\"%d\" y=\"%d\" font-size=\"12\" text-anchor=\"end\">%d</text>\n",
+		svgMargin-4, svgMargin+4, p.Peak()))
+	builder.WriteString(fmt.Sprintf(
+		"<text x=\"%.0f\" y=\"%.0f\" font-size=\"12\" text-anchor=\"end\">%s</text>\n",
+		float64(svgMargin)+plotWidth, bottom+16, p.Duration()))
+
+	peak := float64(p.Peak())
+	duration := float64(p.Duration())
+	series := append([]string{"total"}, p.InjectorIDs...)
+
+	for index, name := range series {
+		var coordinates []string
+		for _, point := range p.Points {
+			value := point.Total
+			if index > 0 {
+				value = point.Injectors[name]
+			}
+
+			x, y := float64(svgMargin), bottom
+			if duration > 0 {
+				x += plotWidth * float64(point.Elapsed) / duration
+			}
+			if peak > 0 {
+				y -= plotHeight * float64(value) / peak
+			}
+			coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+
+		color := svgColors[index%len(svgColors)]
+		builder.WriteString(fmt.Sprintf(
+			"<polyline points=\"%s\" stroke=\"%s\" fill=\"none\" stroke-width=\"2\"/>\n",
+			strings.Join(coordinates, " "), color))
+		builder.WriteString(fmt.Sprintf(
+			"<text x=\"%.0f\" y=\"%d\" font-size=\"12\" fill=\"%s\">%s</text>\n",
+			float64(svgMargin)+plotWidth-100, svgMargin+16*(index+1), color, escapeSVG(name)))
+	}
+
+	builder.WriteString("</svg>\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func escapeSVG(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;").Replace(text)
+}
@@ -0,0 +1,12 @@
+package project
+
+import "fmt"
+
+type ErrInvalidLabelSelector struct {
+	Selector string
+	Reason   string
+}
+
+func (ils ErrInvalidLabelSelector) Error() string {
+	return fmt.Sprintf("invalid label selector '%s': %s", ils.Selector, ils.Reason)
+}
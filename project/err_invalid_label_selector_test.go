@@ -0,0 +1,15 @@
+package project_test
+
+import (
+	"github.com/steromano87/harkonnen/project"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidLabelSelector_Error(t *testing.T) {
+	assert.EqualError(
+		t,
+		project.ErrInvalidLabelSelector{Selector: "=eu", Reason: "empty label key"},
+		"invalid label selector '=eu': empty label key",
+		"Wrong error message format")
+}
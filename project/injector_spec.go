@@ -0,0 +1,10 @@
+package project
+
+type InjectorSpec struct {
+	Address     string   `yaml:"address"`
+	Port        uint16   `yaml:"port"`
+	Weight      int      `yaml:"weight,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	MinShooters int      `yaml:"min_shooters,omitempty"`
+	MaxShooters int      `yaml:"max_shooters,omitempty"`
+}
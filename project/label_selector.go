@@ -0,0 +1,108 @@
+package project
+
+import (
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// LabelSelector lists the labels an injector must have, a selector without value only requires the label key
+type LabelSelector map[string]string
+
+func ParseLabelSelector(raw string) (LabelSelector, error) {
+	output := make(LabelSelector)
+	for _, requirement := range strings.Split(raw, ",") {
+		requirement = strings.TrimSpace(requirement)
+		if requirement == "" {
+			continue
+		}
+
+		key, value := SplitLabel(requirement)
+		if key == "" {
+			return nil, ErrInvalidLabelSelector{Selector: raw, Reason: "empty label key"}
+		}
+		if _, isPresent := output[key]; isPresent {
+			return nil, ErrInvalidLabelSelector{Selector: raw, Reason: "label '" + key + "' is required more than once"}
+		}
+
+		output[key] = value
+	}
+
+	return output, nil
+}
+
+// SplitLabel separates the key and the value of labels in the "key=value" form
+func SplitLabel(label string) (string, string) {
+	parts := strings.SplitN(label, "=", 2)
+	key := strings.TrimSpace(parts[0])
+	if len(parts) == 1 {
+		return key, ""
+	}
+
+	return key, strings.TrimSpace(parts[1])
+}
+
+func (ls LabelSelector) Matches(labels []string) bool {
+	available := make(map[string]string, len(labels))
+	for _, label := range labels {
+		key, value := SplitLabel(label)
+		available[key] = value
+	}
+
+	for key, value := range ls {
+		actual, isPresent := available[key]
+		if !isPresent || (value != "" && actual != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ls LabelSelector) IsEmpty() bool {
+	return len(ls) == 0
+}
+
+func (ls LabelSelector) String() string {
+	requirements := make([]string, 0, len(ls))
+	for key, value := range ls {
+		if value == "" {
+			requirements = append(requirements, key)
+		} else {
+			requirements = append(requirements, key+"="+value)
+		}
+	}
+	sort.Strings(requirements)
+
+	return strings.Join(requirements, ",")
+}
+
+func (ls *LabelSelector) UnmarshalYAML(value *yaml.Node) error {
+	// Selectors can be written both as "region=eu,net=internal" and as a mapping
+	if value.Kind == yaml.MappingNode {
+		var requirements map[string]string
+		if err := value.Decode(&requirements); err != nil {
+			return err
+		}
+
+		*ls = requirements
+		return nil
+	}
+
+	var raw string
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	parsed, err := ParseLabelSelector(raw)
+	if err != nil {
+		return err
+	}
+
+	*ls = parsed
+	return nil
+}
+
+func (ls LabelSelector) MarshalYAML() (interface{}, error) {
+	return ls.String(), nil
+}
@@ -0,0 +1,63 @@
+package project_test
+
+import (
+	"github.com/steromano87/harkonnen/project"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := project.ParseLabelSelector("region=eu, net=internal,gpu")
+	if assert.NoError(t, err) {
+		assert.Equal(t, project.LabelSelector{"region": "eu", "net": "internal", "gpu": ""}, selector)
+		assert.Equal(t, "gpu,net=internal,region=eu", selector.String())
+	}
+
+	empty, err := project.ParseLabelSelector("")
+	if assert.NoError(t, err) {
+		assert.True(t, empty.IsEmpty())
+	}
+}
+
+func TestParseLabelSelector_Invalid(t *testing.T) {
+	_, err := project.ParseLabelSelector("=eu")
+	assert.IsType(t, project.ErrInvalidLabelSelector{}, err)
+
+	_, err = project.ParseLabelSelector("region=eu,region=us")
+	assert.IsType(t, project.ErrInvalidLabelSelector{}, err)
+}
+
+func TestLabelSelector_Matches(t *testing.T) {
+	labels := []string{"region=eu", "net=internal", "gpu"}
+
+	assert.True(t, project.LabelSelector{}.Matches(labels), "empty selectors match every injector")
+	assert.True(t, project.LabelSelector{"region": "eu"}.Matches(labels))
+	assert.True(t, project.LabelSelector{"region": "eu", "gpu": ""}.Matches(labels))
+	assert.True(t, project.LabelSelector{"net": ""}.Matches(labels), "selectors without value only require the key")
+	assert.False(t, project.LabelSelector{"region": "us"}.Matches(labels))
+	assert.False(t, project.LabelSelector{"region": "eu", "disk": "ssd"}.Matches(labels))
+	assert.False(t, project.LabelSelector{"region": "eu"}.Matches(nil))
+}
+
+func TestLabelSelector_UnmarshalYAML(t *testing.T) {
+	var selectors struct {
+		Inline  project.LabelSelector `yaml:"inline"`
+		Mapping project.LabelSelector `yaml:"mapping"`
+	}
+
+	err := yaml.Unmarshal([]byte(`
+inline: region=eu,net=internal
+mapping:
+  region: eu
+  net: internal
+`), &selectors)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, project.LabelSelector{"region": "eu", "net": "internal"}, selectors.Inline)
+		assert.Equal(t, selectors.Inline, selectors.Mapping)
+	}
+
+	var invalid project.LabelSelector
+	assert.IsType(t, project.ErrInvalidLabelSelector{}, yaml.Unmarshal([]byte(`"=eu"`), &invalid))
+}
@@ -0,0 +1,32 @@
+package project
+
+import (
+	"github.com/steromano87/harkonnen/load"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a load profile that can be restricted to the injectors matching a label selector
+type Scenario struct {
+	load.Definition
+	Name     string
+	Selector LabelSelector
+}
+
+func (s *Scenario) UnmarshalYAML(value *yaml.Node) error {
+	var placement struct {
+		Name     string        `yaml:"name"`
+		Selector LabelSelector `yaml:"selector"`
+	}
+
+	if err := value.Decode(&placement); err != nil {
+		return err
+	}
+
+	if err := value.Decode(&s.Definition); err != nil {
+		return err
+	}
+
+	s.Name = placement.Name
+	s.Selector = placement.Selector
+	return nil
+}
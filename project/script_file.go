@@ -2,15 +2,36 @@ package project
 
 import (
 	"errors"
+	"fmt"
+	"github.com/steromano87/harkonnen/shooter"
 	"os"
 )
 
 type ScriptFile struct {
-	Name string `yaml:"name"`
-	Path string `yaml:"path"`
+	Name        string              `yaml:"name"`
+	Path        string              `yaml:"path"`
+	ErrorPolicy shooter.ErrorPolicy `yaml:"error_policy,omitempty"`
+	Weight      int                 `yaml:"weight,omitempty"`
+	When        string              `yaml:"when,omitempty"`
 }
 
 func (f ScriptFile) Exists() bool {
 	_, err := os.Stat(f.Path)
 	return !errors.Is(err, os.ErrNotExist)
 }
+
+func (f ScriptFile) Validate() error {
+	if err := f.ErrorPolicy.Validate(); err != nil {
+		return fmt.Errorf("script '%s': %w", f.Name, err)
+	}
+
+	return nil
+}
+
+func (f ScriptFile) Apply(script shooter.Script) shooter.Script {
+	if f.ErrorPolicy == "" {
+		return script
+	}
+
+	return shooter.WithErrorPolicy(script, f.ErrorPolicy)
+}
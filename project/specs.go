@@ -1,7 +1,12 @@
 package project
 
 import (
+	"fmt"
+	"github.com/steromano87/harkonnen/feeder"
 	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"time"
 )
 
 type Specs struct {
@@ -9,18 +14,66 @@ type Specs struct {
 	Description string `yaml:"description,omitempty"`
 
 	Scripts struct {
-		SetUp    ScriptFile   `yaml:"set_up,omitempty"`
-		Main     []ScriptFile `yaml:"main"`
-		TearDown ScriptFile   `yaml:"tear_down,omitempty"`
+		SetUp             ScriptFile            `yaml:"set_up,omitempty"`
+		Main              []ScriptFile          `yaml:"main"`
+		TearDown          ScriptFile            `yaml:"tear_down,omitempty"`
+		Selection         shooter.SelectionMode `yaml:"selection,omitempty"`
+		SelectionVariable string                `yaml:"selection_variable,omitempty"`
 	} `yaml:"scripts"`
 
-	Ramps []load.LinearRamp `yaml:"ramps"`
+	Pacing          time.Duration       `yaml:"pacing,omitempty"`
+	ErrorPolicy     shooter.ErrorPolicy `yaml:"error_policy,omitempty"`
+	GracePeriod     time.Duration       `yaml:"grace_period,omitempty"`
+	TearDownTimeout time.Duration       `yaml:"tear_down_timeout,omitempty"`
+	MaxSpawnRate    float64             `yaml:"max_spawn_rate,omitempty"`
+	Feeders         []feeder.Spec       `yaml:"feeders,omitempty"`
+
+	Ramps     []Scenario              `yaml:"ramps"`
+	Injectors map[string]InjectorSpec `yaml:"injectors,omitempty"`
+
+	Thresholds []telemetry.CheckThreshold `yaml:"thresholds,omitempty"`
 }
 
 func (s Specs) File() string {
 	return "Harkonnen.yaml"
 }
 
+func (s Specs) ScriptSelector() (shooter.ScriptSelector, error) {
+	weights := make([]int, len(s.Scripts.Main))
+	matches := make([]string, len(s.Scripts.Main))
+
+	for index, scriptFile := range s.Scripts.Main {
+		weights[index] = scriptFile.Weight
+		matches[index] = scriptFile.When
+	}
+
+	return shooter.NewScriptSelector(s.Scripts.Selection, weights, s.Scripts.SelectionVariable, matches)
+}
+
+// Validate checks the error policies and the load profiles, so that mistakes are found before the test is launched
+func (s Specs) Validate() error {
+	if err := s.ErrorPolicy.Validate(); err != nil {
+		return err
+	}
+
+	for _, scriptFile := range append([]ScriptFile{s.Scripts.SetUp, s.Scripts.TearDown}, s.Scripts.Main...) {
+		if err := scriptFile.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for index, ramp := range s.Ramps {
+		if err := load.Validate(ramp.Profile); err != nil {
+			if ramp.Name != "" {
+				return fmt.Errorf("ramp '%s': %w", ramp.Name, err)
+			}
+			return fmt.Errorf("ramp #%d: %w", index+1, err)
+		}
+	}
+
+	return nil
+}
+
 func (s Specs) Create(workdir string) error {
 	return nil
 }
@@ -0,0 +1,169 @@
+package project_test
+
+import (
+	"errors"
+	"github.com/steromano87/harkonnen/load"
+	"github.com/steromano87/harkonnen/project"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"time"
+)
+
+func TestSpecs_UnmarshalYAML(t *testing.T) {
+	rawSpecs := `
+name: Shop
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+      weight: 70
+    - name: buy
+      path: scripts/buy.go
+      weight: 30
+      error_policy: stop_shooter
+  selection: weighted_random
+pacing: 5s
+error_policy: continue_iteration
+`
+	var specs project.Specs
+	err := yaml.Unmarshal([]byte(rawSpecs), &specs)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Shop", specs.Name)
+		assert.Len(t, specs.Scripts.Main, 2)
+		assert.Equal(t, 70, specs.Scripts.Main[0].Weight)
+		assert.Equal(t, shooter.StopShooter, specs.Scripts.Main[1].ErrorPolicy)
+		assert.Equal(t, shooter.WeightedRandomSelection, specs.Scripts.Selection)
+		assert.Equal(t, "5s", specs.Pacing.String())
+		assert.Equal(t, shooter.ContinueIteration, specs.ErrorPolicy)
+	}
+}
+
+func TestSpecs_ScriptSelector(t *testing.T) {
+	specs := project.Specs{}
+	specs.Scripts.Main = []project.ScriptFile{
+		{Name: "browse", When: "browse"},
+		{Name: "buy", When: "buy"},
+	}
+	specs.Scripts.Selection = shooter.ConditionalSelection
+	specs.Scripts.SelectionVariable = "step"
+
+	selector, err := specs.ScriptSelector()
+	if assert.NoError(t, err) {
+		assert.IsType(t, &shooter.ConditionalSelector{}, selector)
+	}
+}
+
+func TestSpecs_ScriptSelectorInvalid(t *testing.T) {
+	specs := project.Specs{}
+	specs.Scripts.Main = []project.ScriptFile{{Name: "browse"}}
+	specs.Scripts.Selection = shooter.WeightedRandomSelection
+
+	_, err := specs.ScriptSelector()
+	assert.IsType(t, shooter.ErrInvalidScriptSelection{}, err)
+}
+
+func TestSpecs_UnmarshalScenarios(t *testing.T) {
+	rawSpecs := `
+name: Shop
+ramps:
+  - name: european users
+    selector: region=eu
+    shooters: 10
+    initial_delay: 0s
+    ramp_up_time: 10s
+    sustain_time: 1m
+    ramp_down_time: 10s
+  - type: constant
+    shooters: 5
+    initial_delay: 5s
+    duration: 1m
+`
+	var specs project.Specs
+	err := yaml.Unmarshal([]byte(rawSpecs), &specs)
+
+	if assert.NoError(t, err) && assert.Len(t, specs.Ramps, 2) {
+		assert.Equal(t, "european users", specs.Ramps[0].Name)
+		assert.Equal(t, project.LabelSelector{"region": "eu"}, specs.Ramps[0].Selector)
+		if assert.IsType(t, load.LinearRamp{}, specs.Ramps[0].Profile, "ramps are linear unless otherwise specified") {
+			assert.Equal(t, 10, specs.Ramps[0].Profile.(load.LinearRamp).Shooters)
+			assert.Equal(t, "10s", specs.Ramps[0].Profile.(load.LinearRamp).RampUpTime.String())
+		}
+
+		assert.True(t, specs.Ramps[1].Selector.IsEmpty())
+		assert.IsType(t, load.Constant{}, specs.Ramps[1].Profile)
+		assert.Equal(t, 5, specs.Ramps[1].At(5*time.Second))
+	}
+}
+
+func TestSpecs_UnmarshalUnknownProfile(t *testing.T) {
+	rawSpecs := `
+name: Shop
+ramps:
+  - type: zigzag
+    shooters: 10
+`
+	var specs project.Specs
+	err := yaml.Unmarshal([]byte(rawSpecs), &specs)
+	assert.IsType(t, load.ErrUnknownProfileType{}, err)
+}
+
+func TestSpecs_Validate(t *testing.T) {
+	rawSpecs := `
+name: Shop
+ramps:
+  - type: constant
+    shooters: 5
+    duration: 1m
+  - name: broken
+    type: constant
+    shooters: 0
+    duration: 1m
+injectors:
+  eu1:
+    address: 10.0.0.1
+    port: 3200
+    labels: [region=eu]
+    max_shooters: 50
+`
+	var specs project.Specs
+	if assert.NoError(t, yaml.Unmarshal([]byte(rawSpecs), &specs)) {
+		assert.Equal(t, project.InjectorSpec{
+			Address:     "10.0.0.1",
+			Port:        3200,
+			Labels:      []string{"region=eu"},
+			MaxShooters: 50,
+		}, specs.Injectors["eu1"])
+
+		err := specs.Validate()
+		assert.EqualError(t, err, "ramp 'broken': invalid constant profile: shooters must be positive, got 0")
+		assert.True(t, errors.As(err, &load.ErrInvalidProfile{}))
+
+		specs.Ramps = specs.Ramps[:1]
+		assert.NoError(t, specs.Validate())
+	}
+}
+
+func TestSpecs_ValidateErrorPolicy(t *testing.T) {
+	rawSpecs := `
+name: Shop
+scripts:
+  main:
+    - name: browse
+      path: scripts/browse.go
+      error_policy: retry
+error_policy: abort_test
+`
+	var specs project.Specs
+	if assert.NoError(t, yaml.Unmarshal([]byte(rawSpecs), &specs)) {
+		err := specs.Validate()
+		assert.EqualError(t, err, "script 'browse': "+shooter.ErrUnknownErrorPolicy{Policy: "retry"}.Error())
+		assert.True(t, errors.As(err, &shooter.ErrUnknownErrorPolicy{}))
+
+		specs.Scripts.Main[0].ErrorPolicy = shooter.StopShooter
+		specs.ErrorPolicy = "abort"
+		assert.Equal(t, shooter.ErrUnknownErrorPolicy{Policy: "abort"}, specs.Validate())
+	}
+}
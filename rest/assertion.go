@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/steromano87/harkonnen/telemetry"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Assertion func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult
+
+func StatusIs(expected int) Assertion {
+	return func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult {
+		return telemetry.CheckResult{
+			Name:    fmt.Sprintf("status is %d", expected),
+			Passed:  response.StatusCode == expected,
+			Message: fmt.Sprintf("got status %d", response.StatusCode),
+		}
+	}
+}
+
+func HeaderEquals(name string, expected string) Assertion {
+	return func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult {
+		actual := response.Header.Get(name)
+		return telemetry.CheckResult{
+			Name:    fmt.Sprintf("header '%s' equals '%s'", name, expected),
+			Passed:  actual == expected,
+			Message: fmt.Sprintf("got header value '%s'", actual),
+		}
+	}
+}
+
+func BodyContains(expected string) Assertion {
+	return func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult {
+		result := telemetry.CheckResult{
+			Name:   fmt.Sprintf("body contains '%s'", expected),
+			Passed: strings.Contains(string(body), expected),
+		}
+
+		if !result.Passed {
+			result.Message = fmt.Sprintf("body of %d bytes does not contain '%s'", len(body), expected)
+		}
+		return result
+	}
+}
+
+func JSONPathEquals(path string, expected interface{}) Assertion {
+	return func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult {
+		result := telemetry.CheckResult{Name: fmt.Sprintf("JSON path '%s' equals '%v'", path, expected)}
+
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			result.Message = fmt.Sprintf("cannot parse body as JSON: %s", err)
+			return result
+		}
+
+		actual, found := lookupJSONPath(document, path)
+		if !found {
+			result.Message = fmt.Sprintf("JSON path '%s' not found", path)
+			return result
+		}
+
+		result.Passed = reflect.DeepEqual(actual, normalizeJSONValue(expected))
+		result.Message = fmt.Sprintf("got value '%v'", actual)
+		return result
+	}
+}
+
+func LatencyUnder(limit time.Duration) Assertion {
+	return func(response *http.Response, body []byte, elapsed time.Duration) telemetry.CheckResult {
+		return telemetry.CheckResult{
+			Name:    fmt.Sprintf("latency under %s", limit),
+			Passed:  elapsed < limit,
+			Message: fmt.Sprintf("got latency of %s", elapsed),
+		}
+	}
+}
+
+func lookupJSONPath(document interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return document, true
+	}
+
+	// Array indexes can be expressed both as "items[0]" and "items.0"
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+
+	current := document
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, isPresent := node[segment]
+			if !isPresent {
+				return nil, false
+			}
+			current = value
+
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func normalizeJSONValue(value interface{}) interface{} {
+	// Round-trip the expected value so that it is comparable with the decoded JSON (e.g. ints become float64)
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
@@ -0,0 +1,67 @@
+package rest_test
+
+import (
+	"github.com/steromano87/harkonnen/rest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var assertionResponse = &http.Response{
+	StatusCode: 200,
+	Header:     http.Header{"Content-Type": []string{"application/json"}},
+}
+
+var assertionBody = []byte(`{"user": {"name": "alice", "roles": ["admin", "editor"], "age": 30}}`)
+
+func TestStatusIs(t *testing.T) {
+	assert.True(t, rest.StatusIs(200)(assertionResponse, assertionBody, time.Millisecond).Passed)
+
+	result := rest.StatusIs(404)(assertionResponse, assertionBody, time.Millisecond)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "status is 404", result.Name)
+	assert.Equal(t, "got status 200", result.Message)
+}
+
+func TestHeaderEquals(t *testing.T) {
+	assert.True(t, rest.HeaderEquals("Content-Type", "application/json")(assertionResponse, assertionBody, 0).Passed)
+	assert.False(t, rest.HeaderEquals("Content-Type", "text/html")(assertionResponse, assertionBody, 0).Passed)
+}
+
+func TestBodyContains(t *testing.T) {
+	passed := rest.BodyContains("alice")(assertionResponse, assertionBody, 0)
+	assert.True(t, passed.Passed)
+	assert.Empty(t, passed.Message)
+
+	failed := rest.BodyContains("bob")(assertionResponse, assertionBody, 0)
+	assert.False(t, failed.Passed)
+	assert.Contains(t, failed.Message, "does not contain 'bob'")
+}
+
+func TestJSONPathEquals(t *testing.T) {
+	assert.True(t, rest.JSONPathEquals("user.name", "alice")(assertionResponse, assertionBody, 0).Passed)
+	assert.True(t, rest.JSONPathEquals("$.user.roles[1]", "editor")(assertionResponse, assertionBody, 0).Passed)
+	assert.True(t, rest.JSONPathEquals("user.roles.0", "admin")(assertionResponse, assertionBody, 0).Passed)
+	assert.True(t, rest.JSONPathEquals("user.age", 30)(assertionResponse, assertionBody, 0).Passed)
+	assert.False(t, rest.JSONPathEquals("user.age", 31)(assertionResponse, assertionBody, 0).Passed)
+}
+
+func TestJSONPathEqualsMissingPath(t *testing.T) {
+	result := rest.JSONPathEquals("user.roles[5]", "admin")(assertionResponse, assertionBody, 0)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, "JSON path 'user.roles[5]' not found", result.Message)
+}
+
+func TestJSONPathEqualsInvalidBody(t *testing.T) {
+	result := rest.JSONPathEquals("user.name", "alice")(assertionResponse, []byte("not JSON"), 0)
+
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Message, "cannot parse body as JSON")
+}
+
+func TestLatencyUnder(t *testing.T) {
+	assert.True(t, rest.LatencyUnder(100*time.Millisecond)(assertionResponse, assertionBody, 50*time.Millisecond).Passed)
+	assert.False(t, rest.LatencyUnder(100*time.Millisecond)(assertionResponse, assertionBody, 150*time.Millisecond).Passed)
+}
@@ -3,11 +3,14 @@ package rest
 import (
 	"bytes"
 	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httputil"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -41,17 +44,27 @@ func (c *Client) Execute(request Request, options ...Option) {
 	rawRequest, err := request.Build(c.settings.BaseUrl)
 
 	if err != nil {
-		c.context.OnUnrecoverableError(err)
+		c.abort(err, options)
 		return
 	}
 
+	// Bind the request to the shooter context, so that a forced shutdown aborts it
+	rawRequest = rawRequest.WithContext(c.context)
+
 	// Perform the request and track the elapsed time
 	startTime := time.Now()
 	response, err := c.innerClient.Do(rawRequest)
 	endTime := time.Now()
 
 	if err != nil {
-		c.context.OnUnrecoverableError(err)
+		// Failed requests are sampled as well, so that connection errors show up in the reports
+		sample := NewSample(sampleName(rawRequest.URL), startTime, endTime, 0, 0)
+		sample.Method = request.Method
+		sample.Fail(telemetry.ClassifyError(err), err.Error())
+		c.context.TagSample(&sample.BaseSample)
+		c.context.SampleCollector().Collect(sample)
+
+		c.abort(err, options)
 		return
 	}
 
@@ -71,15 +84,60 @@ func (c *Client) Execute(request Request, options ...Option) {
 	finalURL := response.Request.URL
 
 	// Create request sample
-	sample := NewSample(rawRequest.URL.String(), startTime, endTime, sentBytes, receivedBytes)
+	sample := NewSample(sampleName(rawRequest.URL), startTime, endTime, sentBytes, receivedBytes)
 	sample.URL = pureUrl
 	sample.Parameters = queryString
 	sample.Method = request.Method
 	sample.IsRedirect = originalURL != finalURL
 	sample.FinalURL = finalURL
+	sample.SetStatusCode(response.StatusCode)
+	c.context.TagSample(&sample.BaseSample)
+
+	// Evaluate response assertions, if any
+	assertionsPassed := true
+	var failedChecks []string
+	if len(request.Assertions) > 0 {
+		responseBody := c.readResponseBody(response)
+		for _, assertion := range request.Assertions {
+			result := assertion(response, responseBody, endTime.Sub(startTime))
+			sample.AddCheck(result)
+			assertionsPassed = c.context.RecordCheck(result) && assertionsPassed
+			if !result.Passed {
+				failedChecks = append(failedChecks, result.Name)
+			}
+		}
+	}
+
+	// Assertions decide the outcome when present, so that an expected error status is not reported as a failure
+	switch {
+	case len(failedChecks) > 0:
+		sample.Fail(telemetry.AssertionError, "failed checks: "+strings.Join(failedChecks, ", "))
+	case len(request.Assertions) == 0 && response.StatusCode >= http.StatusBadRequest && !HasOption(options, AllowUnsuccessfulStatuses):
+		sample.Fail(telemetry.ProtocolError, response.Status)
+	}
 
 	c.context.SampleCollector().Collect(sample)
 	c.lastResponse = response
+
+	if !assertionsPassed && HasOption(options, SkipIterationOnError) {
+		c.context.SkipIteration()
+	}
+}
+
+// sampleName leaves the query string out, so that requests differing only in their parameters are aggregated together
+func sampleName(requestURL *url.URL) string {
+	stripped := *requestURL
+	stripped.RawQuery = ""
+	return stripped.String()
+}
+
+func (c *Client) abort(err error, options []Option) {
+	if HasOption(options, SkipIterationOnError) {
+		c.context.Logger().Warn().Err(err).Msg("Request failed, skipping current iteration")
+		c.context.SkipIteration()
+	}
+
+	c.context.OnUnrecoverableError(err)
 }
 
 func (c *Client) calculateSentReceivedBytes(response *http.Response) (int64, int64) {
@@ -137,6 +195,21 @@ func (c *Client) calculateSentReceivedBytes(response *http.Response) (int64, int
 	return requestHeaderSize + requestBodySize, responseHeaderSize + responseBodySize
 }
 
+func (c *Client) readResponseBody(response *http.Response) []byte {
+	if response.Body == nil {
+		return []byte{}
+	}
+
+	bodyBuffer, err := ioutil.ReadAll(response.Body)
+	response.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBuffer))
+
+	if err != nil {
+		c.context.OnUnrecoverableError(err)
+	}
+
+	return bodyBuffer
+}
+
 func (c *Client) buildInnerClient() {
 	client := http.Client{}
 
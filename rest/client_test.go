@@ -49,6 +49,10 @@ func (suite *ClientTestSuite) SetupTest() {
 		_, _ = fmt.Fprintf(w, "Request body: '%s'\n", body)
 	})
 
+	handler.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
 	handler.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Location", "/redirected")
 		w.WriteHeader(302)
@@ -91,6 +95,9 @@ func (suite *ClientTestSuite) TestGetRequest() {
 			assert.Equal(suite.T(), url.Values{}, sample.Parameters)
 			assert.Greater(suite.T(), sample.SentBytes(), int64(0))
 			assert.Greater(suite.T(), sample.ReceivedBytes(), int64(0))
+			assert.True(suite.T(), sample.Succeeded())
+			assert.Equal(suite.T(), http.StatusOK, sample.StatusCode())
+			assert.Equal(suite.T(), suite.shooterID, sample.Tags()[telemetry.ShooterTag])
 		}
 	}
 
@@ -300,6 +307,142 @@ func (suite *ClientTestSuite) TestPostFormRequest() {
 	assert.Contains(suite.T(), responseBody, fmt.Sprintf("Request body: '%s'", values.Encode()))
 }
 
+func (suite *ClientTestSuite) TestRequestWithAssertions() {
+	request := rest.Get(suite.testServer.URL, nil).Expect(
+		rest.StatusIs(200),
+		rest.BodyContains("Request method: 'GET'"),
+		rest.BodyContains("Request method: 'POST'"),
+	)
+	suite.client.Execute(request)
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+
+	if assert.Equal(suite.T(), 1, len(collectedSamples)) {
+		sample := collectedSamples[0].(rest.Sample)
+		if assert.Len(suite.T(), sample.Checks(), 3) {
+			assert.True(suite.T(), sample.Checks()[0].Passed)
+			assert.True(suite.T(), sample.Checks()[1].Passed)
+			assert.False(suite.T(), sample.Checks()[2].Passed)
+		}
+		assert.False(suite.T(), sample.Succeeded())
+		assert.Equal(suite.T(), telemetry.AssertionError, sample.ErrorClass())
+		assert.Equal(suite.T(), "failed checks: body contains 'Request method: 'POST''", sample.ErrorMessage())
+	}
+
+	assert.EqualValues(suite.T(), 2, suite.context.CheckCounter().Total().Passed)
+	assert.EqualValues(suite.T(), 1, suite.context.CheckCounter().Total().Failed)
+
+	// The response body must still be readable after the assertions
+	responseBodyBytes, _ := ioutil.ReadAll(suite.client.LastResponse().Body)
+	assert.Contains(suite.T(), string(responseBodyBytes), "Request method: 'GET'")
+}
+
+func (suite *ClientTestSuite) TestErrorStatus() {
+	suite.client.Execute(rest.Get(suite.testServer.URL+"/broken", nil))
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 1) {
+		sample := collectedSamples[0]
+		assert.False(suite.T(), sample.Succeeded())
+		assert.Equal(suite.T(), http.StatusInternalServerError, sample.StatusCode())
+		assert.Equal(suite.T(), telemetry.ProtocolError, sample.ErrorClass())
+		assert.Equal(suite.T(), "500 Internal Server Error", sample.ErrorMessage())
+	}
+}
+
+func (suite *ClientTestSuite) TestExpectedErrorStatus() {
+	suite.client.Execute(rest.Get(suite.testServer.URL+"/broken", nil).Expect(rest.StatusIs(500)))
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 1) {
+		assert.True(suite.T(), collectedSamples[0].Succeeded())
+		assert.Equal(suite.T(), http.StatusInternalServerError, collectedSamples[0].StatusCode())
+	}
+}
+
+func (suite *ClientTestSuite) TestAllowedErrorStatus() {
+	suite.client.Execute(rest.Get(suite.testServer.URL+"/broken", nil), rest.AllowUnsuccessfulStatuses)
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 1) {
+		assert.True(suite.T(), collectedSamples[0].Succeeded())
+		assert.Equal(suite.T(), http.StatusInternalServerError, collectedSamples[0].StatusCode())
+	}
+}
+
+func (suite *ClientTestSuite) TestConnectionError() {
+	address := suite.testServer.URL
+	suite.testServer.Close()
+
+	assert.Panics(suite.T(), func() {
+		suite.client.Execute(rest.Get(address, nil))
+	})
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 1, "Failed requests should be sampled") {
+		sample := collectedSamples[0]
+		assert.False(suite.T(), sample.Succeeded())
+		assert.Equal(suite.T(), telemetry.ConnectionError, sample.ErrorClass())
+		assert.Contains(suite.T(), sample.ErrorMessage(), "connection refused")
+		assert.Equal(suite.T(), 0, sample.StatusCode())
+	}
+}
+
+func (suite *ClientTestSuite) TestConnectionErrorWithQueryString() {
+	address := suite.testServer.URL
+	suite.testServer.Close()
+
+	assert.Panics(suite.T(), func() {
+		suite.client.Execute(rest.Get(address+"/search?query=shoes", nil))
+	})
+
+	// Failed requests are named as the successful ones, so that both end up in the same statistics
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 1) {
+		assert.Equal(suite.T(), address+"/search", collectedSamples[0].Name())
+	}
+}
+
+func (suite *ClientTestSuite) TestSampleTags() {
+	suite.context.SetTag(telemetry.ScenarioTag, "checkout")
+
+	err := suite.context.Transaction("Login", func() error {
+		suite.client.Execute(rest.Get(suite.testServer.URL, nil))
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+
+	collectedSamples := suite.context.SampleCollector().Flush()
+	if assert.Len(suite.T(), collectedSamples, 2) {
+		assert.Equal(suite.T(), map[string]string{
+			telemetry.ScenarioTag:    "checkout",
+			telemetry.ShooterTag:     suite.shooterID,
+			telemetry.TransactionTag: "Login",
+		}, collectedSamples[0].Tags())
+
+		assert.Equal(suite.T(), map[string]string{
+			telemetry.ScenarioTag: "checkout",
+			telemetry.ShooterTag:  suite.shooterID,
+		}, collectedSamples[1].Tags())
+	}
+}
+
+func (suite *ClientTestSuite) TestRequestSkipIterationOnError() {
+	assert.PanicsWithError(suite.T(), shooter.ErrIterationSkipped.Error(), func() {
+		suite.client.Execute(rest.Get("http:// invalid url", nil), rest.SkipIterationOnError)
+	})
+}
+
+func (suite *ClientTestSuite) TestRequestSkipIterationOnFailedAssertion() {
+	request := rest.Get(suite.testServer.URL, nil).Expect(rest.StatusIs(404))
+
+	assert.PanicsWithError(suite.T(), shooter.ErrIterationSkipped.Error(), func() {
+		suite.client.Execute(request, rest.SkipIterationOnError)
+	})
+
+	assert.Len(suite.T(), suite.context.SampleCollector().Flush(), 1, "Sample should be collected before skipping")
+}
+
 func (suite *ClientTestSuite) TestRequestMalformedUrl() {
 	malformedUrl := "http:// invalid url"
 
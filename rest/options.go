@@ -8,11 +8,12 @@ const (
 	AllowUnsuccessfulStatuses Option = iota
 	FollowRedirects
 	NoFollowRedirects
+	SkipIterationOnError
 )
 
 func HasOption(optionsList []Option, option Option) bool {
 	sort.Slice(optionsList, func(i, j int) bool { return optionsList[i] < optionsList[j] })
-	index := sort.Search(len(optionsList), func(i int) bool { return optionsList[i] == option })
+	index := sort.Search(len(optionsList), func(i int) bool { return optionsList[i] >= option })
 
-	return index < len(optionsList)
+	return index < len(optionsList) && optionsList[index] == option
 }
@@ -0,0 +1,17 @@
+package rest_test
+
+import (
+	"github.com/steromano87/harkonnen/rest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHasOption(t *testing.T) {
+	options := []rest.Option{rest.SkipIterationOnError, rest.FollowRedirects}
+
+	assert.True(t, rest.HasOption(options, rest.FollowRedirects))
+	assert.True(t, rest.HasOption(options, rest.SkipIterationOnError))
+	assert.False(t, rest.HasOption(options, rest.AllowUnsuccessfulStatuses))
+	assert.False(t, rest.HasOption(options, rest.NoFollowRedirects))
+	assert.False(t, rest.HasOption([]rest.Option{}, rest.FollowRedirects))
+}
@@ -13,6 +13,7 @@ type Request struct {
 	Parameters  *url.Values
 	ContentType string
 	Body        io.Reader
+	Assertions  []Assertion
 }
 
 func Get(url string, parameters *url.Values) Request {
@@ -75,6 +76,11 @@ func Delete(url string, contentType string, body io.Reader) Request {
 	}
 }
 
+func (r Request) Expect(assertions ...Assertion) Request {
+	r.Assertions = append(r.Assertions, assertions...)
+	return r
+}
+
 func (r *Request) Build(baseUrl *url.URL) (*http.Request, error) {
 	completeUrl, err := r.composeUrl(baseUrl, r.Url)
 
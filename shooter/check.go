@@ -0,0 +1,30 @@
+package shooter
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+)
+
+func (c *Context) Check(name string, condition bool) bool {
+	result := telemetry.CheckResult{Name: name, Passed: condition}
+	c.transactions.addCheck(result)
+
+	return c.RecordCheck(result)
+}
+
+func (c *Context) RecordCheck(result telemetry.CheckResult) bool {
+	c.checkCounter.Record(result)
+
+	if !result.Passed {
+		c.logger.Warn().Str("check", result.Name).Msgf("Check failed: %s", result.Message)
+	}
+
+	return result.Passed
+}
+
+func (c *Context) CheckCounter() *telemetry.CheckCounter {
+	return c.checkCounter
+}
+
+func (c *Context) UseCheckCounter(counter *telemetry.CheckCounter) {
+	c.checkCounter = counter
+}
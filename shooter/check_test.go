@@ -0,0 +1,50 @@
+package shooter_test
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+)
+
+type CheckTestSuite struct {
+	suite.Suite
+	context shooter.Context
+}
+
+func (suite *CheckTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	suite.context = shooter.NewContext(context.Background(), logger, "1")
+}
+
+func (suite *CheckTestSuite) TestCheck() {
+	assert.True(suite.T(), suite.context.Check("always true", true))
+	assert.False(suite.T(), suite.context.Check("always false", false))
+
+	assert.Equal(suite.T(), telemetry.CheckCount{Passed: 1}, suite.context.CheckCounter().Count("always true"))
+	assert.Equal(suite.T(), telemetry.CheckCount{Failed: 1}, suite.context.CheckCounter().Count("always false"))
+}
+
+func (suite *CheckTestSuite) TestCheckInsideTransaction() {
+	transaction := suite.context.BeginTransaction("Login")
+	suite.context.Check("logged in", true)
+	sample := transaction.End(nil)
+
+	assert.Equal(suite.T(), []telemetry.CheckResult{{Name: "logged in", Passed: true}}, sample.Checks())
+}
+
+func (suite *CheckTestSuite) TestSharedCheckCounter() {
+	sharedCounter := telemetry.NewCheckCounter()
+	suite.context.UseCheckCounter(sharedCounter)
+	suite.context.Check("shared", true)
+
+	assert.Equal(suite.T(), telemetry.CheckCount{Passed: 1}, sharedCounter.Count("shared"))
+}
+
+func TestCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckTestSuite))
+}
@@ -10,7 +10,12 @@ type Context struct {
 	context.Context
 	id              string
 	variablePool    *VariablePool
+	sharedVariables *VariablePool
 	sampleCollector *telemetry.SampleCollector
+	transactions    *transactionTracker
+	checkCounter    *telemetry.CheckCounter
+	tags            *contextTags
+	iteration       *iterationControl
 	logger          *zerolog.Logger
 	cancelFunc      context.CancelFunc
 }
@@ -18,10 +23,16 @@ type Context struct {
 func NewContext(parent context.Context, parentLogger zerolog.Logger, shooterID string) Context {
 	output := new(Context)
 	output.sampleCollector = new(telemetry.SampleCollector)
+	output.transactions = newTransactionTracker()
+	output.sampleCollector.AddObserver(output.transactions.addChild)
+	output.checkCounter = telemetry.NewCheckCounter()
+	output.iteration = newIterationControl()
+	output.tags = newContextTags()
 	newLogger := parentLogger.With().Str("context", "Shooter").Str("ID", shooterID).Logger()
 	output.logger = &newLogger
 	output.id = shooterID
 	output.variablePool = NewVariablePool(output.logger)
+	output.sharedVariables = NewVariablePool(output.logger)
 	output.Context, output.cancelFunc = context.WithCancel(parent)
 
 	return *output
@@ -35,10 +46,24 @@ func (c *Context) SampleCollector() *telemetry.SampleCollector {
 	return c.sampleCollector
 }
 
+// UseSampleCollector replaces the default collector, e.g. to bound it with the injector settings
+func (c *Context) UseSampleCollector(collector *telemetry.SampleCollector) {
+	collector.AddObserver(c.transactions.addChild)
+	c.sampleCollector = collector
+}
+
 func (c *Context) VariablePool() *VariablePool {
 	return c.variablePool
 }
 
+func (c *Context) SharedVariables() *VariablePool {
+	return c.sharedVariables
+}
+
+func (c *Context) UseSharedVariables(pool *VariablePool) {
+	c.sharedVariables = pool
+}
+
 func (c *Context) ID() string {
 	return c.id
 }
@@ -47,10 +72,6 @@ func (c *Context) Cancel() {
 	c.cancelFunc()
 }
 
-func (c *Context) NextLoop() <-chan struct{} {
-	return make(chan struct{})
-}
-
 func (c *Context) OnUnrecoverableError(err error) {
 	c.logger.Warn().Err(err).Msg("Caught an unrecoverable error, stopping current script execution")
 	panic(err)
@@ -4,10 +4,13 @@ import (
 	"context"
 	"github.com/rs/zerolog"
 	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 type ContextTestSuite struct {
@@ -26,6 +29,64 @@ func (suite *ContextTestSuite) TestNewContext() {
 	assert.IsType(suite.T(), shooter.Context{}, testContext)
 }
 
+func (suite *ContextTestSuite) TestSharedVariables() {
+	firstContext := shooter.NewContext(context.Background(), suite.logger, suite.shooterID)
+	secondContext := shooter.NewContext(context.Background(), suite.logger, "2")
+
+	sharedPool := shooter.NewVariablePool(&suite.logger)
+	firstContext.UseSharedVariables(sharedPool)
+	secondContext.UseSharedVariables(sharedPool)
+
+	firstContext.SharedVariables().Set("token", "abc")
+	actualValue, err := secondContext.SharedVariables().Get("token")
+	assert.Equal(suite.T(), "abc", actualValue)
+	assert.NoError(suite.T(), err)
+
+	_, err = secondContext.VariablePool().Get("token")
+	assert.IsType(suite.T(), shooter.ErrVariableNotFound{}, err)
+}
+
+func (suite *ContextTestSuite) TestTags() {
+	testContext := shooter.NewContext(context.Background(), suite.logger, suite.shooterID)
+	testContext.SetTag(telemetry.InjectorTag, "injector-1")
+
+	assert.Equal(suite.T(), int64(0), testContext.Iteration())
+	assert.Equal(suite.T(), map[string]string{
+		telemetry.InjectorTag: "injector-1",
+		telemetry.ShooterTag:  suite.shooterID,
+	}, testContext.Tags())
+
+	sample := telemetry.NewBaseSample("GET /", time.Now(), time.Now(), 0, 0)
+	sample.SetTag(telemetry.InjectorTag, "overridden")
+	testContext.TagSample(&sample)
+	assert.Equal(suite.T(), "overridden", sample.Tags()[telemetry.InjectorTag])
+	assert.Equal(suite.T(), suite.shooterID, sample.Tags()[telemetry.ShooterTag])
+}
+
+func (suite *ContextTestSuite) TestIterationTag() {
+	wg := sync.WaitGroup{}
+	var tags []map[string]string
+
+	testShooter := shooter.Shooter{
+		Context: shooter.NewContext(context.Background(), suite.logger, suite.shooterID),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			tags = append(tags, ctx.Tags())
+			return nil
+		}},
+		MaxIterations: 2,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	if assert.Len(suite.T(), tags, 2) {
+		assert.Equal(suite.T(), "1", tags[0][telemetry.IterationTag])
+		assert.Equal(suite.T(), "2", tags[1][telemetry.IterationTag])
+	}
+}
+
 func TestContextTestSuite(t *testing.T) {
 	suite.Run(t, new(ContextTestSuite))
 }
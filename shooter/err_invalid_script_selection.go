@@ -0,0 +1,12 @@
+package shooter
+
+import "fmt"
+
+type ErrInvalidScriptSelection struct {
+	Mode   SelectionMode
+	Reason string
+}
+
+func (iss ErrInvalidScriptSelection) Error() string {
+	return fmt.Sprintf("invalid '%s' script selection: %s", iss.Mode, iss.Reason)
+}
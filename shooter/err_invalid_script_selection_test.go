@@ -0,0 +1,20 @@
+package shooter_test
+
+import (
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidScriptSelection_Error(t *testing.T) {
+	myError := shooter.ErrInvalidScriptSelection{
+		Mode:   shooter.WeightedRandomSelection,
+		Reason: "weights cannot be negative",
+	}
+
+	assert.EqualError(
+		t,
+		myError,
+		"invalid 'weighted_random' script selection: weights cannot be negative",
+		"Wrong error message format")
+}
@@ -0,0 +1,12 @@
+package shooter
+
+import "fmt"
+
+type ErrInvalidStatusTransition struct {
+	From Status
+	To   Status
+}
+
+func (ist ErrInvalidStatusTransition) Error() string {
+	return fmt.Sprintf("invalid shooter status transition from %s to %s", ist.From, ist.To)
+}
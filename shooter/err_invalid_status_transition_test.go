@@ -0,0 +1,17 @@
+package shooter_test
+
+import (
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrInvalidStatusTransition_Error(t *testing.T) {
+	myError := shooter.ErrInvalidStatusTransition{From: shooter.Completed, To: shooter.Running}
+
+	assert.EqualError(
+		t,
+		myError,
+		"invalid shooter status transition from COMPLETED to RUNNING",
+		"Wrong error message format")
+}
@@ -0,0 +1,14 @@
+package shooter
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrTearDownTimeout struct {
+	Timeout time.Duration
+}
+
+func (tdt ErrTearDownTimeout) Error() string {
+	return fmt.Sprintf("teardown script did not complete within %s", tdt.Timeout)
+}
@@ -0,0 +1,14 @@
+package shooter_test
+
+import (
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestErrTearDownTimeout_Error(t *testing.T) {
+	myError := shooter.ErrTearDownTimeout{Timeout: 5 * time.Second}
+
+	assert.EqualError(t, myError, "teardown script did not complete within 5s", "Wrong error message format")
+}
@@ -0,0 +1,17 @@
+package shooter
+
+import "fmt"
+
+type ErrUnknownErrorPolicy struct {
+	Policy ErrorPolicy
+}
+
+func (uep ErrUnknownErrorPolicy) Error() string {
+	return fmt.Sprintf(
+		"unknown error policy '%s', expected one of '%s', '%s', '%s' or '%s'",
+		uep.Policy,
+		ContinueIteration,
+		RestartIteration,
+		StopShooter,
+		AbortTest)
+}
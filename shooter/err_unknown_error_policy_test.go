@@ -0,0 +1,17 @@
+package shooter_test
+
+import (
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrUnknownErrorPolicy_Error(t *testing.T) {
+	myError := shooter.ErrUnknownErrorPolicy{Policy: "retry"}
+
+	assert.EqualError(
+		t,
+		myError,
+		"unknown error policy 'retry', expected one of 'continue_iteration', 'restart_iteration', 'stop_shooter' or 'abort_test'",
+		"Wrong error message format")
+}
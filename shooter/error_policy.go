@@ -0,0 +1,69 @@
+package shooter
+
+import (
+	"fmt"
+)
+
+type ErrorPolicy string
+
+const (
+	ContinueIteration ErrorPolicy = "continue_iteration"
+	RestartIteration  ErrorPolicy = "restart_iteration"
+	StopShooter       ErrorPolicy = "stop_shooter"
+	AbortTest         ErrorPolicy = "abort_test"
+)
+
+const (
+	ErrorPolicyEvent = "error_policy"
+
+	setUpPhase    = "setup"
+	mainPhase     = "main loop"
+	tearDownPhase = "teardown"
+)
+
+// Validate accepts the known policies and the empty one, which falls back to the default of each phase
+func (policy ErrorPolicy) Validate() error {
+	switch policy {
+	case "", ContinueIteration, RestartIteration, StopShooter, AbortTest:
+		return nil
+	default:
+		return ErrUnknownErrorPolicy{Policy: policy}
+	}
+}
+
+type policyError struct {
+	cause  error
+	policy ErrorPolicy
+}
+
+func (pe policyError) Error() string {
+	return pe.cause.Error()
+}
+
+func (pe policyError) Unwrap() error {
+	return pe.cause
+}
+
+func WithErrorPolicy(script Script, policy ErrorPolicy) Script {
+	return func(ctx Context) error {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				panic(policyError{cause: recoveredError(recovered), policy: policy})
+			}
+		}()
+
+		if err := script(ctx); err != nil {
+			return policyError{cause: err, policy: policy}
+		}
+
+		return nil
+	}
+}
+
+func recoveredError(recovered interface{}) error {
+	if err, isError := recovered.(error); isError {
+		return err
+	}
+
+	return fmt.Errorf("%v", recovered)
+}
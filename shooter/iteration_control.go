@@ -0,0 +1,83 @@
+package shooter
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrIterationSkipped = errors.New("iteration skipped")
+	ErrLoopBroken       = errors.New("main loop broken")
+)
+
+type iterationControl struct {
+	nextLoop chan struct{}
+	closed   bool
+	number   int64
+	mutex    sync.Mutex
+}
+
+func newIterationControl() *iterationControl {
+	control := new(iterationControl)
+	control.nextLoop = make(chan struct{})
+
+	return control
+}
+
+func (control *iterationControl) reset() {
+	control.mutex.Lock()
+	defer control.mutex.Unlock()
+
+	control.nextLoop = make(chan struct{})
+	control.closed = false
+	control.number++
+}
+
+func (control *iterationControl) current() int64 {
+	control.mutex.Lock()
+	defer control.mutex.Unlock()
+
+	return control.number
+}
+
+func (control *iterationControl) channel() <-chan struct{} {
+	control.mutex.Lock()
+	defer control.mutex.Unlock()
+
+	return control.nextLoop
+}
+
+func (control *iterationControl) signal() {
+	control.mutex.Lock()
+	defer control.mutex.Unlock()
+
+	if !control.closed {
+		close(control.nextLoop)
+		control.closed = true
+	}
+}
+
+// Iteration returns the number of the main loop iteration being executed, starting from 1
+func (c *Context) Iteration() int64 {
+	return c.iteration.current()
+}
+
+func (c *Context) NextLoop() <-chan struct{} {
+	return c.iteration.channel()
+}
+
+func (c *Context) SkipIteration() {
+	c.logger.Info().Msg("Skipping the rest of the current iteration")
+	c.iteration.signal()
+	panic(ErrIterationSkipped)
+}
+
+func (c *Context) BreakLoop() {
+	c.logger.Info().Msg("Breaking out of the main loop")
+	c.iteration.signal()
+	panic(ErrLoopBroken)
+}
+
+func isIterationControl(err error) bool {
+	return errors.Is(err, ErrIterationSkipped) || errors.Is(err, ErrLoopBroken)
+}
@@ -0,0 +1,98 @@
+package shooter
+
+import (
+	"sync"
+	"time"
+)
+
+type lifecycle struct {
+	status               Status
+	timestamps           map[Status]time.Time
+	subscribers          []func(change StatusChange)
+	scheduledForShutdown bool
+	mutex                sync.RWMutex
+}
+
+func (s *Shooter) Status() Status {
+	s.lifecycle.mutex.RLock()
+	defer s.lifecycle.mutex.RUnlock()
+
+	return s.currentStatus()
+}
+
+func (s *Shooter) Subscribe(subscriber func(change StatusChange)) {
+	s.lifecycle.mutex.Lock()
+	defer s.lifecycle.mutex.Unlock()
+
+	s.lifecycle.subscribers = append(s.lifecycle.subscribers, subscriber)
+}
+
+func (s *Shooter) Timestamps() map[Status]time.Time {
+	s.lifecycle.mutex.RLock()
+	defer s.lifecycle.mutex.RUnlock()
+
+	output := make(map[Status]time.Time, len(s.lifecycle.timestamps))
+	for status, timestamp := range s.lifecycle.timestamps {
+		output[status] = timestamp
+	}
+
+	return output
+}
+
+func (s *Shooter) EnteredAt(status Status) (time.Time, bool) {
+	s.lifecycle.mutex.RLock()
+	defer s.lifecycle.mutex.RUnlock()
+
+	timestamp, isPresent := s.lifecycle.timestamps[status]
+	return timestamp, isPresent
+}
+
+func (s *Shooter) transition(target Status) error {
+	s.lifecycle.mutex.Lock()
+
+	current := s.currentStatus()
+	if current == target {
+		s.lifecycle.mutex.Unlock()
+		return nil
+	}
+
+	if !current.CanTransitionTo(target) {
+		s.lifecycle.mutex.Unlock()
+		err := ErrInvalidStatusTransition{From: current, To: target}
+		s.Logger().Debug().Err(err).Msg("Ignoring shooter status transition")
+		return err
+	}
+
+	change := StatusChange{ShooterID: s.ID(), From: current, To: target, At: time.Now()}
+	s.lifecycle.status = target
+	if s.lifecycle.timestamps == nil {
+		s.lifecycle.timestamps = make(map[Status]time.Time)
+	}
+	s.lifecycle.timestamps[target] = change.At
+	subscribers := append([]func(change StatusChange){}, s.lifecycle.subscribers...)
+	s.lifecycle.mutex.Unlock()
+
+	s.Logger().Debug().Str("from", string(change.From)).Str("to", string(change.To)).Msg("Shooter status changed")
+
+	// Subscribers are notified outside the lock so that they can safely query the shooter
+	for _, subscriber := range subscribers {
+		subscriber(change)
+	}
+
+	return nil
+}
+
+func (s *Shooter) isScheduledForShutdown() bool {
+	s.lifecycle.mutex.RLock()
+	defer s.lifecycle.mutex.RUnlock()
+
+	return s.lifecycle.scheduledForShutdown
+}
+
+func (s *Shooter) currentStatus() Status {
+	if s.lifecycle.status == "" {
+		return Created
+	}
+
+	return s.lifecycle.status
+}
@@ -0,0 +1,67 @@
+package shooter
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"sync"
+	"time"
+)
+
+const PauseEvent = "pause"
+
+type pauseGate struct {
+	resumed chan struct{}
+	mutex   sync.Mutex
+}
+
+// Pause freezes the shooter at the next iteration boundary, keeping its variables and connections
+func (s *Shooter) Pause() {
+	s.pause.mutex.Lock()
+	defer s.pause.mutex.Unlock()
+
+	if s.pause.resumed == nil {
+		s.pause.resumed = make(chan struct{})
+		s.Logger().Info().Msg("Shooter will be paused at the end of the current iteration")
+	}
+}
+
+func (s *Shooter) Resume() {
+	s.pause.mutex.Lock()
+	defer s.pause.mutex.Unlock()
+
+	if s.pause.resumed != nil {
+		close(s.pause.resumed)
+		s.pause.resumed = nil
+		s.Logger().Info().Msg("Shooter resumed")
+	}
+}
+
+func (s *Shooter) IsPaused() bool {
+	s.pause.mutex.Lock()
+	defer s.pause.mutex.Unlock()
+
+	return s.pause.resumed != nil
+}
+
+func (s *Shooter) waitWhilePaused() {
+	s.pause.mutex.Lock()
+	resumed := s.pause.resumed
+	s.pause.mutex.Unlock()
+
+	if resumed == nil {
+		return
+	}
+
+	pauseStart := time.Now()
+	_ = s.transition(Paused)
+
+	select {
+	case <-resumed:
+	case <-s.Context.Done():
+	}
+
+	s.SampleCollector().Collect(telemetry.NewEventSample(PauseEvent, pauseStart, time.Now(), map[string]string{}))
+
+	if !s.isScheduledForShutdown() {
+		_ = s.transition(Running)
+	}
+}
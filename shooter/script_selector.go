@@ -0,0 +1,132 @@
+package shooter
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+type ScriptSelector interface {
+	Select(ctx Context, scripts []Script) []Script
+}
+
+type SequentialSelector struct{}
+
+type WeightedRandomSelector struct {
+	weights     []int
+	totalWeight int
+}
+
+type RoundRobinSelector struct {
+	counter uint64
+}
+
+type ConditionalSelector struct {
+	variable string
+	matches  []string
+}
+
+func NewScriptSelector(mode SelectionMode, weights []int, variable string, matches []string) (ScriptSelector, error) {
+	switch mode {
+	case SequentialSelection, "":
+		return SequentialSelector{}, nil
+	case WeightedRandomSelection:
+		return NewWeightedRandomSelector(weights)
+	case RoundRobinSelection:
+		return NewRoundRobinSelector(), nil
+	case ConditionalSelection:
+		return NewConditionalSelector(variable, matches)
+	default:
+		return nil, ErrInvalidScriptSelection{Mode: mode, Reason: "unknown selection mode"}
+	}
+}
+
+func (selector SequentialSelector) Select(ctx Context, scripts []Script) []Script {
+	return scripts
+}
+
+func NewWeightedRandomSelector(weights []int) (*WeightedRandomSelector, error) {
+	selector := new(WeightedRandomSelector)
+	selector.weights = weights
+
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, ErrInvalidScriptSelection{Mode: WeightedRandomSelection, Reason: "weights cannot be negative"}
+		}
+		selector.totalWeight += weight
+	}
+
+	if selector.totalWeight == 0 {
+		return nil, ErrInvalidScriptSelection{Mode: WeightedRandomSelection, Reason: "at least one weight must be positive"}
+	}
+
+	return selector, nil
+}
+
+func (selector *WeightedRandomSelector) Select(ctx Context, scripts []Script) []Script {
+	draw := rand.Intn(selector.totalWeight)
+
+	// Scripts without a matching weight are never selected
+	for index, weight := range selector.weights {
+		if index >= len(scripts) {
+			break
+		}
+
+		if draw < weight {
+			return []Script{scripts[index]}
+		}
+		draw -= weight
+	}
+
+	return []Script{}
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return new(RoundRobinSelector)
+}
+
+func (selector *RoundRobinSelector) Select(ctx Context, scripts []Script) []Script {
+	if len(scripts) == 0 {
+		return scripts
+	}
+
+	next := atomic.AddUint64(&selector.counter, 1) - 1
+	return []Script{scripts[next%uint64(len(scripts))]}
+}
+
+func NewConditionalSelector(variable string, matches []string) (*ConditionalSelector, error) {
+	if variable == "" {
+		return nil, ErrInvalidScriptSelection{Mode: ConditionalSelection, Reason: "selection variable is not set"}
+	}
+
+	selector := new(ConditionalSelector)
+	selector.variable = variable
+	selector.matches = matches
+
+	return selector, nil
+}
+
+func (selector *ConditionalSelector) Select(ctx Context, scripts []Script) []Script {
+	var selected []Script
+	var fallback []Script
+
+	value, err := ctx.VariablePool().GetString(selector.variable)
+	for index, script := range scripts {
+		match := ""
+		if index < len(selector.matches) {
+			match = selector.matches[index]
+		}
+
+		// Scripts without a match value are executed only when no other script matches
+		if match == "" {
+			fallback = append(fallback, script)
+		} else if err == nil && match == value {
+			selected = append(selected, script)
+		}
+	}
+
+	if len(selected) == 0 {
+		return fallback
+	}
+
+	return selected
+}
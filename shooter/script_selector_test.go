@@ -0,0 +1,124 @@
+package shooter_test
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+)
+
+type ScriptSelectorTestSuite struct {
+	suite.Suite
+	context  shooter.Context
+	scripts  []shooter.Script
+	executed []string
+}
+
+func (suite *ScriptSelectorTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	suite.context = shooter.NewContext(context.Background(), logger, "1")
+	suite.executed = []string{}
+	suite.scripts = []shooter.Script{}
+
+	for _, name := range []string{"browse", "search", "buy"} {
+		scriptName := name
+		suite.scripts = append(suite.scripts, func(ctx shooter.Context) error {
+			suite.executed = append(suite.executed, scriptName)
+			return nil
+		})
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) run(selector shooter.ScriptSelector, iterations int) {
+	for index := 0; index < iterations; index++ {
+		for _, script := range selector.Select(suite.context, suite.scripts) {
+			_ = script(suite.context)
+		}
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestSequentialSelector() {
+	selector, err := shooter.NewScriptSelector(shooter.SequentialSelection, nil, "", nil)
+
+	if assert.NoError(suite.T(), err) {
+		suite.run(selector, 2)
+		assert.Equal(suite.T(), []string{"browse", "search", "buy", "browse", "search", "buy"}, suite.executed)
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestDefaultSelector() {
+	selector, err := shooter.NewScriptSelector("", nil, "", nil)
+
+	if assert.NoError(suite.T(), err) {
+		assert.IsType(suite.T(), shooter.SequentialSelector{}, selector)
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestWeightedRandomSelector() {
+	selector, err := shooter.NewScriptSelector(shooter.WeightedRandomSelection, []int{70, 30, 0}, "", nil)
+
+	if assert.NoError(suite.T(), err) {
+		suite.run(selector, 1000)
+
+		counts := make(map[string]int)
+		for _, name := range suite.executed {
+			counts[name]++
+		}
+
+		assert.Len(suite.T(), suite.executed, 1000)
+		assert.InDelta(suite.T(), 700, counts["browse"], 100)
+		assert.InDelta(suite.T(), 300, counts["search"], 100)
+		assert.Zero(suite.T(), counts["buy"])
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestWeightedRandomSelectorInvalidWeights() {
+	_, err := shooter.NewScriptSelector(shooter.WeightedRandomSelection, []int{1, -1, 0}, "", nil)
+	assert.IsType(suite.T(), shooter.ErrInvalidScriptSelection{}, err)
+
+	_, err = shooter.NewScriptSelector(shooter.WeightedRandomSelection, []int{0, 0, 0}, "", nil)
+	assert.IsType(suite.T(), shooter.ErrInvalidScriptSelection{}, err)
+}
+
+func (suite *ScriptSelectorTestSuite) TestRoundRobinSelector() {
+	selector, err := shooter.NewScriptSelector(shooter.RoundRobinSelection, nil, "", nil)
+
+	if assert.NoError(suite.T(), err) {
+		suite.run(selector, 4)
+		assert.Equal(suite.T(), []string{"browse", "search", "buy", "browse"}, suite.executed)
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestConditionalSelector() {
+	selector, err := shooter.NewScriptSelector(shooter.ConditionalSelection, nil, "step", []string{"", "search", "buy"})
+
+	if assert.NoError(suite.T(), err) {
+		suite.context.VariablePool().Set("step", "buy")
+		suite.run(selector, 1)
+
+		suite.context.VariablePool().Set("step", "unknown")
+		suite.run(selector, 1)
+
+		suite.context.VariablePool().Delete("step")
+		suite.run(selector, 1)
+
+		assert.Equal(suite.T(), []string{"buy", "browse", "browse"}, suite.executed)
+	}
+}
+
+func (suite *ScriptSelectorTestSuite) TestConditionalSelectorWithoutVariable() {
+	_, err := shooter.NewScriptSelector(shooter.ConditionalSelection, nil, "", nil)
+	assert.IsType(suite.T(), shooter.ErrInvalidScriptSelection{}, err)
+}
+
+func (suite *ScriptSelectorTestSuite) TestUnknownSelectionMode() {
+	_, err := shooter.NewScriptSelector("shuffle", nil, "", nil)
+	assert.IsType(suite.T(), shooter.ErrInvalidScriptSelection{}, err)
+}
+
+func TestScriptSelectorTestSuite(t *testing.T) {
+	suite.Run(t, new(ScriptSelectorTestSuite))
+}
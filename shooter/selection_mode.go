@@ -0,0 +1,10 @@
+package shooter
+
+type SelectionMode string
+
+const (
+	SequentialSelection     SelectionMode = "sequential"
+	WeightedRandomSelection SelectionMode = "weighted_random"
+	RoundRobinSelection     SelectionMode = "round_robin"
+	ConditionalSelection    SelectionMode = "conditional"
+)
@@ -1,152 +1,338 @@
 package shooter
 
 import (
+	"errors"
+	"github.com/steromano87/harkonnen/feeder"
+	"github.com/steromano87/harkonnen/telemetry"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Shooter struct {
 	Context
-	SetUpScript    Script
-	MainScripts    []Script
-	TearDownScript Script
-	MaxIterations  int
-	WaitGroup      *sync.WaitGroup
+	SetUpScript     Script
+	MainScripts     []Script
+	Selector        ScriptSelector
+	TearDownScript  Script
+	MaxIterations   int
+	Pacing          time.Duration
+	Feeders         []*feeder.Feeder
+	ErrorPolicy     ErrorPolicy
+	OnAbort         func(err error)
+	TearDownTimeout time.Duration
+	WaitGroup       *sync.WaitGroup
 
-	status               Status
-	totalIterations      int
-	successfulIterations int
-	scheduledForShutdown bool
+	lifecycle            lifecycle
+	shutdown             shutdown
+	pause                pauseGate
+	totalIterations      int64
+	successfulIterations int64
+	skippedIterations    int64
+	stoppedOnError       bool
+	loopBroken           bool
 }
 
 func (s *Shooter) Start() {
+	// The status is changed before launching the goroutine, so that callers never observe a stale status
+	_ = s.transition(SettingUp)
 	go s.run()
-	s.status = Running
 	s.Logger().Info().Msg("Shooter started")
 }
 
 func (s *Shooter) ScheduleShutDown() {
 	s.Logger().Info().Msg("Shooter marked for shutdown")
-	s.scheduledForShutdown = true
-	s.status = ShuttingDown
+	s.lifecycle.mutex.Lock()
+	s.lifecycle.scheduledForShutdown = true
+	s.lifecycle.mutex.Unlock()
+
+	// A paused shooter must wake up to run its teardown
+	s.Resume()
+
+	_ = s.transition(ShuttingDown)
 }
 
 func (s *Shooter) TotalIterations() int {
-	return s.totalIterations
+	return int(atomic.LoadInt64(&s.totalIterations))
 }
 
 func (s *Shooter) SuccessfulIterations() int {
-	return s.successfulIterations
+	return int(atomic.LoadInt64(&s.successfulIterations))
 }
 
-func (s *Shooter) run() {
-	defer s.WaitGroup.Done()
+func (s *Shooter) SkippedIterations() int {
+	return int(atomic.LoadInt64(&s.skippedIterations))
+}
 
-	// Setup script execution
+func (s *Shooter) FailedIterations() int {
+	return s.TotalIterations() - s.SuccessfulIterations() - s.SkippedIterations()
+}
+
+func (s *Shooter) SetUp() bool {
+	_ = s.transition(SettingUp)
 	s.executeSetupScript()
-	if s.status == Error {
-		return
+	if s.Status() == Error {
+		return false
 	}
 
-	// Main loop
-	s.executeMainScripts()
+	// A shutdown may have been requested during setup, in this case the shooter never starts running
+	if !s.isScheduledForShutdown() {
+		_ = s.transition(Running)
+	}
+	return true
+}
 
-	// Teardown script execution
+func (s *Shooter) Iterate() bool {
+	// Pooled shooters are not run by their own loop, so the pause is honored here as well
+	s.waitWhilePaused()
+	if s.isScheduledForShutdown() {
+		return false
+	}
+
+	if !s.bindFeeders() {
+		return false
+	}
+
+	s.executeMainScriptsLoop()
+	return !s.isScheduledForShutdown() && !s.loopBroken
+}
+
+func (s *Shooter) TearDown() bool {
+	_ = s.transition(TearingDown)
 	s.executeTearDownScript()
-	if s.status == Error {
-		return
+	if s.Status() == Error {
+		return false
 	}
 
-	if s.scheduledForShutdown {
-		s.status = Stopped
+	if s.stoppedOnError {
+		_ = s.transition(Error)
+	} else if s.isScheduledForShutdown() {
+		_ = s.transition(Stopped)
 	} else {
-		s.status = Completed
+		_ = s.transition(Completed)
+	}
+	return true
+}
+
+func (s *Shooter) run() {
+	defer s.finish()
+
+	// Setup script execution
+	if !s.SetUp() {
+		return
 	}
+
+	// Main loop
+	s.executeMainScripts()
+
+	// Teardown script execution
+	s.TearDown()
 }
 
 func (s *Shooter) executeSetupScript() {
 	if s.SetUpScript != nil {
-		defer s.handleSetUpTearDownPanic()
-
 		s.Logger().Info().Msg("Started setup script execution")
-		err := s.SetUpScript(s.Context)
+		err := s.runScript(s.SetUpScript)
 		s.Logger().Info().Msg("Setup script execution completed")
 
-		if err != nil {
-			s.OnUnrecoverableError(err)
+		if err != nil && !isIterationControl(err) {
+			s.applySetUpTearDownErrorPolicy(setUpPhase, err)
 		}
 	}
 }
 
 func (s *Shooter) executeMainScripts() {
 	if len(s.MainScripts) > 0 {
-		for !s.scheduledForShutdown && (s.totalIterations < s.MaxIterations || s.MaxIterations == 0) {
-			s.executeMainScriptsLoop()
+		for !s.isScheduledForShutdown() && (s.TotalIterations() < s.MaxIterations || s.MaxIterations == 0) {
+			s.waitWhilePaused()
+			if s.isScheduledForShutdown() {
+				break
+			}
+
+			iterationStart := time.Now()
+			if !s.Iterate() {
+				break
+			}
+
+			s.waitForPacing(iterationStart)
 		}
 	}
 }
 
-func (s *Shooter) executeMainScriptsLoop() {
-	defer s.handleMainLoopPanic()
+func (s *Shooter) bindFeeders() bool {
+	for _, dataFeeder := range s.Feeders {
+		row, err := dataFeeder.Next()
+		if err != nil {
+			s.Logger().Info().Err(err).Msg("Feeder data exhausted, no more iterations will be executed")
+			s.ScheduleShutDown()
+			return false
+		}
+
+		for column, value := range row {
+			s.VariablePool().Set(column, value)
+		}
+	}
+
+	return true
+}
+
+func (s *Shooter) waitForPacing(iterationStart time.Time) {
+	if s.Pacing <= 0 || s.isScheduledForShutdown() {
+		return
+	}
+
+	remaining := s.Pacing - time.Since(iterationStart)
+	if remaining <= 0 {
+		s.Logger().Debug().Msgf("Iteration lasted longer than pacing (%s), starting next one immediately", s.Pacing)
+		return
+	}
+
+	_ = s.Context.sleep(remaining)
+}
 
-	var err error
+func (s *Shooter) executeMainScriptsLoop() {
+	s.Context.iteration.reset()
+	iterationFailed := false
+	iterationSkipped := false
 
-	for _, mainScript := range s.MainScripts {
+MainScripts:
+	for _, mainScript := range s.selectMainScripts() {
 		// Check for termination at every loop
 		select {
 		case <-s.Context.Done():
-			s.status = ShuttingDown
+			s.ScheduleShutDown()
 			return
 
 		case <-s.Context.NextLoop():
-			break
+			iterationSkipped = true
+			break MainScripts
 
 		default:
 		}
 
-		err := mainScript(s.Context)
-		// Exit from inner loop in case of error while executing one of the scripts
-		if err != nil {
-			s.OnUnrecoverableError(err)
+		err := s.runScript(mainScript)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, ErrIterationSkipped) {
+			iterationSkipped = true
+			break
 		}
+
+		if errors.Is(err, ErrLoopBroken) {
+			iterationSkipped = true
+			s.loopBroken = true
+			break
+		}
+
+		iterationFailed = true
+		policy := s.resolveErrorPolicy(err, mainPhase)
+		s.recordErrorPolicy(mainPhase, policy, err)
+
+		if policy == ContinueIteration {
+			continue
+		}
+
+		if policy != RestartIteration {
+			s.stopOnError(policy, err)
+		}
+		break
 	}
 
-	s.totalIterations++
+	atomic.AddInt64(&s.totalIterations, 1)
+
+	switch {
+	case iterationSkipped:
+		atomic.AddInt64(&s.skippedIterations, 1)
+	case !iterationFailed:
+		atomic.AddInt64(&s.successfulIterations, 1)
+	}
+}
 
-	if err == nil {
-		s.successfulIterations++
+func (s *Shooter) selectMainScripts() []Script {
+	if s.Selector == nil {
+		return s.MainScripts
 	}
+
+	return s.Selector.Select(s.Context, s.MainScripts)
 }
 
 func (s *Shooter) executeTearDownScript() {
 	if s.TearDownScript != nil {
-		defer s.handleSetUpTearDownPanic()
-
 		s.Logger().Info().Msg("Started teardown script execution")
-		err := s.TearDownScript(s.Context)
+		err := s.runTearDownScript()
 		s.Logger().Info().Msg("Teardown script execution completed")
 
-		if err != nil {
-			s.OnUnrecoverableError(err)
+		if err != nil && !isIterationControl(err) {
+			s.applySetUpTearDownErrorPolicy(tearDownPhase, err)
 		}
 	}
 }
 
-func (s *Shooter) handleSetUpTearDownPanic() {
-	if err := recover(); err != nil {
-		s.Logger().Error().Stack().Err(err.(error)).Msg("Encountered error during setup/teardown sequence, stopping execution")
-		s.status = Error
-		return
+func (s *Shooter) runScript(script Script) error {
+	return s.runScriptIn(s.Context, script)
+}
+
+func (s *Shooter) runScriptIn(ctx Context, script Script) (err error) {
+	// Scripts can abort their execution by panicking (e.g. via OnUnrecoverableError)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoveredError(recovered)
+		}
+	}()
+
+	return script(ctx)
+}
+
+func (s *Shooter) resolveErrorPolicy(err error, phase string) ErrorPolicy {
+	var scriptPolicyErr policyError
+	if errors.As(err, &scriptPolicyErr) {
+		return scriptPolicyErr.policy
+	}
+
+	if s.ErrorPolicy != "" {
+		return s.ErrorPolicy
 	}
+
+	if phase == mainPhase {
+		return RestartIteration
+	}
+
+	return StopShooter
+}
+
+func (s *Shooter) recordErrorPolicy(phase string, policy ErrorPolicy, err error) {
+	s.Logger().Error().Stack().Err(err).Str("phase", phase).Str("policy", string(policy)).
+		Msgf("Encountered error during %s, applying '%s' error policy", phase, policy)
+
+	now := time.Now()
+	s.SampleCollector().Collect(telemetry.NewEventSample(ErrorPolicyEvent, now, now, map[string]string{
+		"phase":  phase,
+		"policy": string(policy),
+		"error":  err.Error(),
+	}))
 }
 
-func (s *Shooter) handleMainLoopPanic() {
-	if err := recover(); err != nil {
-		s.Logger().Error().Stack().Err(err.(error)).Msg("Encountered error during main loop, continuing with next iteration")
-		s.totalIterations++
+func (s *Shooter) applySetUpTearDownErrorPolicy(phase string, err error) {
+	policy := s.resolveErrorPolicy(err, phase)
+	s.recordErrorPolicy(phase, policy, err)
+
+	if policy == ContinueIteration || policy == RestartIteration {
 		return
 	}
+
+	if policy == AbortTest && s.OnAbort != nil {
+		s.OnAbort(err)
+	}
+	_ = s.transition(Error)
 }
 
-func (s *Shooter) Status() Status {
-	return s.status
+func (s *Shooter) stopOnError(policy ErrorPolicy, err error) {
+	if policy == AbortTest && s.OnAbort != nil {
+		s.OnAbort(err)
+	}
+
+	s.stoppedOnError = true
+	s.ScheduleShutDown()
 }
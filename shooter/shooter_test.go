@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/feeder"
 	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"os"
 	"sync"
 	"testing"
+	"time"
 )
 
 type SampleClient struct {
@@ -75,6 +78,14 @@ func (suite *ShooterTestSuite) createContext() shooter.Context {
 	return shooter.NewContext(context.Background(), suite.logger, suite.shooterID)
 }
 
+// assertReached waits for a status, which may already be left behind by the time it is checked
+func (suite *ShooterTestSuite) assertReached(testShooter *shooter.Shooter, status shooter.Status) {
+	assert.Eventually(suite.T(), func() bool {
+		_, isReached := testShooter.EnteredAt(status)
+		return isReached
+	}, time.Second, time.Millisecond)
+}
+
 func (suite *ShooterTestSuite) TestStart() {
 	wg := sync.WaitGroup{}
 
@@ -89,7 +100,7 @@ func (suite *ShooterTestSuite) TestStart() {
 
 	wg.Add(1)
 	testShooter.Start()
-	assert.Equal(suite.T(), shooter.Running, testShooter.Status())
+	suite.assertReached(&testShooter, shooter.Running)
 
 	wg.Wait()
 	assert.Equal(suite.T(), 3, testShooter.TotalIterations())
@@ -111,7 +122,7 @@ func (suite *ShooterTestSuite) TestExplicitErrorInMainLoop() {
 
 	wg.Add(1)
 	testShooter.Start()
-	assert.Equal(suite.T(), shooter.Running, testShooter.Status())
+	suite.assertReached(&testShooter, shooter.Running)
 
 	wg.Wait()
 	assert.Equal(suite.T(), 3, testShooter.TotalIterations())
@@ -133,7 +144,7 @@ func (suite *ShooterTestSuite) TestImplicitErrorInMainLoop() {
 
 	wg.Add(1)
 	testShooter.Start()
-	assert.Equal(suite.T(), shooter.Running, testShooter.Status())
+	suite.assertReached(&testShooter, shooter.Running)
 
 	wg.Wait()
 	assert.Equal(suite.T(), 3, testShooter.TotalIterations())
@@ -155,12 +166,14 @@ func (suite *ShooterTestSuite) TestImplicitErrorInSetupScript() {
 
 	wg.Add(1)
 	testShooter.Start()
-	assert.Equal(suite.T(), shooter.Running, testShooter.Status())
+	suite.assertReached(&testShooter, shooter.Error)
 
 	wg.Wait()
 	assert.Equal(suite.T(), 0, testShooter.TotalIterations())
 	assert.Equal(suite.T(), 0, testShooter.SuccessfulIterations())
 	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
+	_, wasRunning := testShooter.EnteredAt(shooter.Running)
+	assert.False(suite.T(), wasRunning, "a shooter failing its setup never runs")
 }
 
 func (suite *ShooterTestSuite) TestImplicitErrorInTearDownScript() {
@@ -177,7 +190,7 @@ func (suite *ShooterTestSuite) TestImplicitErrorInTearDownScript() {
 
 	wg.Add(1)
 	testShooter.Start()
-	assert.Equal(suite.T(), shooter.Running, testShooter.Status())
+	suite.assertReached(&testShooter, shooter.Running)
 
 	wg.Wait()
 	assert.Equal(suite.T(), 3, testShooter.TotalIterations())
@@ -185,6 +198,625 @@ func (suite *ShooterTestSuite) TestImplicitErrorInTearDownScript() {
 	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
 }
 
+func (suite *ShooterTestSuite) TestFeederBinding() {
+	wg := sync.WaitGroup{}
+	rows := []feeder.Row{{"username": "alice"}, {"username": "bob"}}
+	var usernames []string
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			username, err := ctx.VariablePool().GetString("username")
+			usernames = append(usernames, username)
+			return err
+		}},
+		MaxIterations: 3,
+		Feeders:       []*feeder.Feeder{feeder.New("users", rows, feeder.Circular, feeder.PerShooter)},
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), []string{"alice", "bob", "alice"}, usernames)
+	assert.Equal(suite.T(), 3, testShooter.SuccessfulIterations())
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestFeederExhaustion() {
+	wg := sync.WaitGroup{}
+	rows := []feeder.Row{{"username": "alice"}, {"username": "bob"}}
+
+	testShooter := shooter.Shooter{
+		Context:        suite.createContext(),
+		MainScripts:    []shooter.Script{suite.mainScriptOne},
+		TearDownScript: suite.tearDownScript,
+		MaxIterations:  5,
+		Feeders:        []*feeder.Feeder{feeder.New("users", rows, feeder.Unique, feeder.PerShooter)},
+		WaitGroup:      &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 2, testShooter.TotalIterations())
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestPacing() {
+	wg := sync.WaitGroup{}
+
+	testShooter := shooter.Shooter{
+		Context:       suite.createContext(),
+		MainScripts:   []shooter.Script{suite.mainScriptOne},
+		MaxIterations: 3,
+		Pacing:        30 * time.Millisecond,
+		WaitGroup:     &wg,
+	}
+
+	start := time.Now()
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.GreaterOrEqual(suite.T(), time.Since(start), 90*time.Millisecond)
+	assert.Equal(suite.T(), 3, testShooter.SuccessfulIterations())
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestContinueIterationPolicy() {
+	wg := sync.WaitGroup{}
+	executions := 0
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{suite.explicitErrorScript, func(ctx shooter.Context) error {
+			executions++
+			return nil
+		}},
+		MaxIterations: 3,
+		ErrorPolicy:   shooter.ContinueIteration,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 3, executions, "Scripts following the failed one should be executed")
+	assert.Equal(suite.T(), 3, testShooter.TotalIterations())
+	assert.Equal(suite.T(), 0, testShooter.SuccessfulIterations())
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestStopShooterPolicy() {
+	wg := sync.WaitGroup{}
+	tearDownExecuted := false
+
+	testShooter := shooter.Shooter{
+		Context:     suite.createContext(),
+		MainScripts: []shooter.Script{suite.implicitErrorScript, suite.mainScriptTwo},
+		TearDownScript: func(ctx shooter.Context) error {
+			tearDownExecuted = true
+			return nil
+		},
+		MaxIterations: 3,
+		ErrorPolicy:   shooter.StopShooter,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 1, testShooter.TotalIterations())
+	assert.True(suite.T(), tearDownExecuted)
+	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestAbortTestPolicy() {
+	wg := sync.WaitGroup{}
+	var abortError error
+
+	testShooter := shooter.Shooter{
+		Context:       suite.createContext(),
+		MainScripts:   []shooter.Script{suite.explicitErrorScript},
+		MaxIterations: 3,
+		ErrorPolicy:   shooter.AbortTest,
+		OnAbort: func(err error) {
+			abortError = err
+		},
+		WaitGroup: &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.EqualError(suite.T(), abortError, "triggered error")
+	assert.Equal(suite.T(), 1, testShooter.TotalIterations())
+	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestScriptErrorPolicyOverride() {
+	wg := sync.WaitGroup{}
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{
+			suite.mainScriptOne,
+			shooter.WithErrorPolicy(suite.implicitErrorScript, shooter.StopShooter),
+		},
+		MaxIterations: 3,
+		ErrorPolicy:   shooter.RestartIteration,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 1, testShooter.TotalIterations())
+	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
+
+	var policyEvents []telemetry.EventSample
+	for _, sample := range testShooter.SampleCollector().Flush() {
+		if event, isEvent := sample.(telemetry.EventSample); isEvent && event.Name() == shooter.ErrorPolicyEvent {
+			policyEvents = append(policyEvents, event)
+		}
+	}
+
+	if assert.Len(suite.T(), policyEvents, 1) {
+		assert.Equal(suite.T(), string(shooter.StopShooter), policyEvents[0].Attributes["policy"])
+		assert.Equal(suite.T(), "main loop", policyEvents[0].Attributes["phase"])
+		assert.Equal(suite.T(), "sample error", policyEvents[0].Attributes["error"])
+	}
+}
+
+func (suite *ShooterTestSuite) TestSetupErrorWithContinuePolicy() {
+	wg := sync.WaitGroup{}
+
+	testShooter := shooter.Shooter{
+		Context:       suite.createContext(),
+		SetUpScript:   suite.explicitErrorScript,
+		MainScripts:   []shooter.Script{suite.mainScriptOne},
+		MaxIterations: 2,
+		ErrorPolicy:   shooter.ContinueIteration,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 2, testShooter.SuccessfulIterations())
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestSkipIteration() {
+	wg := sync.WaitGroup{}
+	executions := 0
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{
+			func(ctx shooter.Context) error {
+				if executions++; executions%2 == 0 {
+					ctx.SkipIteration()
+				}
+				return nil
+			},
+			suite.mainScriptTwo,
+		},
+		MaxIterations: 4,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 4, testShooter.TotalIterations())
+	assert.Equal(suite.T(), 2, testShooter.SuccessfulIterations())
+	assert.Equal(suite.T(), 2, testShooter.SkippedIterations())
+	assert.Equal(suite.T(), 0, testShooter.FailedIterations())
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestNextLoopSignal() {
+	wg := sync.WaitGroup{}
+	signalled := 0
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{
+			func(ctx shooter.Context) error {
+				nextLoop := ctx.NextLoop()
+				defer func() {
+					select {
+					case <-nextLoop:
+						signalled++
+					default:
+					}
+				}()
+
+				ctx.SkipIteration()
+				return nil
+			},
+		},
+		MaxIterations: 2,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 2, signalled, "NextLoop channel should be closed when skipping")
+	assert.Equal(suite.T(), 2, testShooter.SkippedIterations())
+}
+
+func (suite *ShooterTestSuite) TestBreakLoop() {
+	wg := sync.WaitGroup{}
+	tearDownExecuted := false
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{
+			suite.mainScriptOne,
+			func(ctx shooter.Context) error {
+				ctx.BreakLoop()
+				return nil
+			},
+		},
+		TearDownScript: func(ctx shooter.Context) error {
+			tearDownExecuted = true
+			return nil
+		},
+		MaxIterations: 5,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 1, testShooter.TotalIterations())
+	assert.Equal(suite.T(), 1, testShooter.SkippedIterations())
+	assert.True(suite.T(), tearDownExecuted)
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestFailedIterations() {
+	wg := sync.WaitGroup{}
+
+	testShooter := shooter.Shooter{
+		Context:       suite.createContext(),
+		MainScripts:   []shooter.Script{suite.explicitErrorScript},
+		MaxIterations: 3,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), 3, testShooter.FailedIterations())
+	assert.Equal(suite.T(), 0, testShooter.SkippedIterations())
+}
+
+func (suite *ShooterTestSuite) TestRoundRobinSelection() {
+	wg := sync.WaitGroup{}
+	executions := make(map[string]int)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{
+			func(ctx shooter.Context) error {
+				executions["first"]++
+				return nil
+			},
+			func(ctx shooter.Context) error {
+				executions["second"]++
+				return nil
+			},
+		},
+		Selector:      shooter.NewRoundRobinSelector(),
+		MaxIterations: 4,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), map[string]int{"first": 2, "second": 2}, executions)
+	assert.Equal(suite.T(), 4, testShooter.SuccessfulIterations())
+}
+
+func (suite *ShooterTestSuite) TestStatusTransitions() {
+	wg := sync.WaitGroup{}
+	var changes []shooter.StatusChange
+	var mutex sync.Mutex
+
+	testShooter := shooter.Shooter{
+		Context:        suite.createContext(),
+		SetUpScript:    suite.setUpScript,
+		MainScripts:    []shooter.Script{suite.mainScriptOne},
+		TearDownScript: suite.tearDownScript,
+		MaxIterations:  2,
+		WaitGroup:      &wg,
+	}
+	testShooter.Subscribe(func(change shooter.StatusChange) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		changes = append(changes, change)
+	})
+
+	assert.Equal(suite.T(), shooter.Created, testShooter.Status())
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	var visited []shooter.Status
+	for _, change := range changes {
+		assert.Equal(suite.T(), suite.shooterID, change.ShooterID)
+		visited = append(visited, change.To)
+	}
+
+	assert.Equal(
+		suite.T(),
+		[]shooter.Status{shooter.SettingUp, shooter.Running, shooter.TearingDown, shooter.Completed},
+		visited)
+
+	timestamps := testShooter.Timestamps()
+	assert.Len(suite.T(), timestamps, 4)
+	assert.False(suite.T(), timestamps[shooter.Completed].Before(timestamps[shooter.SettingUp]))
+}
+
+func (suite *ShooterTestSuite) TestScheduledShutDownTransitions() {
+	wg := sync.WaitGroup{}
+	var visited []shooter.Status
+
+	testShooter := shooter.Shooter{
+		Context:     suite.createContext(),
+		MainScripts: []shooter.Script{suite.mainScriptOne},
+		WaitGroup:   &wg,
+	}
+	testShooter.Subscribe(func(change shooter.StatusChange) {
+		visited = append(visited, change.To)
+	})
+	testShooter.MainScripts = []shooter.Script{func(ctx shooter.Context) error {
+		testShooter.ScheduleShutDown()
+		return nil
+	}}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(
+		suite.T(),
+		[]shooter.Status{shooter.SettingUp, shooter.Running, shooter.ShuttingDown, shooter.TearingDown, shooter.Stopped},
+		visited)
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+
+	_, hasErrorTimestamp := testShooter.EnteredAt(shooter.Error)
+	assert.False(suite.T(), hasErrorTimestamp)
+}
+
+func (suite *ShooterTestSuite) TestGracefulStop() {
+	wg := sync.WaitGroup{}
+	tearDownExecuted := make(chan struct{}, 1)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			return ctx.Think(5 * time.Millisecond)
+		}},
+		TearDownScript: func(ctx shooter.Context) error {
+			tearDownExecuted <- struct{}{}
+			return nil
+		},
+		WaitGroup: &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(suite.T(), shooter.Graceful, testShooter.Stop(time.Second))
+	wg.Wait()
+
+	assert.Len(suite.T(), tearDownExecuted, 1)
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestForcedStop() {
+	wg := sync.WaitGroup{}
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			// Simulates a script that does not honour the context cancellation
+			started <- struct{}{}
+			<-unblock
+			return nil
+		}},
+		WaitGroup: &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	<-started
+
+	assert.Equal(suite.T(), shooter.Forced, testShooter.Stop(20*time.Millisecond))
+	wg.Wait()
+
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+	assert.Error(suite.T(), testShooter.Context.Err())
+}
+
+func (suite *ShooterTestSuite) TestForcedStopCancelsContext() {
+	wg := sync.WaitGroup{}
+	started := make(chan struct{}, 1)
+	tearDownContextErr := make(chan error, 1)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		TearDownScript: func(ctx shooter.Context) error {
+			tearDownContextErr <- ctx.Err()
+			return nil
+		},
+		WaitGroup: &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	<-started
+
+	assert.Equal(suite.T(), shooter.Forced, testShooter.Stop(20*time.Millisecond))
+	<-testShooter.Finished()
+
+	// A forced shutdown cancels the context of the shooter, which is still exposed through Done
+	<-testShooter.Done()
+
+	// Teardown still runs on a live context after the shooter context has been cancelled
+	assert.NoError(suite.T(), <-tearDownContextErr)
+}
+
+func (suite *ShooterTestSuite) TestTearDownTimeout() {
+	wg := sync.WaitGroup{}
+
+	testShooter := shooter.Shooter{
+		Context:       suite.createContext(),
+		MainScripts:   []shooter.Script{suite.mainScriptOne},
+		MaxIterations: 1,
+		TearDownScript: func(ctx shooter.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		TearDownTimeout: 20 * time.Millisecond,
+		WaitGroup:       &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	wg.Wait()
+
+	assert.Equal(suite.T(), shooter.Error, testShooter.Status())
+	assert.Equal(suite.T(), shooter.Forced, testShooter.ShutdownOutcome())
+}
+
+func (suite *ShooterTestSuite) TestStopBeforeStart() {
+	testShooter := shooter.Shooter{
+		Context:     suite.createContext(),
+		MainScripts: []shooter.Script{suite.mainScriptOne},
+	}
+
+	assert.Equal(suite.T(), shooter.Graceful, testShooter.Stop(time.Second))
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+}
+
+func (suite *ShooterTestSuite) TestPauseAndResume() {
+	wg := sync.WaitGroup{}
+	iterations := make(chan int, 100)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			counter, err := ctx.VariablePool().Increment("counter", 1)
+			iterations <- counter
+			_ = ctx.Think(time.Millisecond)
+			return err
+		}},
+		MaxIterations: 50,
+		WaitGroup:     &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	<-iterations
+
+	testShooter.Pause()
+	assert.Eventually(suite.T(), func() bool {
+		return testShooter.Status() == shooter.Paused
+	}, time.Second, time.Millisecond)
+
+	frozenIterations := testShooter.TotalIterations()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(suite.T(), frozenIterations, testShooter.TotalIterations())
+
+	testShooter.Resume()
+	wg.Wait()
+
+	// Variables survive the pause, so the counter keeps growing across it
+	counter, err := testShooter.VariablePool().GetInt("counter")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 50, counter)
+	assert.Equal(suite.T(), shooter.Completed, testShooter.Status())
+
+	var pauses []telemetry.Sample
+	for _, sample := range testShooter.SampleCollector().Flush() {
+		if sample.Name() == shooter.PauseEvent {
+			pauses = append(pauses, sample)
+		}
+	}
+	if assert.Len(suite.T(), pauses, 1) {
+		assert.GreaterOrEqual(suite.T(), pauses[0].Duration(), 20*time.Millisecond)
+	}
+}
+
+func (suite *ShooterTestSuite) TestStopWhilePaused() {
+	wg := sync.WaitGroup{}
+	started := make(chan struct{}, 1)
+
+	testShooter := shooter.Shooter{
+		Context: suite.createContext(),
+		MainScripts: []shooter.Script{func(ctx shooter.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			return ctx.Think(time.Millisecond)
+		}},
+		WaitGroup: &wg,
+	}
+
+	wg.Add(1)
+	testShooter.Start()
+	<-started
+
+	testShooter.Pause()
+	assert.Eventually(suite.T(), func() bool {
+		return testShooter.Status() == shooter.Paused
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(suite.T(), shooter.Graceful, testShooter.Stop(time.Second))
+	wg.Wait()
+	assert.Equal(suite.T(), shooter.Stopped, testShooter.Status())
+}
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, shooter.Created.CanTransitionTo(shooter.SettingUp))
+	assert.True(t, shooter.Running.CanTransitionTo(shooter.ShuttingDown))
+	assert.False(t, shooter.Completed.CanTransitionTo(shooter.Running))
+	assert.False(t, shooter.Created.CanTransitionTo(shooter.Completed))
+	assert.True(t, shooter.Stopped.IsFinal())
+	assert.False(t, shooter.TearingDown.IsFinal())
+}
+
 func TestShooterTestSuite(t *testing.T) {
 	suite.Run(t, new(ShooterTestSuite))
 }
@@ -0,0 +1,150 @@
+package shooter
+
+import (
+	"context"
+	"github.com/steromano87/harkonnen/telemetry"
+	"sync"
+	"time"
+)
+
+type ShutdownOutcome string
+
+const (
+	Graceful ShutdownOutcome = "graceful"
+	Forced   ShutdownOutcome = "forced"
+)
+
+const ShutdownEvent = "shutdown"
+
+type shutdown struct {
+	done        chan struct{}
+	outcome     ShutdownOutcome
+	initOnce    sync.Once
+	doneOnce    sync.Once
+	releaseOnce sync.Once
+	mutex       sync.Mutex
+}
+
+// Stop asks the shooter to finish its current iteration and run the teardown script.
+// If the shooter is still running after the grace period, its context is cancelled and the shooter is abandoned.
+// A non-positive grace period waits indefinitely.
+func (s *Shooter) Stop(grace time.Duration) ShutdownOutcome {
+	if s.Status() == Created {
+		s.ScheduleShutDown()
+		_ = s.transition(Stopped)
+		s.setShutdownOutcome(Graceful)
+		s.markDone()
+		return s.ShutdownOutcome()
+	}
+
+	s.ScheduleShutDown()
+
+	var deadline <-chan time.Time
+	if grace > 0 {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case <-s.Finished():
+	case <-deadline:
+		s.Logger().Warn().Msgf("Shooter did not stop within %s, forcing shutdown", grace)
+		s.setShutdownOutcome(Forced)
+		s.Context.Cancel()
+		_ = s.transition(Stopped)
+		s.release()
+	}
+
+	return s.ShutdownOutcome()
+}
+
+// Finished is closed once the shooter has stopped, while Done still belongs to the embedded context
+func (s *Shooter) Finished() <-chan struct{} {
+	s.shutdown.initOnce.Do(func() {
+		s.shutdown.done = make(chan struct{})
+	})
+
+	return s.shutdown.done
+}
+
+// Release marks as finished a shooter driven step by step through SetUp, Iterate and TearDown
+func (s *Shooter) Release() {
+	s.finish()
+}
+
+func (s *Shooter) ShutdownOutcome() ShutdownOutcome {
+	s.shutdown.mutex.Lock()
+	defer s.shutdown.mutex.Unlock()
+
+	return s.shutdown.outcome
+}
+
+func (s *Shooter) setShutdownOutcome(outcome ShutdownOutcome) {
+	s.shutdown.mutex.Lock()
+	defer s.shutdown.mutex.Unlock()
+
+	// The first outcome wins, a forced shutdown cannot be turned into a graceful one afterwards
+	if s.shutdown.outcome != "" {
+		return
+	}
+	s.shutdown.outcome = outcome
+
+	now := time.Now()
+	s.SampleCollector().Collect(telemetry.NewEventSample(ShutdownEvent, now, now, map[string]string{
+		"outcome": string(outcome),
+	}))
+}
+
+func (s *Shooter) finish() {
+	if s.isScheduledForShutdown() {
+		s.setShutdownOutcome(Graceful)
+	}
+
+	s.markDone()
+	s.release()
+}
+
+func (s *Shooter) markDone() {
+	// The channel is lazily created, so it must exist before being closed
+	s.Finished()
+	s.shutdown.doneOnce.Do(func() {
+		close(s.shutdown.done)
+	})
+}
+
+func (s *Shooter) release() {
+	s.shutdown.releaseOnce.Do(func() {
+		if s.WaitGroup != nil {
+			s.WaitGroup.Done()
+		}
+	})
+}
+
+func (s *Shooter) runTearDownScript() error {
+	// Teardown runs on a detached context, so that it can still do its job after a forced shutdown
+	tearDownContext := s.Context
+	if s.TearDownTimeout <= 0 {
+		tearDownContext.Context, tearDownContext.cancelFunc = context.WithCancel(context.Background())
+		defer tearDownContext.Cancel()
+
+		return s.runScriptIn(tearDownContext, s.TearDownScript)
+	}
+
+	tearDownContext.Context, tearDownContext.cancelFunc = context.WithTimeout(context.Background(), s.TearDownTimeout)
+	defer tearDownContext.Cancel()
+
+	// The script runs on its own goroutine, so that a hanging teardown cannot block the shooter forever
+	result := make(chan error, 1)
+	go func() {
+		result <- s.runScriptIn(tearDownContext, s.TearDownScript)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-tearDownContext.Done():
+		s.setShutdownOutcome(Forced)
+		return ErrTearDownTimeout{Timeout: s.TearDownTimeout}
+	}
+}
@@ -3,9 +3,36 @@ package shooter
 type Status string
 
 const (
+	Created      Status = "CREATED"
+	SettingUp    Status = "SETTING_UP"
 	Running      Status = "RUNNING"
+	Paused       Status = "PAUSED"
 	ShuttingDown Status = "SHUTTING_DOWN"
+	TearingDown  Status = "TEARING_DOWN"
 	Completed    Status = "COMPLETED"
 	Stopped      Status = "STOPPED"
 	Error        Status = "ERROR"
 )
+
+var allowedTransitions = map[Status][]Status{
+	Created:      {SettingUp, ShuttingDown, Stopped, Error},
+	SettingUp:    {Running, ShuttingDown, TearingDown, Error},
+	Running:      {Paused, ShuttingDown, TearingDown, Error},
+	Paused:       {Running, ShuttingDown, TearingDown, Error},
+	ShuttingDown: {TearingDown, Stopped, Error},
+	TearingDown:  {Completed, Stopped, Error},
+}
+
+func (s Status) CanTransitionTo(target Status) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Status) IsFinal() bool {
+	return s == Completed || s == Stopped || s == Error
+}
@@ -0,0 +1,10 @@
+package shooter
+
+import "time"
+
+type StatusChange struct {
+	ShooterID string
+	From      Status
+	To        Status
+	At        time.Time
+}
@@ -0,0 +1,57 @@
+package shooter
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"strconv"
+	"sync"
+)
+
+type contextTags struct {
+	values map[string]string
+	mutex  sync.RWMutex
+}
+
+func newContextTags() *contextTags {
+	output := new(contextTags)
+	output.values = make(map[string]string)
+
+	return output
+}
+
+// SetTag adds a tag to all the samples collected from now on, e.g. the scenario the shooter belongs to
+func (c *Context) SetTag(key string, value string) {
+	c.tags.mutex.Lock()
+	defer c.tags.mutex.Unlock()
+
+	c.tags.values[key] = value
+}
+
+// Tags returns the tags set on the context, along with the shooter, the iteration and the innermost open transaction
+func (c *Context) Tags() map[string]string {
+	c.tags.mutex.RLock()
+	output := make(map[string]string, len(c.tags.values)+3)
+	for key, value := range c.tags.values {
+		output[key] = value
+	}
+	c.tags.mutex.RUnlock()
+
+	output[telemetry.ShooterTag] = c.id
+	if iteration := c.Iteration(); iteration > 0 {
+		output[telemetry.IterationTag] = strconv.FormatInt(iteration, 10)
+	}
+	if transaction := c.transactions.innermost(); transaction != nil {
+		output[telemetry.TransactionTag] = transaction.name
+	}
+
+	return output
+}
+
+// TagSample applies the context tags to the sample, without overwriting the tags already set on it
+func (c *Context) TagSample(sample *telemetry.BaseSample) {
+	existing := sample.Tags()
+	for key, value := range c.Tags() {
+		if _, isSet := existing[key]; !isSet {
+			sample.SetTag(key, value)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package shooter
+
+import (
+	"math/rand"
+	"time"
+)
+
+func (c *Context) Think(duration time.Duration) error {
+	return c.think(duration)
+}
+
+func (c *Context) ThinkUniform(min time.Duration, max time.Duration) error {
+	if max <= min {
+		return c.think(min)
+	}
+
+	return c.think(min + time.Duration(rand.Int63n(int64(max-min))))
+}
+
+func (c *Context) ThinkGaussian(mean time.Duration, standardDeviation time.Duration) error {
+	return c.think(mean + time.Duration(rand.NormFloat64()*float64(standardDeviation)))
+}
+
+func (c *Context) ThinkPoisson(mean time.Duration) error {
+	// Delays between events of a Poisson process are exponentially distributed
+	return c.think(time.Duration(rand.ExpFloat64() * float64(mean)))
+}
+
+func (c *Context) think(duration time.Duration) error {
+	start := time.Now()
+	err := c.sleep(duration)
+	c.transactions.addThinkTime(time.Since(start))
+
+	return err
+}
+
+func (c *Context) sleep(duration time.Duration) error {
+	if duration <= 0 {
+		return c.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-c.Done():
+		return c.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package shooter_test
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+	"time"
+)
+
+type ThinkTimeTestSuite struct {
+	suite.Suite
+	context shooter.Context
+}
+
+func (suite *ThinkTimeTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	suite.context = shooter.NewContext(context.Background(), logger, "1")
+}
+
+func (suite *ThinkTimeTestSuite) TestThink() {
+	start := time.Now()
+	err := suite.context.Think(20 * time.Millisecond)
+
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), time.Since(start), 20*time.Millisecond)
+}
+
+func (suite *ThinkTimeTestSuite) TestThinkNegative() {
+	start := time.Now()
+	err := suite.context.Think(-1 * time.Second)
+
+	assert.NoError(suite.T(), err)
+	assert.Less(suite.T(), time.Since(start), 10*time.Millisecond)
+}
+
+func (suite *ThinkTimeTestSuite) TestThinkUniform() {
+	start := time.Now()
+	err := suite.context.ThinkUniform(10*time.Millisecond, 30*time.Millisecond)
+	elapsed := time.Since(start)
+
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), elapsed, 10*time.Millisecond)
+	assert.Less(suite.T(), elapsed, 100*time.Millisecond)
+}
+
+func (suite *ThinkTimeTestSuite) TestThinkGaussian() {
+	start := time.Now()
+	err := suite.context.ThinkGaussian(20*time.Millisecond, time.Millisecond)
+
+	assert.NoError(suite.T(), err)
+	assert.Less(suite.T(), time.Since(start), 100*time.Millisecond)
+}
+
+func (suite *ThinkTimeTestSuite) TestThinkPoisson() {
+	err := suite.context.ThinkPoisson(5 * time.Millisecond)
+
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *ThinkTimeTestSuite) TestThinkInterrupted() {
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		suite.context.Cancel()
+	}()
+
+	start := time.Now()
+	err := suite.context.Think(time.Hour)
+
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	assert.Less(suite.T(), time.Since(start), time.Second)
+}
+
+func TestThinkTimeTestSuite(t *testing.T) {
+	suite.Run(t, new(ThinkTimeTestSuite))
+}
@@ -0,0 +1,174 @@
+package shooter
+
+import (
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/telemetry"
+	"sync"
+	"time"
+)
+
+type TransactionOption int
+
+const (
+	ExcludeThinkTime TransactionOption = iota
+)
+
+type Transaction struct {
+	name             string
+	start            time.Time
+	excludeThinkTime bool
+	children         []telemetry.Sample
+	thinkTime        time.Duration
+	checks           []telemetry.CheckResult
+	tags             map[string]string
+	ended            bool
+
+	tracker         *transactionTracker
+	sampleCollector *telemetry.SampleCollector
+	logger          *zerolog.Logger
+}
+
+type transactionTracker struct {
+	open  []*Transaction
+	mutex sync.Mutex
+}
+
+func (c *Context) BeginTransaction(name string, options ...TransactionOption) *Transaction {
+	transaction := new(Transaction)
+	transaction.name = name
+	transaction.start = time.Now()
+	transaction.tracker = c.transactions
+	transaction.sampleCollector = c.sampleCollector
+	transaction.logger = c.logger
+	// Tags are taken before opening the transaction, so that it is tagged with the enclosing one
+	transaction.tags = c.Tags()
+
+	for _, option := range options {
+		if option == ExcludeThinkTime {
+			transaction.excludeThinkTime = true
+		}
+	}
+
+	c.transactions.mutex.Lock()
+	c.transactions.open = append(c.transactions.open, transaction)
+	c.transactions.mutex.Unlock()
+
+	c.logger.Info().Msgf("Started transaction '%s'", name)
+	return transaction
+}
+
+func (c *Context) Transaction(name string, body func() error, options ...TransactionOption) error {
+	transaction := c.BeginTransaction(name, options...)
+
+	// Scripts may abort through OnUnrecoverableError, so the transaction is closed before propagating the panic
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			recoveredErr, isError := recovered.(error)
+			if !isError {
+				recoveredErr = fmt.Errorf("%v", recovered)
+			}
+
+			transaction.End(recoveredErr)
+			panic(recovered)
+		}
+	}()
+
+	err := body()
+	transaction.End(err)
+	return err
+}
+
+func (t *Transaction) Name() string {
+	return t.name
+}
+
+func (t *Transaction) End(err error) telemetry.TransactionSample {
+	t.tracker.mutex.Lock()
+	if t.ended {
+		t.tracker.mutex.Unlock()
+		t.logger.Warn().Msgf("Transaction '%s' has already been ended", t.name)
+		return telemetry.TransactionSample{}
+	}
+
+	t.ended = true
+	t.tracker.remove(t)
+	sample := telemetry.NewTransactionSample(t.name, t.start, time.Now(), t.children)
+	sample.ThinkTime = t.thinkTime
+	sample.ThinkTimeExcluded = t.excludeThinkTime
+	sample.Success = err == nil
+	sample.Err = err
+	if err != nil {
+		sample.Fail(telemetry.ClassifyError(err), err.Error())
+	}
+	for _, check := range t.checks {
+		sample.AddCheck(check)
+	}
+	for key, value := range t.tags {
+		sample.SetTag(key, value)
+	}
+	t.tracker.mutex.Unlock()
+
+	if err != nil {
+		t.logger.Warn().Err(err).Msgf("Transaction '%s' failed", t.name)
+	} else {
+		t.logger.Info().Msgf("Transaction '%s' completed", t.name)
+	}
+
+	// Collecting the sample also registers it as a child of the enclosing transaction, if any
+	t.sampleCollector.Collect(sample)
+	return sample
+}
+
+func newTransactionTracker() *transactionTracker {
+	return new(transactionTracker)
+}
+
+func (tracker *transactionTracker) remove(transaction *Transaction) {
+	for index, openTransaction := range tracker.open {
+		if openTransaction == transaction {
+			tracker.open = append(tracker.open[:index], tracker.open[index+1:]...)
+			return
+		}
+	}
+}
+
+func (tracker *transactionTracker) innermost() *Transaction {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if len(tracker.open) == 0 {
+		return nil
+	}
+
+	return tracker.open[len(tracker.open)-1]
+}
+
+func (tracker *transactionTracker) addChild(sample telemetry.Sample) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if len(tracker.open) > 0 {
+		innermost := tracker.open[len(tracker.open)-1]
+		innermost.children = append(innermost.children, sample)
+	}
+}
+
+func (tracker *transactionTracker) addCheck(result telemetry.CheckResult) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if len(tracker.open) > 0 {
+		innermost := tracker.open[len(tracker.open)-1]
+		innermost.checks = append(innermost.checks, result)
+	}
+}
+
+func (tracker *transactionTracker) addThinkTime(thinkTime time.Duration) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	for _, openTransaction := range tracker.open {
+		openTransaction.thinkTime += thinkTime
+	}
+}
@@ -0,0 +1,157 @@
+package shooter_test
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/steromano87/harkonnen/shooter"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+	"time"
+)
+
+type TransactionTestSuite struct {
+	suite.Suite
+	context shooter.Context
+}
+
+func (suite *TransactionTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	suite.context = shooter.NewContext(context.Background(), logger, "1")
+}
+
+func (suite *TransactionTestSuite) collectTransactions() []telemetry.TransactionSample {
+	var transactions []telemetry.TransactionSample
+	for _, sample := range suite.context.SampleCollector().Flush() {
+		if transaction, isTransaction := sample.(telemetry.TransactionSample); isTransaction {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return transactions
+}
+
+func (suite *TransactionTestSuite) TestBeginEnd() {
+	transaction := suite.context.BeginTransaction("Login")
+	childSample := telemetry.NewBaseSample("request", time.Now(), time.Now(), 10, 100)
+	suite.context.SampleCollector().Collect(childSample)
+	sample := transaction.End(nil)
+
+	assert.Equal(suite.T(), "Login", sample.Name())
+	assert.True(suite.T(), sample.Success)
+	assert.NoError(suite.T(), sample.Err)
+	assert.Equal(suite.T(), []telemetry.Sample{childSample}, sample.Children)
+	assert.EqualValues(suite.T(), 10, sample.SentBytes())
+	assert.EqualValues(suite.T(), 100, sample.ReceivedBytes())
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 1) {
+		assert.Equal(suite.T(), "Login", transactions[0].Name())
+	}
+}
+
+func (suite *TransactionTestSuite) TestCustomSampleCollector() {
+	collector := telemetry.NewSampleCollector(10, telemetry.DropAndCount)
+	suite.context.UseSampleCollector(collector)
+
+	transaction := suite.context.BeginTransaction("Login")
+	childSample := telemetry.NewBaseSample("request", time.Now(), time.Now(), 10, 100)
+	suite.context.SampleCollector().Collect(childSample)
+	sample := transaction.End(nil)
+
+	assert.Same(suite.T(), collector, suite.context.SampleCollector())
+	assert.Equal(suite.T(), []telemetry.Sample{childSample}, sample.Children)
+	assert.Len(suite.T(), suite.collectTransactions(), 1)
+}
+
+func (suite *TransactionTestSuite) TestEndTwice() {
+	transaction := suite.context.BeginTransaction("Login")
+	transaction.End(nil)
+	transaction.End(errors.New("late error"))
+
+	assert.Len(suite.T(), suite.collectTransactions(), 1)
+}
+
+func (suite *TransactionTestSuite) TestTransactionWrapper() {
+	err := suite.context.Transaction("Checkout", func() error {
+		return errors.New("payment refused")
+	})
+
+	assert.EqualError(suite.T(), err, "payment refused")
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 1) {
+		assert.False(suite.T(), transactions[0].Success)
+		assert.EqualError(suite.T(), transactions[0].Err, "payment refused")
+	}
+}
+
+func (suite *TransactionTestSuite) TestTransactionWrapperWithPanic() {
+	assert.Panics(suite.T(), func() {
+		_ = suite.context.Transaction("Checkout", func() error {
+			suite.context.OnUnrecoverableError(errors.New("unrecoverable"))
+			return nil
+		})
+	})
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 1) {
+		assert.False(suite.T(), transactions[0].Success)
+		assert.EqualError(suite.T(), transactions[0].Err, "unrecoverable")
+	}
+}
+
+func (suite *TransactionTestSuite) TestNestedTransactions() {
+	err := suite.context.Transaction("Purchase", func() error {
+		return suite.context.Transaction("Payment", func() error {
+			return nil
+		})
+	})
+
+	assert.NoError(suite.T(), err)
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 2) {
+		assert.Equal(suite.T(), "Payment", transactions[0].Name())
+		assert.Equal(suite.T(), "Purchase", transactions[1].Name())
+		if assert.Len(suite.T(), transactions[1].Children, 1) {
+			assert.Equal(suite.T(), "Payment", transactions[1].Children[0].Name())
+		}
+	}
+}
+
+func (suite *TransactionTestSuite) TestExcludeThinkTime() {
+	err := suite.context.Transaction("Browse", func() error {
+		return suite.context.Think(50 * time.Millisecond)
+	}, shooter.ExcludeThinkTime)
+
+	assert.NoError(suite.T(), err)
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 1) {
+		assert.True(suite.T(), transactions[0].ThinkTimeExcluded)
+		assert.GreaterOrEqual(suite.T(), transactions[0].ThinkTime, 50*time.Millisecond)
+		assert.Less(suite.T(), transactions[0].Duration(), 50*time.Millisecond)
+	}
+}
+
+func (suite *TransactionTestSuite) TestIncludeThinkTime() {
+	err := suite.context.Transaction("Browse", func() error {
+		return suite.context.Think(50 * time.Millisecond)
+	})
+
+	assert.NoError(suite.T(), err)
+
+	transactions := suite.collectTransactions()
+	if assert.Len(suite.T(), transactions, 1) {
+		assert.False(suite.T(), transactions[0].ThinkTimeExcluded)
+		assert.GreaterOrEqual(suite.T(), transactions[0].Duration(), 50*time.Millisecond)
+	}
+}
+
+func TestTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionTestSuite))
+}
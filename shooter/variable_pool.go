@@ -3,11 +3,14 @@ package shooter
 import (
 	"fmt"
 	"github.com/rs/zerolog"
+	"reflect"
+	"sync"
 )
 
 type VariablePool struct {
 	variables map[string]interface{}
 	logger    *zerolog.Logger
+	mutex     sync.RWMutex
 }
 
 func NewVariablePool(parentLogger *zerolog.Logger) *VariablePool {
@@ -21,10 +24,66 @@ func NewVariablePool(parentLogger *zerolog.Logger) *VariablePool {
 }
 
 func (pool *VariablePool) Set(name string, value interface{}) {
+	pool.mutex.Lock()
 	pool.variables[name] = value
+	pool.mutex.Unlock()
+
 	pool.logger.Info().Msgf("Set variable '%s' with value '%s'", name, value)
 }
 
+func (pool *VariablePool) SetIfAbsent(name string, value interface{}) bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if _, isPresent := pool.variables[name]; isPresent {
+		return false
+	}
+
+	pool.variables[name] = value
+	pool.logger.Info().Msgf("Set variable '%s' with value '%s'", name, value)
+	return true
+}
+
+func (pool *VariablePool) CompareAndSet(name string, expected interface{}, value interface{}) bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	current, isPresent := pool.variables[name]
+	if !isPresent || !reflect.DeepEqual(current, expected) {
+		return false
+	}
+
+	pool.variables[name] = value
+	pool.logger.Info().Msgf("Swapped variable '%s' from value '%v' to value '%v'", name, expected, value)
+	return true
+}
+
+func (pool *VariablePool) Increment(name string, delta int) (int, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	// Missing variables are treated as counters starting from zero
+	current := 0
+	if value, isPresent := pool.variables[name]; isPresent {
+		convertedValue, isOk := value.(int)
+		if !isOk {
+			err := ErrBadVariableCast{
+				Name:     name,
+				CastType: "int",
+				RawValue: value,
+			}
+			pool.logger.Warn().Err(err).Msgf("Caught an error while incrementing variable '%s'", name)
+			return 0, err
+		}
+		current = convertedValue
+	}
+
+	current += delta
+	pool.variables[name] = current
+	pool.logger.Info().Msgf("Incremented variable '%s' by %d to value '%d'", name, delta, current)
+	return current, nil
+}
+
 func (pool *VariablePool) GetString(name string) (string, error) {
 	value, err := pool.Get(name)
 	return fmt.Sprintf("%v", value), err
@@ -72,7 +131,10 @@ func (pool *VariablePool) GetBool(name string) (bool, error) {
 
 func (pool *VariablePool) Get(name string) (interface{}, error) {
 	pool.logger.Info().Msgf("Requested variable '%s'", name)
+
+	pool.mutex.RLock()
 	value, isPresent := pool.variables[name]
+	pool.mutex.RUnlock()
 
 	if !isPresent {
 		err := ErrVariableNotFound{Name: name}
@@ -84,6 +146,9 @@ func (pool *VariablePool) Get(name string) (interface{}, error) {
 }
 
 func (pool *VariablePool) Delete(name string) {
+	pool.mutex.Lock()
 	delete(pool.variables, name)
+	pool.mutex.Unlock()
+
 	pool.logger.Info().Msgf("Deleted variable '%s'", name)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"os"
+	"sync"
 	"testing"
 )
 
@@ -126,6 +127,76 @@ func (suite *VariablePoolTestSuite) TestDelete() {
 	assert.IsType(suite.T(), shooter.ErrVariableNotFound{}, err)
 }
 
+func (suite *VariablePoolTestSuite) TestSetIfAbsent() {
+	vp := shooter.NewVariablePool(&suite.logger)
+
+	assert.True(suite.T(), vp.SetIfAbsent("test", "firstValue"))
+	assert.False(suite.T(), vp.SetIfAbsent("test", "secondValue"))
+
+	actualValue, err := vp.Get("test")
+	assert.Equal(suite.T(), "firstValue", actualValue)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *VariablePoolTestSuite) TestCompareAndSet() {
+	vp := shooter.NewVariablePool(&suite.logger)
+	vp.Set("test", "oldValue")
+
+	assert.False(suite.T(), vp.CompareAndSet("test", "wrongValue", "newValue"))
+	assert.True(suite.T(), vp.CompareAndSet("test", "oldValue", "newValue"))
+
+	actualValue, err := vp.Get("test")
+	assert.Equal(suite.T(), "newValue", actualValue)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *VariablePoolTestSuite) TestCompareAndSetNonExisting() {
+	vp := shooter.NewVariablePool(&suite.logger)
+
+	assert.False(suite.T(), vp.CompareAndSet("nonExisting", nil, "newValue"))
+}
+
+func (suite *VariablePoolTestSuite) TestIncrement() {
+	vp := shooter.NewVariablePool(&suite.logger)
+
+	actualValue, err := vp.Increment("counter", 1)
+	assert.Equal(suite.T(), 1, actualValue)
+	assert.NoError(suite.T(), err)
+
+	actualValue, err = vp.Increment("counter", 5)
+	assert.Equal(suite.T(), 6, actualValue)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *VariablePoolTestSuite) TestIncrementBadType() {
+	vp := shooter.NewVariablePool(&suite.logger)
+	vp.Set("counter", "a")
+	actualValue, err := vp.Increment("counter", 1)
+
+	assert.Zero(suite.T(), actualValue, "Expected zero to be returned")
+	assert.IsType(suite.T(), shooter.ErrBadVariableCast{}, err)
+}
+
+func (suite *VariablePoolTestSuite) TestConcurrentAccess() {
+	vp := shooter.NewVariablePool(&suite.logger)
+	wg := sync.WaitGroup{}
+
+	for index := 0; index < 50; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vp.Set("test", "value")
+			_, _ = vp.Get("test")
+			_, _ = vp.Increment("counter", 1)
+		}()
+	}
+
+	wg.Wait()
+	actualValue, err := vp.GetInt("counter")
+	assert.Equal(suite.T(), 50, actualValue)
+	assert.NoError(suite.T(), err)
+}
+
 func TestVariablePoolTestSuite(t *testing.T) {
 	suite.Run(t, new(VariablePoolTestSuite))
 }
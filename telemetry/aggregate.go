@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// AggregationKey identifies the samples aggregated together, tags are in their canonical form (k1=v1,k2=v2)
+type AggregationKey struct {
+	Name string `json:"name"`
+	Tags string `json:"tags,omitempty"`
+}
+
+func KeyOf(sample Sample) AggregationKey {
+	return AggregationKey{Name: sample.Name(), Tags: canonicalTags(sample.Tags())}
+}
+
+func canonicalTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if !unaggregatedTags[key] {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+type Aggregate struct {
+	Count         int64      `json:"count"`
+	Errors        int64      `json:"errors"`
+	SentBytes     int64      `json:"sent_bytes"`
+	ReceivedBytes int64      `json:"received_bytes"`
+	Latency       *Histogram `json:"latency"`
+}
+
+type AggregateSummary struct {
+	Count         int64
+	Errors        int64
+	ErrorRate     float64
+	SentBytes     int64
+	ReceivedBytes int64
+	Min           time.Duration
+	Max           time.Duration
+	Mean          time.Duration
+	P50           time.Duration
+	P90           time.Duration
+	P95           time.Duration
+	P99           time.Duration
+	P999          time.Duration
+}
+
+func NewAggregate() *Aggregate {
+	output := new(Aggregate)
+	output.Latency = NewHistogram()
+
+	return output
+}
+
+func (a *Aggregate) Add(sample Sample, failed bool) {
+	a.Count++
+	if failed {
+		a.Errors++
+	}
+	a.SentBytes += sample.SentBytes()
+	a.ReceivedBytes += sample.ReceivedBytes()
+	a.Latency.Record(sample.Duration())
+}
+
+func (a *Aggregate) Merge(other *Aggregate) {
+	a.Count += other.Count
+	a.Errors += other.Errors
+	a.SentBytes += other.SentBytes
+	a.ReceivedBytes += other.ReceivedBytes
+	a.Latency.Merge(other.Latency)
+}
+
+func (a *Aggregate) Summarize() AggregateSummary {
+	output := AggregateSummary{
+		Count:         a.Count,
+		Errors:        a.Errors,
+		SentBytes:     a.SentBytes,
+		ReceivedBytes: a.ReceivedBytes,
+		Min:           a.Latency.Min,
+		Max:           a.Latency.Max,
+		Mean:          a.Latency.Mean(),
+		P50:           a.Latency.Percentile(50),
+		P90:           a.Latency.Percentile(90),
+		P95:           a.Latency.Percentile(95),
+		P99:           a.Latency.Percentile(99),
+		P999:          a.Latency.Percentile(99.9),
+	}
+
+	if a.Count > 0 {
+		output.ErrorRate = float64(a.Errors) / float64(a.Count)
+	}
+
+	return output
+}
@@ -0,0 +1,193 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultBucketSize = 10 * time.Second
+
+// AggregateEntry is the serializable form of an aggregate in a time bucket, used to merge aggregators across processes
+type AggregateEntry struct {
+	Bucket    time.Time      `json:"bucket"`
+	Key       AggregationKey `json:"key"`
+	Aggregate *Aggregate     `json:"aggregate"`
+}
+
+// Aggregator groups samples by key and by the time bucket they ended in
+type Aggregator struct {
+	bucketSize time.Duration
+	kinds      map[RecordKind]bool
+	buckets    map[time.Time]map[AggregationKey]*Aggregate
+	mutex      sync.Mutex
+}
+
+// NewAggregator creates an aggregator over the given kinds of samples, plain samples and transactions by default
+func NewAggregator(bucketSize time.Duration, kinds ...RecordKind) *Aggregator {
+	output := new(Aggregator)
+	output.bucketSize = bucketSize
+	output.kinds = make(map[RecordKind]bool)
+	output.buckets = make(map[time.Time]map[AggregationKey]*Aggregate)
+
+	if output.bucketSize <= 0 {
+		output.bucketSize = DefaultBucketSize
+	}
+
+	if len(kinds) == 0 {
+		kinds = []RecordKind{GenericRecord, TransactionRecord}
+	}
+	for _, kind := range kinds {
+		output.kinds[kind] = true
+	}
+
+	return output
+}
+
+func (a *Aggregator) Add(samples ...Sample) {
+	records := make([]Record, 0, len(samples))
+	for _, sample := range samples {
+		records = append(records, NewRecord("", sample))
+	}
+
+	a.AddRecords(records...)
+}
+
+// Write makes the aggregator a sink, so that it can be fed by a sample collector
+func (a *Aggregator) Write(samples []Sample) error {
+	a.Add(samples...)
+	return nil
+}
+
+func (a *Aggregator) AddRecords(records ...Record) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, record := range records {
+		if !a.kinds[record.Kind] {
+			continue
+		}
+
+		sample := record.Sample()
+		a.aggregate(a.bucketOf(record.End), KeyOf(sample)).Add(sample, record.Failed())
+	}
+}
+
+func (a *Aggregator) BucketSize() time.Duration {
+	return a.bucketSize
+}
+
+// Buckets returns the start of the time buckets with at least an aggregated sample, in chronological order
+func (a *Aggregator) Buckets() []time.Time {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	output := make([]time.Time, 0, len(a.buckets))
+	for bucket := range a.buckets {
+		output = append(output, bucket)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Before(output[j])
+	})
+
+	return output
+}
+
+func (a *Aggregator) Bucket(start time.Time) map[AggregationKey]AggregateSummary {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	output := make(map[AggregationKey]AggregateSummary)
+	for key, aggregate := range a.buckets[a.bucketOf(start)] {
+		output[key] = aggregate.Summarize()
+	}
+
+	return output
+}
+
+// Overall merges the time buckets, giving the statistics of the whole test
+func (a *Aggregator) Overall() map[AggregationKey]AggregateSummary {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	merged := make(map[AggregationKey]*Aggregate)
+	for _, aggregates := range a.buckets {
+		for key, aggregate := range aggregates {
+			if _, isPresent := merged[key]; !isPresent {
+				merged[key] = NewAggregate()
+			}
+			merged[key].Merge(aggregate)
+		}
+	}
+
+	output := make(map[AggregationKey]AggregateSummary, len(merged))
+	for key, aggregate := range merged {
+		output[key] = aggregate.Summarize()
+	}
+
+	return output
+}
+
+// Export returns a copy of the aggregated state, ordered by bucket and key
+func (a *Aggregator) Export() []AggregateEntry {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var output []AggregateEntry
+	for bucket, aggregates := range a.buckets {
+		for key, aggregate := range aggregates {
+			copied := NewAggregate()
+			copied.Merge(aggregate)
+			output = append(output, AggregateEntry{Bucket: bucket, Key: key, Aggregate: copied})
+		}
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		if !output[i].Bucket.Equal(output[j].Bucket) {
+			return output[i].Bucket.Before(output[j].Bucket)
+		}
+		if output[i].Key.Name != output[j].Key.Name {
+			return output[i].Key.Name < output[j].Key.Name
+		}
+		return output[i].Key.Tags < output[j].Key.Tags
+	})
+
+	return output
+}
+
+// Import merges exported entries, e.g. the aggregates of an injector into the ones of the cockpit
+func (a *Aggregator) Import(entries ...AggregateEntry) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, entry := range entries {
+		if entry.Aggregate != nil {
+			a.aggregate(a.bucketOf(entry.Bucket), entry.Key).Merge(entry.Aggregate)
+		}
+	}
+}
+
+func (a *Aggregator) Merge(other *Aggregator) {
+	a.Import(other.Export()...)
+}
+
+func (a *Aggregator) aggregate(bucket time.Time, key AggregationKey) *Aggregate {
+	aggregates, isPresent := a.buckets[bucket]
+	if !isPresent {
+		aggregates = make(map[AggregationKey]*Aggregate)
+		a.buckets[bucket] = aggregates
+	}
+
+	aggregate, isPresent := aggregates[key]
+	if !isPresent {
+		aggregate = NewAggregate()
+		aggregates[key] = aggregate
+	}
+
+	return aggregate
+}
+
+// Buckets are kept in UTC, so that the same instant coming from different processes falls in the same bucket
+func (a *Aggregator) bucketOf(instant time.Time) time.Time {
+	return instant.Truncate(a.bucketSize).UTC()
+}
@@ -0,0 +1,135 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func aggregatedSample(name string, end time.Time, elapsed time.Duration) telemetry.BaseSample {
+	return telemetry.NewBaseSample(name, end.Add(-elapsed), end, 100, 1000)
+}
+
+func TestAggregator_Buckets(t *testing.T) {
+	aggregator := telemetry.NewAggregator(10 * time.Second)
+	aggregator.Add(
+		aggregatedSample("GET /", recordStart.Add(time.Second), 100*time.Millisecond),
+		aggregatedSample("GET /", recordStart.Add(2*time.Second), 300*time.Millisecond),
+		aggregatedSample("GET /", recordStart.Add(12*time.Second), 200*time.Millisecond),
+		aggregatedSample("POST /login", recordStart.Add(15*time.Second), time.Second),
+		telemetry.NewMetricSample("queue_length", recordStart, 12),
+	)
+
+	assert.Equal(t, []time.Time{recordStart, recordStart.Add(10 * time.Second)}, aggregator.Buckets())
+
+	first := aggregator.Bucket(recordStart.Add(5 * time.Second))
+	if assert.Len(t, first, 1) {
+		summary := first[telemetry.AggregationKey{Name: "GET /"}]
+		assert.EqualValues(t, 2, summary.Count)
+		assert.EqualValues(t, 200, summary.SentBytes)
+		assert.EqualValues(t, 2000, summary.ReceivedBytes)
+		assert.Equal(t, 100*time.Millisecond, summary.Min)
+		assert.Equal(t, 300*time.Millisecond, summary.Max)
+		assert.Equal(t, 200*time.Millisecond, summary.Mean)
+	}
+
+	assert.Len(t, aggregator.Bucket(recordStart.Add(10*time.Second)), 2)
+	assert.Empty(t, aggregator.Bucket(recordStart.Add(time.Hour)))
+}
+
+func TestAggregator_Overall(t *testing.T) {
+	aggregator := telemetry.NewAggregator(time.Second)
+	for index := 1; index <= 100; index++ {
+		aggregator.Add(aggregatedSample("GET /", recordStart.Add(time.Duration(index)*time.Second), time.Duration(index)*time.Millisecond))
+	}
+
+	overall := aggregator.Overall()[telemetry.AggregationKey{Name: "GET /"}]
+	assert.EqualValues(t, 100, overall.Count)
+	assert.Equal(t, time.Millisecond, overall.Min)
+	assert.Equal(t, 100*time.Millisecond, overall.Max)
+	assertWithinPrecision(t, 50*time.Millisecond, overall.P50)
+	assertWithinPrecision(t, 90*time.Millisecond, overall.P90)
+	assertWithinPrecision(t, 95*time.Millisecond, overall.P95)
+	assertWithinPrecision(t, 99*time.Millisecond, overall.P99)
+	assert.Equal(t, 100*time.Millisecond, overall.P999)
+}
+
+func TestAggregator_Errors(t *testing.T) {
+	aggregator := telemetry.NewAggregator(time.Minute)
+
+	failed := telemetry.NewTransactionSample("checkout", recordStart, recordStart.Add(time.Second), nil)
+	failed.Err = errors.New("payment refused")
+	succeeded := telemetry.NewTransactionSample("checkout", recordStart, recordStart.Add(time.Second), nil)
+	succeeded.Success = true
+	checked := aggregatedSample("GET /", recordStart.Add(time.Second), time.Second)
+	checked.AddCheck(telemetry.CheckResult{Name: "status is 200", Passed: false})
+
+	aggregator.Add(failed, succeeded, checked)
+
+	overall := aggregator.Overall()
+	assert.EqualValues(t, 1, overall[telemetry.AggregationKey{Name: "checkout"}].Errors)
+	assert.Equal(t, 0.5, overall[telemetry.AggregationKey{Name: "checkout"}].ErrorRate)
+	assert.EqualValues(t, 1, overall[telemetry.AggregationKey{Name: "GET /"}].Errors)
+}
+
+func TestAggregator_MergeIsExact(t *testing.T) {
+	whole := telemetry.NewAggregator(5 * time.Second)
+	first := telemetry.NewAggregator(5 * time.Second)
+	second := telemetry.NewAggregator(5 * time.Second)
+
+	for index := 0; index < 500; index++ {
+		sample := aggregatedSample("GET /", recordStart.Add(time.Duration(index)*50*time.Millisecond), time.Duration(index*index)*time.Microsecond)
+		whole.Add(sample)
+		if index%2 == 0 {
+			first.Add(sample)
+		} else {
+			second.Add(sample)
+		}
+	}
+
+	// Injector aggregates travel as JSON before being merged by the cockpit
+	encoded, err := json.Marshal(second.Export())
+	if !assert.NoError(t, err) {
+		return
+	}
+	var entries []telemetry.AggregateEntry
+	if !assert.NoError(t, json.Unmarshal(encoded, &entries)) {
+		return
+	}
+
+	merged := telemetry.NewAggregator(5 * time.Second)
+	merged.Merge(first)
+	merged.Import(entries...)
+
+	assert.Equal(t, whole.Overall(), merged.Overall())
+	assert.Equal(t, whole.Buckets(), merged.Buckets())
+	for _, bucket := range whole.Buckets() {
+		assert.Equal(t, whole.Bucket(bucket), merged.Bucket(bucket))
+	}
+}
+
+func TestAggregator_Sink(t *testing.T) {
+	aggregator := telemetry.NewAggregator(0)
+	assert.Equal(t, telemetry.DefaultBucketSize, aggregator.BucketSize())
+
+	collector := new(telemetry.SampleCollector)
+	collector.AddSink(aggregator)
+	collector.Collect(aggregatedSample("GET /", recordStart, time.Millisecond))
+
+	assert.NoError(t, collector.Forward())
+	assert.EqualValues(t, 1, aggregator.Overall()[telemetry.AggregationKey{Name: "GET /"}].Count)
+}
+
+func TestKeyOf(t *testing.T) {
+	sample := aggregatedSample("GET /", recordStart, time.Millisecond)
+	sample.SetTag(telemetry.TransactionTag, "Login")
+	sample.SetTag(telemetry.ScenarioTag, "checkout")
+	sample.SetTag(telemetry.ShooterTag, "shooter-1")
+	sample.SetTag(telemetry.IterationTag, "12")
+
+	// Tags unique to a shooter or to an iteration would give a key for each sample
+	assert.Equal(t, telemetry.AggregationKey{Name: "GET /", Tags: "scenario=checkout,transaction=Login"}, telemetry.KeyOf(sample))
+}
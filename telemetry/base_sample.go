@@ -8,6 +8,11 @@ type BaseSample struct {
 	name          string
 	sentBytes     int64
 	receivedBytes int64
+	checks        []CheckResult
+	errorClass    ErrorClass
+	errorMessage  string
+	statusCode    int
+	tags          map[string]string
 }
 
 func NewBaseSample(name string, start time.Time, end time.Time, sentBytes int64, receivedBytes int64) BaseSample {
@@ -44,3 +49,64 @@ func (sample BaseSample) SentBytes() int64 {
 func (sample BaseSample) ReceivedBytes() int64 {
 	return sample.receivedBytes
 }
+
+func (sample BaseSample) Checks() []CheckResult {
+	return sample.checks
+}
+
+func (sample *BaseSample) AddCheck(result CheckResult) {
+	sample.checks = append(sample.checks, result)
+}
+
+// Succeeded tells whether the sampled operation completed without errors and with all its checks passed
+func (sample BaseSample) Succeeded() bool {
+	if sample.errorClass != "" {
+		return false
+	}
+
+	for _, check := range sample.checks {
+		if !check.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (sample BaseSample) ErrorClass() ErrorClass {
+	return sample.errorClass
+}
+
+func (sample BaseSample) ErrorMessage() string {
+	return sample.errorMessage
+}
+
+// StatusCode is the status of the protocol response (e.g. 404 for HTTP), zero when there is none
+func (sample BaseSample) StatusCode() int {
+	return sample.statusCode
+}
+
+func (sample BaseSample) Tags() map[string]string {
+	return sample.tags
+}
+
+func (sample *BaseSample) Fail(class ErrorClass, message string) {
+	if class == "" {
+		class = UnknownError
+	}
+
+	sample.errorClass = class
+	sample.errorMessage = message
+}
+
+func (sample *BaseSample) SetStatusCode(statusCode int) {
+	sample.statusCode = statusCode
+}
+
+func (sample *BaseSample) SetTag(key string, value string) {
+	if sample.tags == nil {
+		sample.tags = make(map[string]string)
+	}
+
+	sample.tags[key] = value
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+)
+
+type Batch struct {
+	Source   string   `json:"source"`
+	Sequence uint64   `json:"sequence"`
+	Records  []Record `json:"records"`
+}
+
+// Encode serializes the batch as gzip-compressed JSON
+func (b Batch) Encode() ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	writer := gzip.NewWriter(buffer)
+
+	if err := json.NewEncoder(writer).Encode(b); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func DecodeBatch(data []byte) (Batch, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return Batch{}, err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	var output Batch
+	err = json.Unmarshal(content, &output)
+	return output, err
+}
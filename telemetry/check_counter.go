@@ -0,0 +1,91 @@
+package telemetry
+
+import "sync"
+
+type CheckCount struct {
+	Passed int64
+	Failed int64
+}
+
+func (count CheckCount) Total() int64 {
+	return count.Passed + count.Failed
+}
+
+func (count CheckCount) PassRate() float64 {
+	if count.Total() == 0 {
+		return 1
+	}
+
+	return float64(count.Passed) / float64(count.Total())
+}
+
+type CheckCounter struct {
+	counts map[string]CheckCount
+	mutex  sync.Mutex
+}
+
+func NewCheckCounter() *CheckCounter {
+	counter := new(CheckCounter)
+	counter.counts = make(map[string]CheckCount)
+
+	return counter
+}
+
+func (counter *CheckCounter) Record(result CheckResult) {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	count := counter.counts[result.Name]
+	if result.Passed {
+		count.Passed++
+	} else {
+		count.Failed++
+	}
+	counter.counts[result.Name] = count
+}
+
+func (counter *CheckCounter) Count(name string) CheckCount {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	return counter.counts[name]
+}
+
+func (counter *CheckCounter) Total() CheckCount {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	total := CheckCount{}
+	for _, count := range counter.counts {
+		total.Passed += count.Passed
+		total.Failed += count.Failed
+	}
+
+	return total
+}
+
+func (counter *CheckCounter) Counts() map[string]CheckCount {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	output := make(map[string]CheckCount, len(counter.counts))
+	for name, count := range counter.counts {
+		output[name] = count
+	}
+
+	return output
+}
+
+func (counter *CheckCounter) Merge(other *CheckCounter) {
+	otherCounts := other.Counts()
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	for name, otherCount := range otherCounts {
+		count := counter.counts[name]
+		count.Passed += otherCount.Passed
+		count.Failed += otherCount.Failed
+		counter.counts[name] = count
+	}
+}
@@ -0,0 +1,68 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestCheckCounter_Record(t *testing.T) {
+	counter := telemetry.NewCheckCounter()
+	counter.Record(telemetry.CheckResult{Name: "logged in", Passed: true})
+	counter.Record(telemetry.CheckResult{Name: "logged in", Passed: false})
+	counter.Record(telemetry.CheckResult{Name: "cart not empty", Passed: true})
+
+	assert.Equal(t, telemetry.CheckCount{Passed: 1, Failed: 1}, counter.Count("logged in"))
+	assert.Equal(t, telemetry.CheckCount{Passed: 1}, counter.Count("cart not empty"))
+	assert.Equal(t, telemetry.CheckCount{Passed: 2, Failed: 1}, counter.Total())
+	assert.Len(t, counter.Counts(), 2)
+}
+
+func TestCheckCounter_ConcurrentRecord(t *testing.T) {
+	counter := telemetry.NewCheckCounter()
+	wg := sync.WaitGroup{}
+
+	for index := 0; index < 50; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counter.Record(telemetry.CheckResult{Name: "concurrent", Passed: true})
+		}()
+	}
+
+	wg.Wait()
+	assert.EqualValues(t, 50, counter.Count("concurrent").Passed)
+}
+
+func TestCheckCounter_Merge(t *testing.T) {
+	first := telemetry.NewCheckCounter()
+	first.Record(telemetry.CheckResult{Name: "logged in", Passed: true})
+
+	second := telemetry.NewCheckCounter()
+	second.Record(telemetry.CheckResult{Name: "logged in", Passed: false})
+	second.Record(telemetry.CheckResult{Name: "cart not empty", Passed: true})
+
+	first.Merge(second)
+	assert.Equal(t, telemetry.CheckCount{Passed: 1, Failed: 1}, first.Count("logged in"))
+	assert.Equal(t, telemetry.CheckCount{Passed: 1}, first.Count("cart not empty"))
+}
+
+func TestCheckCount_PassRate(t *testing.T) {
+	assert.Equal(t, float64(1), telemetry.CheckCount{}.PassRate())
+	assert.Equal(t, 0.75, telemetry.CheckCount{Passed: 3, Failed: 1}.PassRate())
+}
+
+func TestCheckThreshold_Evaluate(t *testing.T) {
+	counter := telemetry.NewCheckCounter()
+	counter.Record(telemetry.CheckResult{Name: "logged in", Passed: true})
+	counter.Record(telemetry.CheckResult{Name: "cart not empty", Passed: false})
+
+	assert.NoError(t, telemetry.CheckThreshold{Check: "logged in", MinPassRate: 1}.Evaluate(counter))
+	assert.IsType(
+		t,
+		telemetry.ErrThresholdViolated{},
+		telemetry.CheckThreshold{Check: "cart not empty", MinPassRate: 0.5}.Evaluate(counter))
+	assert.NoError(t, telemetry.CheckThreshold{MinPassRate: 0.5}.Evaluate(counter))
+	assert.Error(t, telemetry.CheckThreshold{MinPassRate: 0.9}.Evaluate(counter))
+}
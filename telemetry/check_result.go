@@ -0,0 +1,7 @@
+package telemetry
+
+type CheckResult struct {
+	Name    string
+	Passed  bool
+	Message string
+}
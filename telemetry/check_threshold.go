@@ -0,0 +1,26 @@
+package telemetry
+
+type CheckThreshold struct {
+	Check       string  `yaml:"check,omitempty"`
+	MinPassRate float64 `yaml:"min_pass_rate"`
+}
+
+func (threshold CheckThreshold) Evaluate(counter *CheckCounter) error {
+	// Thresholds without a check name apply to all the checks together
+	var count CheckCount
+	if threshold.Check == "" {
+		count = counter.Total()
+	} else {
+		count = counter.Count(threshold.Check)
+	}
+
+	if count.PassRate() < threshold.MinPassRate {
+		return ErrThresholdViolated{
+			Check:    threshold.Check,
+			Expected: threshold.MinPassRate,
+			Actual:   count.PassRate(),
+		}
+	}
+
+	return nil
+}
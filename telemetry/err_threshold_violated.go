@@ -0,0 +1,22 @@
+package telemetry
+
+import "fmt"
+
+type ErrThresholdViolated struct {
+	Check    string
+	Expected float64
+	Actual   float64
+}
+
+func (tv ErrThresholdViolated) Error() string {
+	checkName := tv.Check
+	if checkName == "" {
+		checkName = "all checks"
+	}
+
+	return fmt.Sprintf(
+		"threshold violated for '%s': expected pass rate of at least %.2f%%, got %.2f%%",
+		checkName,
+		tv.Expected*100,
+		tv.Actual*100)
+}
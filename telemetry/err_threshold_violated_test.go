@@ -0,0 +1,27 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrThresholdViolated_Error(t *testing.T) {
+	myError := telemetry.ErrThresholdViolated{Check: "status is 200", Expected: 0.99, Actual: 0.5}
+
+	assert.EqualError(
+		t,
+		myError,
+		"threshold violated for 'status is 200': expected pass rate of at least 99.00%, got 50.00%",
+		"Wrong error message format")
+}
+
+func TestErrThresholdViolated_ErrorAllChecks(t *testing.T) {
+	myError := telemetry.ErrThresholdViolated{Expected: 1, Actual: 0.75}
+
+	assert.EqualError(
+		t,
+		myError,
+		"threshold violated for 'all checks': expected pass rate of at least 100.00%, got 75.00%",
+		"Wrong error message format")
+}
@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+type ErrorClass string
+
+const (
+	TimeoutError    ErrorClass = "timeout"
+	ConnectionError ErrorClass = "connection"
+	ProtocolError   ErrorClass = "protocol"
+	AssertionError  ErrorClass = "assertion"
+	CancelledError  ErrorClass = "cancelled"
+	UnknownError    ErrorClass = "unknown"
+)
+
+// ClassifyError maps the errors of the network stack to a class, so that failures can be grouped in reports
+func ClassifyError(err error) ErrorClass {
+	var netErr net.Error
+	var opErr *net.OpError
+
+	switch {
+	case err == nil:
+		return ""
+
+	case errors.Is(err, context.Canceled):
+		return CancelledError
+
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return TimeoutError
+
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &opErr):
+		return ConnectionError
+
+	default:
+		return UnknownError
+	}
+}
@@ -0,0 +1,27 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	resetErr := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	assert.Equal(t, telemetry.ErrorClass(""), telemetry.ClassifyError(nil))
+	assert.Equal(t, telemetry.CancelledError, telemetry.ClassifyError(fmt.Errorf("request aborted: %w", context.Canceled)))
+	assert.Equal(t, telemetry.TimeoutError, telemetry.ClassifyError(context.DeadlineExceeded))
+	assert.Equal(t, telemetry.TimeoutError, telemetry.ClassifyError(&url.Error{Op: "Get", URL: "http://localhost", Err: os.ErrDeadlineExceeded}))
+	assert.Equal(t, telemetry.ConnectionError, telemetry.ClassifyError(&url.Error{Op: "Get", URL: "http://localhost", Err: resetErr}))
+	assert.Equal(t, telemetry.ConnectionError, telemetry.ClassifyError(io.ErrUnexpectedEOF))
+	assert.Equal(t, telemetry.UnknownError, telemetry.ClassifyError(errors.New("unexpected")))
+}
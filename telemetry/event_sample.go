@@ -0,0 +1,16 @@
+package telemetry
+
+import "time"
+
+type EventSample struct {
+	BaseSample
+	Attributes map[string]string
+}
+
+func NewEventSample(name string, start time.Time, end time.Time, attributes map[string]string) EventSample {
+	sample := new(EventSample)
+	sample.BaseSample = NewBaseSample(name, start, end, 0, 0)
+	sample.Attributes = attributes
+
+	return *sample
+}
@@ -0,0 +1,21 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewEventSample(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2000, 1, 1, 0, 0, 3, 0, time.UTC)
+	sample := telemetry.NewEventSample("pause", start, end, map[string]string{"reason": "deploy"})
+
+	assert.Implements(t, (*telemetry.Sample)(nil), sample)
+	assert.Equal(t, "pause", sample.Name())
+	assert.Equal(t, 3*time.Second, sample.Duration())
+	assert.Equal(t, "deploy", sample.Attributes["reason"])
+	assert.Zero(t, sample.SentBytes())
+	assert.Zero(t, sample.ReceivedBytes())
+}
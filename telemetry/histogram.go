@@ -0,0 +1,159 @@
+package telemetry
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+const (
+	// Values are recorded with 3 significant digits: every power of 2 is split in 1024 linear sub-buckets
+	subBucketHalfCountMagnitude = 10
+	subBucketHalfCount          = 1 << subBucketHalfCountMagnitude
+	subBucketMask               = 2*subBucketHalfCount - 1
+)
+
+// Histogram records latencies in HDR-style log-linear buckets, storing only the used ones.
+// Two histograms are merged exactly, since the bucket layout does not depend on the recorded values.
+type Histogram struct {
+	Counts     map[int32]int64 `json:"counts"`
+	TotalCount int64           `json:"total_count"`
+	Sum        int64           `json:"sum"`
+	Min        time.Duration   `json:"min"`
+	Max        time.Duration   `json:"max"`
+}
+
+func NewHistogram() *Histogram {
+	output := new(Histogram)
+	output.Counts = make(map[int32]int64)
+
+	return output
+}
+
+func (h *Histogram) Record(value time.Duration) {
+	h.RecordN(value, 1)
+}
+
+func (h *Histogram) RecordN(value time.Duration, count int64) {
+	if count <= 0 {
+		return
+	}
+	if value < 0 {
+		value = 0
+	}
+
+	if h.Counts == nil {
+		h.Counts = make(map[int32]int64)
+	}
+
+	if h.TotalCount == 0 || value < h.Min {
+		h.Min = value
+	}
+	if value > h.Max {
+		h.Max = value
+	}
+
+	h.Counts[bucketIndex(int64(value))] += count
+	h.TotalCount += count
+	h.Sum += int64(value) * count
+}
+
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.TotalCount == 0 {
+		return
+	}
+
+	if h.Counts == nil {
+		h.Counts = make(map[int32]int64)
+	}
+
+	if h.TotalCount == 0 || other.Min < h.Min {
+		h.Min = other.Min
+	}
+	if other.Max > h.Max {
+		h.Max = other.Max
+	}
+
+	for index, count := range other.Counts {
+		h.Counts[index] += count
+	}
+	h.TotalCount += other.TotalCount
+	h.Sum += other.Sum
+}
+
+func (h *Histogram) Mean() time.Duration {
+	if h.TotalCount == 0 {
+		return 0
+	}
+
+	return time.Duration(h.Sum / h.TotalCount)
+}
+
+// Percentile returns the highest value equivalent to the one at the given percentile (0-100)
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	if h.TotalCount == 0 {
+		return 0
+	}
+
+	target := int64(math.Ceil(percentile / 100 * float64(h.TotalCount)))
+	if target < 1 {
+		target = 1
+	}
+
+	indexes := make([]int32, 0, len(h.Counts))
+	for index := range h.Counts {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(a, b int) bool {
+		return indexes[a] < indexes[b]
+	})
+
+	var cumulative int64
+	for _, index := range indexes {
+		cumulative += h.Counts[index]
+		if cumulative >= target {
+			return h.clamp(time.Duration(highestEquivalentValue(index)))
+		}
+	}
+
+	return h.Max
+}
+
+func (h *Histogram) clamp(value time.Duration) time.Duration {
+	if value < h.Min {
+		return h.Min
+	}
+	if value > h.Max {
+		return h.Max
+	}
+
+	return value
+}
+
+func bucketIndex(value int64) int32 {
+	bucket := 64 - bits.LeadingZeros64(uint64(value)|subBucketMask) - (subBucketHalfCountMagnitude + 1)
+	subBucket := value >> uint(bucket)
+
+	return int32(bucket*subBucketHalfCount) + int32(subBucket)
+}
+
+func lowestEquivalentValue(index int32) int64 {
+	bucket := int(index>>subBucketHalfCountMagnitude) - 1
+	subBucket := int64(index&(subBucketHalfCount-1)) + subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= subBucketHalfCount
+		bucket = 0
+	}
+
+	return subBucket << uint(bucket)
+}
+
+func highestEquivalentValue(index int32) int64 {
+	bucket := int(index>>subBucketHalfCountMagnitude) - 1
+	if bucket < 0 {
+		bucket = 0
+	}
+
+	return lowestEquivalentValue(index) + (int64(1) << uint(bucket)) - 1
+}
@@ -0,0 +1,90 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func assertWithinPrecision(t *testing.T, expected time.Duration, actual time.Duration) {
+	// 3 significant digits give a relative error lower than 0.1%
+	assert.InEpsilon(t, float64(expected), float64(actual), 0.001)
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	histogram := telemetry.NewHistogram()
+
+	assert.Equal(t, time.Duration(0), histogram.Percentile(99))
+	assert.Equal(t, time.Duration(0), histogram.Mean())
+}
+
+func TestHistogram_Percentiles(t *testing.T) {
+	histogram := telemetry.NewHistogram()
+	for value := 1; value <= 10000; value++ {
+		histogram.Record(time.Duration(value) * time.Microsecond)
+	}
+
+	assert.EqualValues(t, 10000, histogram.TotalCount)
+	assert.Equal(t, time.Microsecond, histogram.Min)
+	assert.Equal(t, 10*time.Millisecond, histogram.Max)
+	assert.Equal(t, 5000500*time.Nanosecond, histogram.Mean())
+
+	assertWithinPrecision(t, 5*time.Millisecond, histogram.Percentile(50))
+	assertWithinPrecision(t, 9*time.Millisecond, histogram.Percentile(90))
+	assertWithinPrecision(t, 9900*time.Microsecond, histogram.Percentile(99))
+	assertWithinPrecision(t, 9990*time.Microsecond, histogram.Percentile(99.9))
+	assert.Equal(t, 10*time.Millisecond, histogram.Percentile(100))
+	assert.Equal(t, time.Microsecond, histogram.Percentile(0))
+}
+
+func TestHistogram_SmallValuesAreExact(t *testing.T) {
+	histogram := telemetry.NewHistogram()
+	histogram.Record(3)
+	histogram.Record(1500)
+	histogram.RecordN(2047, 2)
+	histogram.Record(-5)
+
+	assert.Equal(t, time.Duration(0), histogram.Percentile(20))
+	assert.Equal(t, time.Duration(3), histogram.Percentile(40))
+	assert.Equal(t, time.Duration(1500), histogram.Percentile(60))
+	assert.Equal(t, time.Duration(2047), histogram.Percentile(100))
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	first := telemetry.NewHistogram()
+	second := telemetry.NewHistogram()
+	whole := telemetry.NewHistogram()
+
+	for value := 1; value <= 1000; value++ {
+		sample := time.Duration(value*value) * time.Microsecond
+		whole.Record(sample)
+		if value%3 == 0 {
+			first.Record(sample)
+		} else {
+			second.Record(sample)
+		}
+	}
+
+	first.Merge(second)
+	first.Merge(nil)
+	assert.Equal(t, whole, first)
+
+	empty := new(telemetry.Histogram)
+	empty.Merge(whole)
+	assert.Equal(t, whole.Percentile(95), empty.Percentile(95))
+}
+
+func TestHistogram_JSONRoundTrip(t *testing.T) {
+	histogram := telemetry.NewHistogram()
+	histogram.Record(250 * time.Millisecond)
+	histogram.Record(3 * time.Second)
+
+	encoded, err := json.Marshal(histogram)
+	if assert.NoError(t, err) {
+		decoded := new(telemetry.Histogram)
+		assert.NoError(t, json.Unmarshal(encoded, decoded))
+		assert.Equal(t, histogram, decoded)
+	}
+}
@@ -0,0 +1,20 @@
+package telemetry
+
+import "time"
+
+type MetricSample struct {
+	BaseSample
+	value float64
+}
+
+func NewMetricSample(name string, timestamp time.Time, value float64) MetricSample {
+	sample := new(MetricSample)
+	sample.BaseSample = NewBaseSample(name, timestamp, timestamp, 0, 0)
+	sample.value = value
+
+	return *sample
+}
+
+func (sample MetricSample) Value() float64 {
+	return sample.value
+}
@@ -0,0 +1,20 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewMetricSample(t *testing.T) {
+	timestamp := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := telemetry.NewMetricSample("dropped_iterations", timestamp, 3)
+
+	assert.Implements(t, (*telemetry.Sample)(nil), sample)
+	assert.Equal(t, "dropped_iterations", sample.Name())
+	assert.Equal(t, timestamp, sample.Start())
+	assert.Equal(t, timestamp, sample.End())
+	assert.Zero(t, sample.Duration())
+	assert.Equal(t, float64(3), sample.Value())
+}
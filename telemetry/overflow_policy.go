@@ -0,0 +1,18 @@
+package telemetry
+
+type OverflowPolicy string
+
+const (
+	// Block makes writers wait until the buffered samples are flushed
+	Block OverflowPolicy = "block"
+
+	// DropOldest discards the oldest buffered sample to make room for the new one
+	DropOldest OverflowPolicy = "drop_oldest"
+
+	// DropAndCount discards the new sample, keeping only the count of the dropped ones
+	DropAndCount OverflowPolicy = "drop_and_count"
+)
+
+func (policy OverflowPolicy) IsValid() bool {
+	return policy == Block || policy == DropOldest || policy == DropAndCount
+}
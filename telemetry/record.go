@@ -0,0 +1,125 @@
+package telemetry
+
+import (
+	"errors"
+	"time"
+)
+
+// Record is the serializable form of a sample, used to move samples between processes
+type Record struct {
+	Kind              RecordKind        `json:"kind"`
+	Source            string            `json:"source,omitempty"`
+	Name              string            `json:"name"`
+	Start             time.Time         `json:"start"`
+	End               time.Time         `json:"end"`
+	SentBytes         int64             `json:"sent_bytes,omitempty"`
+	ReceivedBytes     int64             `json:"received_bytes,omitempty"`
+	Value             float64           `json:"value,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty"`
+	Checks            []CheckResult     `json:"checks,omitempty"`
+	Success           bool              `json:"success,omitempty"`
+	Error             string            `json:"error,omitempty"`
+	ErrorClass        ErrorClass        `json:"error_class,omitempty"`
+	StatusCode        int               `json:"status_code,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	ThinkTime         time.Duration     `json:"think_time,omitempty"`
+	ThinkTimeExcluded bool              `json:"think_time_excluded,omitempty"`
+}
+
+func NewRecord(source string, sample Sample) Record {
+	output := Record{
+		Kind:          GenericRecord,
+		Source:        source,
+		Name:          sample.Name(),
+		Start:         sample.Start(),
+		End:           sample.End(),
+		SentBytes:     sample.SentBytes(),
+		ReceivedBytes: sample.ReceivedBytes(),
+		Error:         sample.ErrorMessage(),
+		ErrorClass:    sample.ErrorClass(),
+		StatusCode:    sample.StatusCode(),
+		Tags:          sample.Tags(),
+	}
+
+	if checkedSample, hasChecks := sample.(interface{ Checks() []CheckResult }); hasChecks {
+		output.Checks = checkedSample.Checks()
+	}
+
+	switch typedSample := sample.(type) {
+	case MetricSample:
+		output.Kind = MetricRecord
+		output.Value = typedSample.Value()
+
+	case EventSample:
+		output.Kind = EventRecord
+		output.Attributes = typedSample.Attributes
+
+	case TransactionSample:
+		output.Kind = TransactionRecord
+		output.Success = typedSample.Success
+		output.ThinkTime = typedSample.ThinkTime
+		output.ThinkTimeExcluded = typedSample.ThinkTimeExcluded
+	}
+
+	return output
+}
+
+// Failed tells whether the sampled operation failed, either by an explicit error or by a failed check
+func (r Record) Failed() bool {
+	if r.ErrorClass != "" {
+		return true
+	}
+
+	if r.Kind == TransactionRecord && (!r.Success || r.Error != "") {
+		return true
+	}
+
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sample rebuilds the sample from the record, transaction children are not included since they travel as records of their own
+func (r Record) Sample() Sample {
+	base := NewBaseSample(r.Name, r.Start, r.End, r.SentBytes, r.ReceivedBytes)
+	for _, check := range r.Checks {
+		base.AddCheck(check)
+	}
+	if r.ErrorClass != "" {
+		base.Fail(r.ErrorClass, r.Error)
+	}
+	base.SetStatusCode(r.StatusCode)
+	for key, value := range r.Tags {
+		base.SetTag(key, value)
+	}
+
+	switch r.Kind {
+	case MetricRecord:
+		sample := NewMetricSample(r.Name, r.Start, r.Value)
+		sample.BaseSample = base
+		return sample
+
+	case EventRecord:
+		sample := NewEventSample(r.Name, r.Start, r.End, r.Attributes)
+		sample.BaseSample = base
+		return sample
+
+	case TransactionRecord:
+		sample := NewTransactionSample(r.Name, r.Start, r.End, nil)
+		sample.BaseSample = base
+		sample.Success = r.Success
+		sample.ThinkTime = r.ThinkTime
+		sample.ThinkTimeExcluded = r.ThinkTimeExcluded
+		if r.Error != "" {
+			sample.Err = errors.New(r.Error)
+		}
+		return sample
+
+	default:
+		return base
+	}
+}
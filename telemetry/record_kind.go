@@ -0,0 +1,10 @@
+package telemetry
+
+type RecordKind string
+
+const (
+	GenericRecord     RecordKind = "sample"
+	MetricRecord      RecordKind = "metric"
+	EventRecord       RecordKind = "event"
+	TransactionRecord RecordKind = "transaction"
+)
@@ -0,0 +1,125 @@
+package telemetry_test
+
+import (
+	"errors"
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var recordStart = time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+func TestRecord_MetricRoundTrip(t *testing.T) {
+	record := telemetry.NewRecord("shooter-1", telemetry.NewMetricSample("dropped_iterations", recordStart, 3))
+
+	assert.Equal(t, telemetry.MetricRecord, record.Kind)
+	assert.Equal(t, "shooter-1", record.Source)
+
+	sample, isMetric := record.Sample().(telemetry.MetricSample)
+	if assert.True(t, isMetric) {
+		assert.Equal(t, "dropped_iterations", sample.Name())
+		assert.Equal(t, 3.0, sample.Value())
+	}
+}
+
+func TestRecord_EventRoundTrip(t *testing.T) {
+	event := telemetry.NewEventSample("pause", recordStart, recordStart.Add(time.Minute), map[string]string{"scope": "injector"})
+	sample, isEvent := telemetry.NewRecord("", event).Sample().(telemetry.EventSample)
+
+	if assert.True(t, isEvent) {
+		assert.Equal(t, time.Minute, sample.Duration())
+		assert.Equal(t, map[string]string{"scope": "injector"}, sample.Attributes)
+	}
+}
+
+func TestRecord_TransactionRoundTrip(t *testing.T) {
+	transaction := telemetry.NewTransactionSample("checkout", recordStart, recordStart.Add(3*time.Second), nil)
+	transaction.Success = false
+	transaction.Err = errors.New("payment refused")
+	transaction.ThinkTime = time.Second
+	transaction.ThinkTimeExcluded = true
+	transaction.AddCheck(telemetry.CheckResult{Name: "paid", Passed: false})
+
+	sample, isTransaction := telemetry.NewRecord("", transaction).Sample().(telemetry.TransactionSample)
+	if assert.True(t, isTransaction) {
+		assert.False(t, sample.Success)
+		assert.EqualError(t, sample.Err, "payment refused")
+		assert.Equal(t, 2*time.Second, sample.Duration())
+		assert.Equal(t, []telemetry.CheckResult{{Name: "paid", Passed: false}}, sample.Checks())
+	}
+}
+
+func TestRecord_GenericSample(t *testing.T) {
+	base := telemetry.NewBaseSample("GET /", recordStart, recordStart.Add(time.Millisecond), 100, 2000)
+	record := telemetry.NewRecord("", base)
+
+	assert.Equal(t, telemetry.GenericRecord, record.Kind)
+	assert.Equal(t, base, record.Sample())
+}
+
+func TestRecord_OutcomeRoundTrip(t *testing.T) {
+	base := telemetry.NewBaseSample("GET /cart", recordStart, recordStart.Add(time.Second), 100, 20)
+	base.SetStatusCode(503)
+	base.Fail(telemetry.ProtocolError, "503 Service Unavailable")
+	base.SetTag(telemetry.ScenarioTag, "checkout")
+
+	record := telemetry.NewRecord("shooter-1", base)
+	assert.True(t, record.Failed())
+	assert.Equal(t, telemetry.ProtocolError, record.ErrorClass)
+	assert.Equal(t, "503 Service Unavailable", record.Error)
+	assert.Equal(t, 503, record.StatusCode)
+	assert.Equal(t, map[string]string{telemetry.ScenarioTag: "checkout"}, record.Tags)
+
+	assert.Equal(t, base, record.Sample())
+}
+
+func TestBaseSample_Outcome(t *testing.T) {
+	base := telemetry.NewBaseSample("GET /", recordStart, recordStart, 0, 0)
+	assert.True(t, base.Succeeded())
+	assert.Empty(t, base.ErrorClass())
+
+	base.AddCheck(telemetry.CheckResult{Name: "status is 200", Passed: false})
+	assert.False(t, base.Succeeded())
+
+	other := telemetry.NewBaseSample("GET /", recordStart, recordStart, 0, 0)
+	other.Fail("", "something went wrong")
+	assert.False(t, other.Succeeded())
+	assert.Equal(t, telemetry.UnknownError, other.ErrorClass())
+}
+
+func TestTransactionSample_Outcome(t *testing.T) {
+	transaction := telemetry.NewTransactionSample("checkout", recordStart, recordStart.Add(time.Second), nil)
+	assert.True(t, transaction.Succeeded())
+
+	transaction.Err = errors.New("payment refused")
+	assert.False(t, transaction.Succeeded())
+	assert.Equal(t, "payment refused", transaction.ErrorMessage())
+}
+
+func TestBatch_EncodeDecode(t *testing.T) {
+	batch := telemetry.Batch{
+		Source:   "injector-1",
+		Sequence: 7,
+		Records: []telemetry.Record{
+			telemetry.NewRecord("shooter-1", telemetry.NewMetricSample("lag", recordStart, 0.5)),
+			telemetry.NewRecord("shooter-2", telemetry.NewBaseSample("GET /", recordStart, recordStart, 1, 2)),
+		},
+	}
+
+	data, err := batch.Encode()
+	assert.NoError(t, err)
+
+	decoded, err := telemetry.DecodeBatch(data)
+	assert.NoError(t, err)
+	assert.Equal(t, batch.Source, decoded.Source)
+	assert.Equal(t, batch.Sequence, decoded.Sequence)
+	assert.Len(t, decoded.Records, 2)
+	assert.Equal(t, 0.5, decoded.Records[0].Value)
+	assert.True(t, batch.Records[1].Start.Equal(decoded.Records[1].Start))
+}
+
+func TestDecodeBatch_InvalidData(t *testing.T) {
+	_, err := telemetry.DecodeBatch([]byte("not gzip"))
+	assert.Error(t, err)
+}
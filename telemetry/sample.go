@@ -9,4 +9,9 @@ type Sample interface {
 	Duration() time.Duration
 	SentBytes() int64
 	ReceivedBytes() int64
+	Succeeded() bool
+	ErrorClass() ErrorClass
+	ErrorMessage() string
+	StatusCode() int
+	Tags() map[string]string
 }
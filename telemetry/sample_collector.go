@@ -1,15 +1,170 @@
 package telemetry
 
+import "sync"
+
+const DefaultCollectorCapacity = 10000
+
+// SampleCollector buffers the samples of concurrent writers up to its capacity, then applies its overflow policy.
+// The zero value is ready to use, with the default capacity and the DropOldest policy.
 type SampleCollector struct {
-	samples []Sample
+	capacity  int
+	policy    OverflowPolicy
+	samples   []Sample
+	head      int
+	dropped   uint64
+	closed    bool
+	observers []func(sample Sample)
+	sinks     []Sink
+	flushed   *sync.Cond
+	mutex     sync.Mutex
+}
+
+func NewSampleCollector(capacity int, policy OverflowPolicy) *SampleCollector {
+	output := new(SampleCollector)
+	output.capacity = capacity
+	output.policy = policy
+
+	return output
 }
 
 func (collector *SampleCollector) Collect(sample Sample) {
+	collector.mutex.Lock()
+	observers := collector.observers
+	collector.mutex.Unlock()
+
+	for _, observer := range observers {
+		observer(sample)
+	}
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	for collector.buffered() >= collector.maxSamples() {
+		switch collector.policy {
+		case DropAndCount:
+			collector.dropped++
+			return
+
+		case Block:
+			if collector.closed {
+				collector.dropped++
+				return
+			}
+			collector.waitForFlush()
+
+		default:
+			collector.samples[collector.head] = nil
+			collector.head++
+			collector.dropped++
+		}
+	}
+
+	// The space of the discarded samples is reclaimed once it grows as large as the buffer
+	if collector.head > 0 && collector.head >= collector.buffered() {
+		collector.samples = append(collector.samples[:0], collector.samples[collector.head:]...)
+		collector.head = 0
+	}
+
 	collector.samples = append(collector.samples, sample)
 }
 
+// Write makes the collector a sink, so that collectors can be chained
+func (collector *SampleCollector) Write(samples []Sample) error {
+	for _, sample := range samples {
+		collector.Collect(sample)
+	}
+
+	return nil
+}
+
+func (collector *SampleCollector) AddObserver(observer func(sample Sample)) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.observers = append(collector.observers, observer)
+}
+
+func (collector *SampleCollector) AddSink(sink Sink) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.sinks = append(collector.sinks, sink)
+}
+
 func (collector *SampleCollector) Flush() []Sample {
-	output := collector.samples
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	output := collector.samples[collector.head:]
 	collector.samples = []Sample{}
+	collector.head = 0
+
+	if collector.flushed != nil {
+		collector.flushed.Broadcast()
+	}
 	return output
 }
+
+// Forward flushes the buffered samples to the sinks, returning the first error raised by them
+func (collector *SampleCollector) Forward() error {
+	collector.mutex.Lock()
+	sinks := MultiSink(collector.sinks)
+	collector.mutex.Unlock()
+
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	samples := collector.Flush()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	return sinks.Write(samples)
+}
+
+// Dropped returns how many samples have been discarded because the collector was full
+func (collector *SampleCollector) Dropped() uint64 {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	return collector.dropped
+}
+
+func (collector *SampleCollector) Buffered() int {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	return collector.buffered()
+}
+
+// Close releases the blocked writers, the samples collected afterwards are dropped when the collector is full
+func (collector *SampleCollector) Close() {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.closed = true
+	if collector.flushed != nil {
+		collector.flushed.Broadcast()
+	}
+}
+
+func (collector *SampleCollector) buffered() int {
+	return len(collector.samples) - collector.head
+}
+
+func (collector *SampleCollector) maxSamples() int {
+	if collector.capacity <= 0 {
+		return DefaultCollectorCapacity
+	}
+
+	return collector.capacity
+}
+
+func (collector *SampleCollector) waitForFlush() {
+	if collector.flushed == nil {
+		collector.flushed = sync.NewCond(&collector.mutex)
+	}
+
+	collector.flushed.Wait()
+}
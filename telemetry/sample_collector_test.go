@@ -1,9 +1,11 @@
 package telemetry_test
 
 import (
+	"errors"
 	"github.com/steromano87/harkonnen/telemetry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"sync"
 	"testing"
 	"time"
 )
@@ -12,33 +14,53 @@ type MockedSample struct {
 	mock.Mock
 }
 
-func (mocked MockedSample) Name() string {
+func (mocked *MockedSample) Name() string {
 	return "Mocked sample"
 }
 
-func (mocked MockedSample) Start() time.Time {
+func (mocked *MockedSample) Start() time.Time {
 	return time.Time{}
 }
 
-func (mocked MockedSample) End() time.Time {
+func (mocked *MockedSample) End() time.Time {
 	return time.Now()
 }
 
-func (mocked MockedSample) Duration() time.Duration {
+func (mocked *MockedSample) Duration() time.Duration {
 	return time.Now().Sub(time.Time{})
 }
 
-func (mocked MockedSample) SentBytes() int64 {
+func (mocked *MockedSample) SentBytes() int64 {
 	return int64(256)
 }
 
-func (mocked MockedSample) ReceivedBytes() int64 {
+func (mocked *MockedSample) ReceivedBytes() int64 {
 	return int64(2048)
 }
 
+func (mocked *MockedSample) Succeeded() bool {
+	return true
+}
+
+func (mocked *MockedSample) ErrorClass() telemetry.ErrorClass {
+	return ""
+}
+
+func (mocked *MockedSample) ErrorMessage() string {
+	return ""
+}
+
+func (mocked *MockedSample) StatusCode() int {
+	return 0
+}
+
+func (mocked *MockedSample) Tags() map[string]string {
+	return nil
+}
+
 func TestNewSampleCollector(t *testing.T) {
-	collector := telemetry.SampleCollector{}
-	assert.IsType(t, telemetry.SampleCollector{}, collector)
+	collector := new(telemetry.SampleCollector)
+	assert.IsType(t, &telemetry.SampleCollector{}, collector)
 
 	flushedSamples := collector.Flush()
 	assert.Empty(t, flushedSamples, "Flushing unused sample collector should give no collected samples")
@@ -46,7 +68,7 @@ func TestNewSampleCollector(t *testing.T) {
 
 func TestSampleCollector_CollectFlush(t *testing.T) {
 	collector := telemetry.SampleCollector{}
-	mockedSample := MockedSample{}
+	mockedSample := new(MockedSample)
 	collector.Collect(mockedSample)
 
 	flushedSamples := collector.Flush()
@@ -54,3 +76,146 @@ func TestSampleCollector_CollectFlush(t *testing.T) {
 		assert.Equal(t, mockedSample, flushedSamples[0])
 	}
 }
+
+func TestSampleCollector_AddObserver(t *testing.T) {
+	collector := telemetry.SampleCollector{}
+	var observedSamples []telemetry.Sample
+	collector.AddObserver(func(sample telemetry.Sample) {
+		observedSamples = append(observedSamples, sample)
+	})
+
+	sample := telemetry.NewBaseSample("observed", time.Time{}, time.Time{}, 0, 0)
+	collector.Collect(sample)
+
+	assert.Equal(t, []telemetry.Sample{sample}, observedSamples)
+	assert.Len(t, collector.Flush(), 1, "Observing samples should not prevent them from being collected")
+}
+
+func collectorSample(name string) telemetry.Sample {
+	return telemetry.NewBaseSample(name, time.Time{}, time.Time{}, 0, 0)
+}
+
+func sampleNames(samples []telemetry.Sample) []string {
+	names := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		names = append(names, sample.Name())
+	}
+
+	return names
+}
+
+func TestSampleCollector_ConcurrentCollect(t *testing.T) {
+	collector := telemetry.NewSampleCollector(0, telemetry.DropOldest)
+
+	var waitGroup sync.WaitGroup
+	for writer := 0; writer < 10; writer++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := 0; index < 100; index++ {
+				collector.Collect(collectorSample("concurrent"))
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	assert.Len(t, collector.Flush(), 1000)
+	assert.Equal(t, uint64(0), collector.Dropped())
+}
+
+func TestSampleCollector_DropOldest(t *testing.T) {
+	collector := telemetry.NewSampleCollector(3, telemetry.DropOldest)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		collector.Collect(collectorSample(name))
+	}
+
+	assert.Equal(t, 3, collector.Buffered())
+	assert.Equal(t, uint64(4), collector.Dropped())
+	assert.Equal(t, []string{"e", "f", "g"}, sampleNames(collector.Flush()))
+	assert.Empty(t, collector.Flush())
+}
+
+func TestSampleCollector_DropAndCount(t *testing.T) {
+	collector := telemetry.NewSampleCollector(3, telemetry.DropAndCount)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		collector.Collect(collectorSample(name))
+	}
+
+	assert.Equal(t, uint64(2), collector.Dropped())
+	assert.Equal(t, []string{"a", "b", "c"}, sampleNames(collector.Flush()))
+
+	collector.Collect(collectorSample("f"))
+	assert.Equal(t, []string{"f"}, sampleNames(collector.Flush()))
+}
+
+func TestSampleCollector_Block(t *testing.T) {
+	collector := telemetry.NewSampleCollector(2, telemetry.Block)
+	collector.Collect(collectorSample("a"))
+	collector.Collect(collectorSample("b"))
+
+	collected := make(chan struct{})
+	go func() {
+		collector.Collect(collectorSample("c"))
+		close(collected)
+	}()
+
+	select {
+	case <-collected:
+		assert.Fail(t, "Writer not blocked by a full collector")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, []string{"a", "b"}, sampleNames(collector.Flush()))
+	<-collected
+	assert.Equal(t, []string{"c"}, sampleNames(collector.Flush()))
+	assert.Equal(t, uint64(0), collector.Dropped())
+}
+
+func TestSampleCollector_CloseReleasesBlockedWriters(t *testing.T) {
+	collector := telemetry.NewSampleCollector(1, telemetry.Block)
+	collector.Collect(collectorSample("a"))
+
+	collected := make(chan struct{})
+	go func() {
+		collector.Collect(collectorSample("b"))
+		close(collected)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	collector.Close()
+	<-collected
+
+	assert.Equal(t, uint64(1), collector.Dropped())
+	assert.Equal(t, []string{"a"}, sampleNames(collector.Flush()))
+}
+
+func TestSampleCollector_Forward(t *testing.T) {
+	collector := telemetry.NewSampleCollector(10, telemetry.DropOldest)
+	collector.Collect(collectorSample("a"))
+
+	// Without sinks, the samples stay in the collector
+	assert.NoError(t, collector.Forward())
+	assert.Equal(t, 1, collector.Buffered())
+
+	var exported []telemetry.Sample
+	downstream := new(telemetry.SampleCollector)
+	collector.AddSink(telemetry.SinkFunc(func(samples []telemetry.Sample) error {
+		exported = append(exported, samples...)
+		return errors.New("exporter unavailable")
+	}))
+	collector.AddSink(downstream)
+
+	collector.Collect(collectorSample("b"))
+	assert.EqualError(t, collector.Forward(), "exporter unavailable")
+
+	assert.Equal(t, []string{"a", "b"}, sampleNames(exported))
+	assert.Equal(t, []string{"a", "b"}, sampleNames(downstream.Flush()))
+	assert.Equal(t, 0, collector.Buffered())
+}
+
+func TestOverflowPolicy_IsValid(t *testing.T) {
+	assert.True(t, telemetry.Block.IsValid())
+	assert.True(t, telemetry.DropOldest.IsValid())
+	assert.True(t, telemetry.DropAndCount.IsValid())
+	assert.False(t, telemetry.OverflowPolicy("spill").IsValid())
+}
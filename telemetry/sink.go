@@ -0,0 +1,26 @@
+package telemetry
+
+// Sink receives the samples flushed by a collector, e.g. to export them or to forward them to another collector
+type Sink interface {
+	Write(samples []Sample) error
+}
+
+type SinkFunc func(samples []Sample) error
+
+func (function SinkFunc) Write(samples []Sample) error {
+	return function(samples)
+}
+
+// MultiSink chains sinks, every sink receives all the samples even if a previous one failed
+type MultiSink []Sink
+
+func (sinks MultiSink) Write(samples []Sample) error {
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Write(samples); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
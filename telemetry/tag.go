@@ -0,0 +1,16 @@
+package telemetry
+
+const (
+	ScenarioTag    = "scenario"
+	ShooterTag     = "shooter"
+	InjectorTag    = "injector"
+	IterationTag   = "iteration"
+	TransactionTag = "transaction"
+)
+
+// Tags with a value for every shooter or iteration are left out of the aggregation keys, to keep their number bounded
+var unaggregatedTags = map[string]bool{
+	ShooterTag:   true,
+	InjectorTag:  true,
+	IterationTag: true,
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import "time"
+
+type TransactionSample struct {
+	BaseSample
+	Success           bool
+	Err               error
+	Children          []Sample
+	ThinkTime         time.Duration
+	ThinkTimeExcluded bool
+}
+
+func NewTransactionSample(name string, start time.Time, end time.Time, children []Sample) TransactionSample {
+	sentBytes := int64(0)
+	receivedBytes := int64(0)
+	for _, child := range children {
+		sentBytes += child.SentBytes()
+		receivedBytes += child.ReceivedBytes()
+	}
+
+	sample := new(TransactionSample)
+	sample.BaseSample = NewBaseSample(name, start, end, sentBytes, receivedBytes)
+	sample.Children = children
+	sample.Success = true
+
+	return *sample
+}
+
+func (sample TransactionSample) Succeeded() bool {
+	return sample.Success && sample.Err == nil && sample.BaseSample.Succeeded()
+}
+
+func (sample TransactionSample) ErrorMessage() string {
+	if sample.BaseSample.ErrorMessage() == "" && sample.Err != nil {
+		return sample.Err.Error()
+	}
+
+	return sample.BaseSample.ErrorMessage()
+}
+
+func (sample TransactionSample) Duration() time.Duration {
+	if sample.ThinkTimeExcluded {
+		return sample.BaseSample.Duration() - sample.ThinkTime
+	}
+
+	return sample.BaseSample.Duration()
+}
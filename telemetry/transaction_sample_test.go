@@ -0,0 +1,36 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var transactionStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+var transactionEnd = time.Date(2000, 1, 1, 0, 0, 5, 0, time.UTC)
+
+func TestNewTransactionSample(t *testing.T) {
+	children := []telemetry.Sample{
+		telemetry.NewBaseSample("first", transactionStart, transactionEnd, 10, 100),
+		telemetry.NewBaseSample("second", transactionStart, transactionEnd, 20, 200),
+	}
+	sample := telemetry.NewTransactionSample("Login", transactionStart, transactionEnd, children)
+
+	assert.Implements(t, (*telemetry.Sample)(nil), sample)
+	assert.Equal(t, "Login", sample.Name())
+	assert.True(t, sample.Success)
+	assert.Len(t, sample.Children, 2)
+	assert.EqualValues(t, 30, sample.SentBytes())
+	assert.EqualValues(t, 300, sample.ReceivedBytes())
+}
+
+func TestTransactionSample_Duration(t *testing.T) {
+	sample := telemetry.NewTransactionSample("Login", transactionStart, transactionEnd, nil)
+	sample.ThinkTime = 2 * time.Second
+
+	assert.Equal(t, 5*time.Second, sample.Duration())
+
+	sample.ThinkTimeExcluded = true
+	assert.Equal(t, 3*time.Second, sample.Duration())
+}
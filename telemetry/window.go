@@ -0,0 +1,117 @@
+package telemetry
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type windowEntry struct {
+	end     time.Time
+	elapsed time.Duration
+	failed  bool
+}
+
+// Window aggregates the records of the latest time span, giving a live view of throughput, latency and errors
+type Window struct {
+	span    time.Duration
+	kinds   map[RecordKind]bool
+	entries []windowEntry
+	mutex   sync.Mutex
+}
+
+type WindowSummary struct {
+	Count      int
+	Failures   int
+	Throughput float64
+	ErrorRate  float64
+	Latency    time.Duration
+}
+
+// NewWindow creates a window over the given kinds of records, plain samples (e.g. HTTP requests) by default
+func NewWindow(span time.Duration, kinds ...RecordKind) *Window {
+	output := new(Window)
+	output.span = span
+	output.kinds = make(map[RecordKind]bool)
+
+	if len(kinds) == 0 {
+		kinds = []RecordKind{GenericRecord}
+	}
+	for _, kind := range kinds {
+		output.kinds[kind] = true
+	}
+
+	return output
+}
+
+func (w *Window) Add(records ...Record) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, record := range records {
+		if !w.kinds[record.Kind] {
+			continue
+		}
+
+		w.entries = append(w.entries, windowEntry{
+			end:     record.End,
+			elapsed: record.End.Sub(record.Start),
+			failed:  record.Failed(),
+		})
+	}
+}
+
+// Summarize aggregates the records ended in the span before now, the latency is the requested percentile (0-100)
+func (w *Window) Summarize(now time.Time, percentile float64) WindowSummary {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	threshold := now.Add(-w.span)
+	retained := w.entries[:0]
+	var durations []time.Duration
+	output := WindowSummary{}
+
+	for _, entry := range w.entries {
+		if entry.end.Before(threshold) {
+			continue
+		}
+		retained = append(retained, entry)
+
+		if entry.end.After(now) {
+			continue
+		}
+
+		output.Count++
+		durations = append(durations, entry.elapsed)
+		if entry.failed {
+			output.Failures++
+		}
+	}
+	w.entries = retained
+
+	if output.Count == 0 {
+		return output
+	}
+
+	output.Throughput = float64(output.Count) / w.span.Seconds()
+	output.ErrorRate = float64(output.Failures) / float64(output.Count)
+	output.Latency = nearestRank(durations, percentile)
+	return output
+}
+
+func nearestRank(durations []time.Duration, percentile float64) time.Duration {
+	sort.Slice(durations, func(a, b int) bool {
+		return durations[a] < durations[b]
+	})
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(durations) {
+		rank = len(durations)
+	}
+
+	return durations[rank-1]
+}
@@ -0,0 +1,65 @@
+package telemetry_test
+
+import (
+	"github.com/steromano87/harkonnen/telemetry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func windowRecord(end time.Time, elapsed time.Duration) telemetry.Record {
+	return telemetry.NewRecord("shooter-1", telemetry.NewBaseSample("GET /", end.Add(-elapsed), end, 0, 0))
+}
+
+func TestWindow_Summarize(t *testing.T) {
+	window := telemetry.NewWindow(10 * time.Second)
+	now := recordStart.Add(time.Minute)
+
+	for index := 1; index <= 10; index++ {
+		window.Add(windowRecord(now.Add(-time.Duration(index)*time.Second/2), time.Duration(index)*100*time.Millisecond))
+	}
+
+	summary := window.Summarize(now, 90)
+	assert.Equal(t, 10, summary.Count)
+	assert.Equal(t, 0, summary.Failures)
+	assert.Equal(t, 1.0, summary.Throughput)
+	assert.Equal(t, 0.0, summary.ErrorRate)
+	assert.Equal(t, 900*time.Millisecond, summary.Latency)
+
+	assert.Equal(t, time.Second, window.Summarize(now, 100).Latency)
+	assert.Equal(t, 100*time.Millisecond, window.Summarize(now, 0).Latency)
+}
+
+func TestWindow_EvictsOldRecords(t *testing.T) {
+	window := telemetry.NewWindow(10 * time.Second)
+	now := recordStart.Add(time.Minute)
+
+	window.Add(windowRecord(now.Add(-time.Minute), time.Second))
+	window.Add(windowRecord(now.Add(-time.Second), time.Second))
+
+	assert.Equal(t, 1, window.Summarize(now, 95).Count)
+	assert.Equal(t, 0, window.Summarize(now.Add(time.Minute), 95).Count)
+	assert.Equal(t, telemetry.WindowSummary{}, window.Summarize(now, 95))
+}
+
+func TestWindow_Failures(t *testing.T) {
+	window := telemetry.NewWindow(10*time.Second, telemetry.GenericRecord, telemetry.TransactionRecord)
+	now := recordStart.Add(time.Minute)
+
+	failed := telemetry.NewTransactionSample("checkout", now.Add(-time.Second), now, nil)
+	failed.Success = false
+	succeeded := telemetry.NewTransactionSample("checkout", now.Add(-time.Second), now, nil)
+	succeeded.Success = true
+
+	window.Add(
+		telemetry.NewRecord("", failed),
+		telemetry.NewRecord("", succeeded),
+		telemetry.NewRecord("", telemetry.NewMetricSample("lag", now, 1)),
+		windowRecord(now, time.Second),
+	)
+
+	summary := window.Summarize(now, 95)
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, 1, summary.Failures)
+	assert.InDelta(t, 1.0/3, summary.ErrorRate, 0.0001)
+}